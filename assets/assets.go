@@ -0,0 +1,10 @@
+// Package assets holds the game data shipped inside the binary.
+package assets
+
+import "embed"
+
+// Data holds the definitions under assets/data (spells, items, monsters,
+// blueprints), so the game finds them wherever it is installed.
+//
+//go:embed data/*.json
+var Data embed.FS
@@ -5,8 +5,8 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log/slog"
-	"math/rand"
 	"os"
 	"os/signal"
 	"syscall"
@@ -19,16 +19,22 @@ import (
 )
 
 func main() {
+	seedFlag := flag.Int64("seed", 0, "seed for a reproducible run (0 picks one from the clock)")
+	flag.Parse()
+
 	config.Init()
 	ui.InitializeSDL()
 
 	slog.Info("Starting roguelike game", "debug_mode", config.Config.Advanced.DebugMode)
 
-	seed := time.Now().UnixNano()
-	rand.New(rand.NewSource(seed))
+	seed := *seedFlag
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	slog.Info("Using random seed", "seed", seed)
 
 	gd := gruid.NewGrid(config.Config.Gameplay.DungeonWidth, config.Config.Gameplay.DungeonHeight)
-	m := game.NewModel(gd)
+	m := game.NewModel(gd, seed)
 
 	driver := ui.GetDriver()
 	app := gruid.NewApp(gruid.AppConfig{
@@ -17,7 +17,7 @@ const (
 
 	// Stats panel (top-right)
 	StatsPanelWidth  = 20
-	StatsPanelHeight = 12
+	StatsPanelHeight = 16
 	StatsPanelX      = 60
 	StatsPanelY      = 0
 
@@ -5,6 +5,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/io"
 )
@@ -39,8 +40,21 @@ type GameplayConfig struct {
 	MaxMonstersPerRoom int `json:"max_monsters_per_room"`
 	DungeonWidth       int `json:"dungeon_width"`
 	DungeonHeight      int `json:"dungeon_height"`
+
+	// MapGenerators selects the level generator per depth range
+	MapGenerators []MapGeneratorRule `json:"map_generators"`
+}
+
+// MapGeneratorRule selects a map generator for a range of dungeon depths
+type MapGeneratorRule struct {
+	MinDepth  int    `json:"min_depth"`
+	MaxDepth  int    `json:"max_depth"` // 0 = no upper bound
+	Generator string `json:"generator"` // "rooms", "bsp", "cave", "drunkard", "maze"
 }
 
+// MapGeneratorNames lists the generator names accepted in MapGeneratorRule
+var MapGeneratorNames = []string{"rooms", "bsp", "cave", "drunkard", "maze"}
+
 // DisplayConfig holds display-related settings
 type DisplayConfig struct {
 	// Window settings
@@ -159,6 +173,13 @@ func DefaultConfig() FullConfig {
 			MaxMonstersPerRoom:      2,
 			DungeonWidth:            80,
 			DungeonHeight:           24,
+			MapGenerators: []MapGeneratorRule{
+				{MinDepth: 1, MaxDepth: 2, Generator: "rooms"},
+				{MinDepth: 3, MaxDepth: 4, Generator: "bsp"},
+				{MinDepth: 5, MaxDepth: 6, Generator: "cave"},
+				{MinDepth: 7, MaxDepth: 8, Generator: "drunkard"},
+				{MinDepth: 9, MaxDepth: 0, Generator: "maze"},
+			},
 		},
 		Display: DisplayConfig{
 			WindowWidth:    1280, // 80 chars * 16 pixels = 1280
@@ -290,6 +311,9 @@ func mergeWithDefaults(config FullConfig) FullConfig {
 	if config.Gameplay.DungeonHeight == 0 {
 		config.Gameplay.DungeonHeight = defaults.Gameplay.DungeonHeight
 	}
+	if len(config.Gameplay.MapGenerators) == 0 {
+		config.Gameplay.MapGenerators = defaults.Gameplay.MapGenerators
+	}
 
 	// Merge missing display fields
 	if config.Display.TilesetPath == "" {
@@ -315,6 +339,15 @@ func ValidateConfig(config *FullConfig) error {
 		return fmt.Errorf("FOV radius must be between 1 and 20")
 	}
 
+	for _, rule := range config.Gameplay.MapGenerators {
+		if rule.MinDepth < 1 || (rule.MaxDepth != 0 && rule.MaxDepth < rule.MinDepth) {
+			return fmt.Errorf("map generator %q has an invalid depth range %d-%d", rule.Generator, rule.MinDepth, rule.MaxDepth)
+		}
+		if !slices.Contains(MapGeneratorNames, rule.Generator) {
+			return fmt.Errorf("unknown map generator %q", rule.Generator)
+		}
+	}
+
 	// Validate display settings
 	if config.Display.ScaleFactorX < 0.1 || config.Display.ScaleFactorX > 5.0 {
 		return fmt.Errorf("scale factor X must be between 0.1 and 5.0")
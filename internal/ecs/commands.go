@@ -0,0 +1,125 @@
+package ecs
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// CommandBuffer records structural changes, i.e. spawning and despawning
+// entities and adding or removing components, and applies them later at a
+// sync point. Code walking query results records its changes here, so the
+// entity sets and component pointers it iterates over stay valid.
+//
+// Commands are applied in the order they were recorded. Commands aimed at
+// entities that no longer exist by then are skipped.
+type CommandBuffer struct {
+	ecs      *ECS
+	mu       sync.Mutex
+	commands []command
+}
+
+// commandKind tells what a recorded command does
+type commandKind uint8
+
+const (
+	cmdSpawn commandKind = iota
+	cmdDespawn
+	cmdAdd
+	cmdRemove
+)
+
+// command is one recorded structural change
+type command struct {
+	kind      commandKind
+	id        EntityID
+	compType  components.ComponentType // For cmdAdd and cmdRemove
+	component any                      // For cmdAdd
+	comps     []any                    // For cmdSpawn
+}
+
+// NewCommandBuffer creates an empty buffer applying its commands to ecs.
+func NewCommandBuffer(ecs *ECS) *CommandBuffer {
+	return &CommandBuffer{ecs: ecs}
+}
+
+// Spawn records the creation of an entity with the given components. Its ID
+// is returned right away so further commands can target it, but the entity
+// only exists once the buffer is applied; until then the ID is reserved.
+func (cb *CommandBuffer) Spawn(comps ...any) EntityID {
+	cb.ecs.mu.Lock()
+	id := cb.ecs.allocateSlot(slotReserved)
+	cb.ecs.mu.Unlock()
+
+	cb.record(command{kind: cmdSpawn, id: id, comps: comps})
+	return id
+}
+
+// Despawn records the removal of an entity.
+func (cb *CommandBuffer) Despawn(id EntityID) {
+	cb.record(command{kind: cmdDespawn, id: id})
+}
+
+// AddComponent records adding or replacing a component.
+func (cb *CommandBuffer) AddComponent(id EntityID, compType components.ComponentType, component any) {
+	cb.record(command{kind: cmdAdd, id: id, compType: compType, component: component})
+}
+
+// RemoveComponents records removing components from an entity.
+func (cb *CommandBuffer) RemoveComponents(id EntityID, compTypes ...components.ComponentType) {
+	for _, compType := range compTypes {
+		cb.record(command{kind: cmdRemove, id: id, compType: compType})
+	}
+}
+
+// Len returns the number of commands waiting to be applied.
+func (cb *CommandBuffer) Len() int {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return len(cb.commands)
+}
+
+// Apply runs the recorded commands in order and empties the buffer.
+// Commands recorded while applying, e.g. by component hooks, are applied in
+// the same call.
+func (cb *CommandBuffer) Apply() {
+	for {
+		cb.mu.Lock()
+		commands := cb.commands
+		cb.commands = nil
+		cb.mu.Unlock()
+
+		if len(commands) == 0 {
+			return
+		}
+		for _, cmd := range commands {
+			cb.apply(cmd)
+		}
+	}
+}
+
+// record appends a command
+func (cb *CommandBuffer) record(cmd command) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.commands = append(cb.commands, cmd)
+}
+
+// apply runs a single command against the ECS
+func (cb *CommandBuffer) apply(cmd command) {
+	switch cmd.kind {
+	case cmdSpawn:
+		if err := cb.ecs.AddEntityWithID(cmd.id); err != nil {
+			slog.Warn("Failed to spawn buffered entity", "entityId", cmd.id, "error", err)
+			return
+		}
+		cb.ecs.AddComponents(cmd.id, cmd.comps...)
+	case cmdDespawn:
+		cb.ecs.RemoveEntity(cmd.id)
+	case cmdAdd:
+		cb.ecs.AddComponent(cmd.id, cmd.compType, cmd.component)
+	case cmdRemove:
+		cb.ecs.RemoveComponent(cmd.id, cmd.compType)
+	}
+}
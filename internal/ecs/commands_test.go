@@ -0,0 +1,120 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// newCommandWorld creates n entities with a position and health
+func newCommandWorld(n int) (*ECS, []EntityID) {
+	world := NewECS()
+	ids := make([]EntityID, n)
+	for i := range ids {
+		ids[i] = world.AddEntity()
+		world.AddComponents(ids[i], gruid.Point{X: i}, components.NewHealth(10))
+	}
+	return world, ids
+}
+
+func TestCommandBuffer_IterationSeesStableWorld(t *testing.T) {
+	world, ids := newCommandWorld(6)
+	cb := NewCommandBuffer(world)
+
+	// Every visited entity despawns its successor, loses its health and
+	// spawns a replacement; none of it may show during the pass
+	var visited []EntityID
+	var spawned []EntityID
+	NewQuery2[gruid.Point, components.Health](world, components.CPosition, components.CHealth).
+		Each(func(id EntityID, pos *gruid.Point, health *components.Health) {
+			visited = append(visited, id)
+			health.CurrentHP-- // Pointers stay valid while commands pile up
+			if i := slices.Index(ids, id); i+1 < len(ids) {
+				cb.Despawn(ids[i+1])
+			}
+			cb.RemoveComponents(id, components.CHealth)
+			spawned = append(spawned, cb.Spawn(gruid.Point{X: pos.X, Y: 1}, components.NewHealth(5)))
+		})
+
+	slices.Sort(visited)
+	if !slices.Equal(visited, ids) {
+		t.Fatalf("Expected every original entity visited once, got %v", visited)
+	}
+	for _, id := range spawned {
+		if world.EntityExists(id) {
+			t.Errorf("Spawned entity %d exists before the buffer is applied", id)
+		}
+	}
+	if hp := world.GetHealthSafe(ids[0]).CurrentHP; hp != 9 {
+		t.Errorf("Expected in-place mutation to persist during the pass, got %d HP", hp)
+	}
+
+	cb.Apply()
+
+	if cb.Len() != 0 {
+		t.Errorf("Expected an empty buffer after applying, got %d commands", cb.Len())
+	}
+	if !world.EntityExists(ids[0]) || world.HasComponent(ids[0], components.CHealth) {
+		t.Error("Expected the first entity to survive without health")
+	}
+	for _, id := range ids[1:] {
+		if world.EntityExists(id) {
+			t.Errorf("Expected entity %d despawned", id)
+		}
+	}
+	for _, id := range spawned {
+		if pos, ok := world.GetPosition(id); !ok || pos.Y != 1 {
+			t.Errorf("Expected spawned entity %d positioned, got %v, %v", id, pos, ok)
+		}
+	}
+}
+
+func TestCommandBuffer_SpawnReservesID(t *testing.T) {
+	world := NewECS()
+	cb := NewCommandBuffer(world)
+
+	pending := cb.Spawn(components.Name{Name: "pending"})
+	cb.AddComponent(pending, components.CPosition, gruid.Point{X: 4, Y: 4})
+	if other := world.AddEntity(); other.Index() == pending.Index() {
+		t.Fatal("Expected the pending spawn's index to stay reserved")
+	}
+	if got := world.GetAllEntities(); slices.Contains(got, pending) {
+		t.Errorf("Expected the pending spawn hidden from queries, got %v", got)
+	}
+
+	cb.Apply()
+	if name, ok := world.GetName(pending); !ok || name != "pending" {
+		t.Errorf("Expected the spawned name, got %q, %v", name, ok)
+	}
+	if pos, ok := world.GetPosition(pending); !ok || pos != (gruid.Point{X: 4, Y: 4}) {
+		t.Errorf("Expected the later command applied to the spawn, got %v, %v", pos, ok)
+	}
+}
+
+func TestCommandBuffer_SkipsStaleAndAppliesHookCommands(t *testing.T) {
+	world, ids := newCommandWorld(2)
+	cb := NewCommandBuffer(world)
+
+	// A hook deferring its own change gets it applied in the same call
+	world.OnRemove(components.CHealth, func(id EntityID, _ any) {
+		cb.AddComponent(id, components.CCorpseTag, components.CorpseTag{})
+	})
+
+	cb.Despawn(ids[0])
+	cb.AddComponent(ids[0], components.CName, components.Name{Name: "ghost"})
+	cb.RemoveComponents(ids[1], components.CHealth)
+	cb.Apply()
+
+	reused := world.AddEntity()
+	if reused.Index() != ids[0].Index() {
+		t.Fatalf("Expected the despawned index reused, got %d", reused)
+	}
+	if world.HasComponent(reused, components.CName) {
+		t.Error("Expected the command on the despawned entity skipped")
+	}
+	if !world.HasComponent(ids[1], components.CCorpseTag) {
+		t.Error("Expected the hook's deferred command applied")
+	}
+}
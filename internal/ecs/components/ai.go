@@ -128,3 +128,16 @@ func manhattanDistance(a, b gruid.Point) int {
 	}
 	return dx + dy
 }
+
+// Reward component records what a creature yields to whoever kills it
+type Reward struct {
+	XP   int
+	Loot []LootDrop
+}
+
+// LootDrop is a chance for a dying creature to leave an item behind
+type LootDrop struct {
+	Item     string `json:"item"`     // Item name, as defined in the item data
+	Chance   int    `json:"chance"`   // Percent chance to drop
+	Quantity int    `json:"quantity"` // How many drop, at least 1
+}
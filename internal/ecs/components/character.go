@@ -1,5 +1,7 @@
 package components
 
+import "slices"
+
 // Stats represents character attributes
 type Stats struct {
 	Strength     int // Affects melee damage and carrying capacity
@@ -226,6 +228,99 @@ type StatusEffect struct {
 	Regenerating bool
 	Paralyzed    bool
 	Confused     bool
+
+	DetectMonsters int // Radius within which monsters are sensed through walls
+	SpeedMod       int // Percent change in speed: positive hastes, negative slows
+
+	// Intensity is the HP lost (poison) or regained (regeneration) per turn
+	Intensity    int
+	Stacking     StackingRule // How reapplying the effect combines with an active one
+	MaxIntensity int          // Cap for StackIntensity, 0 for no cap
+}
+
+// StackingRule decides what happens when an effect is applied while an
+// effect of the same name is already active
+type StackingRule string
+
+const (
+	StackRefresh   StackingRule = "refresh" // Reset the duration (default)
+	StackIntensity StackingRule = "stack"   // Add the intensities and reset the duration
+	StackMax       StackingRule = "max"     // Keep the stronger intensity and the longer duration
+)
+
+// NewPoisonEffect creates a poison dealing damage per turn. Doses stack.
+func NewPoisonEffect(damage, duration int) StatusEffect {
+	return StatusEffect{
+		Name:         "Poison",
+		Duration:     duration,
+		Type:         "debuff",
+		Description:  "Losing health every turn",
+		Poisoned:     true,
+		Intensity:    damage,
+		Stacking:     StackIntensity,
+		MaxIntensity: 5 * damage,
+	}
+}
+
+// NewRegenerationEffect creates a regeneration healing per turn
+func NewRegenerationEffect(healing, duration int) StatusEffect {
+	return StatusEffect{
+		Name:         "Regeneration",
+		Duration:     duration,
+		Type:         "buff",
+		Description:  "Recovering health every turn",
+		Regenerating: true,
+		Intensity:    healing,
+		Stacking:     StackMax,
+	}
+}
+
+// NewParalysisEffect creates a paralysis skipping the affected turns
+func NewParalysisEffect(duration int) StatusEffect {
+	return StatusEffect{
+		Name:        "Paralysis",
+		Duration:    duration,
+		Type:        "debuff",
+		Description: "Unable to act",
+		Paralyzed:   true,
+		Stacking:    StackRefresh,
+	}
+}
+
+// NewConfusionEffect creates a confusion making movement erratic
+func NewConfusionEffect(duration int) StatusEffect {
+	return StatusEffect{
+		Name:        "Confusion",
+		Duration:    duration,
+		Type:        "debuff",
+		Description: "Stumbling in random directions",
+		Confused:    true,
+		Stacking:    StackRefresh,
+	}
+}
+
+// NewHasteEffect creates a haste doubling the speed of actions
+func NewHasteEffect(duration int) StatusEffect {
+	return StatusEffect{
+		Name:        "Haste",
+		Duration:    duration,
+		Type:        "buff",
+		Description: "Moving twice as fast",
+		SpeedMod:    100,
+		Stacking:    StackMax,
+	}
+}
+
+// NewSlowEffect creates a slow halving the speed of actions
+func NewSlowEffect(duration int) StatusEffect {
+	return StatusEffect{
+		Name:        "Slow",
+		Duration:    duration,
+		Type:        "debuff",
+		Description: "Moving at half speed",
+		SpeedMod:    -50,
+		Stacking:    StackRefresh,
+	}
 }
 
 // StatusEffects component holds all active status effects
@@ -240,14 +335,29 @@ func NewStatusEffects() StatusEffects {
 	}
 }
 
-// AddEffect adds a new status effect
+// AddEffect adds a new status effect. If an effect with the same name is
+// already active, the new effect's stacking rule decides how they combine.
 func (se *StatusEffects) AddEffect(effect StatusEffect) {
-	// Check if effect already exists and refresh duration
 	for i := range se.Effects {
-		if se.Effects[i].Name == effect.Name {
-			se.Effects[i].Duration = effect.Duration
-			return
+		active := &se.Effects[i]
+		if active.Name != effect.Name {
+			continue
+		}
+
+		switch effect.Stacking {
+		case StackIntensity:
+			active.Intensity += effect.Intensity
+			if effect.MaxIntensity > 0 {
+				active.Intensity = min(active.Intensity, effect.MaxIntensity)
+			}
+			active.Duration = effect.Duration
+		case StackMax:
+			active.Intensity = max(active.Intensity, effect.Intensity)
+			active.Duration = max(active.Duration, effect.Duration)
+		default: // StackRefresh
+			active.Duration = effect.Duration
 		}
+		return
 	}
 
 	// Add new effect
@@ -282,6 +392,39 @@ func (se *StatusEffects) UpdateEffects() []StatusEffect {
 	return expired
 }
 
+// TickTotals returns the HP lost to poison and regained from regeneration
+// this turn across all active effects
+func (se *StatusEffects) TickTotals() (damage, healing int) {
+	for _, effect := range se.Effects {
+		if effect.Poisoned {
+			damage += effect.Intensity
+		}
+		if effect.Regenerating {
+			healing += effect.Intensity
+		}
+	}
+	return damage, healing
+}
+
+// IsParalyzed reports whether any active effect prevents acting
+func (se *StatusEffects) IsParalyzed() bool {
+	return slices.ContainsFunc(se.Effects, func(e StatusEffect) bool { return e.Paralyzed })
+}
+
+// IsConfused reports whether any active effect scrambles movement
+func (se *StatusEffects) IsConfused() bool {
+	return slices.ContainsFunc(se.Effects, func(e StatusEffect) bool { return e.Confused })
+}
+
+// SpeedModifier returns the summed percent change in speed of all effects
+func (se *StatusEffects) SpeedModifier() int {
+	total := 0
+	for _, effect := range se.Effects {
+		total += effect.SpeedMod
+	}
+	return total
+}
+
 // HasEffect checks if a specific effect is active
 func (se *StatusEffects) HasEffect(name string) bool {
 	for _, effect := range se.Effects {
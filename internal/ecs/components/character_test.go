@@ -0,0 +1,72 @@
+package components
+
+import "testing"
+
+func TestStatusEffects_AddEffectStacking(t *testing.T) {
+	tests := []struct {
+		name          string
+		first, second StatusEffect
+		wantIntensity int
+		wantDuration  int
+	}{
+		{
+			name:          "refresh resets duration",
+			first:         StatusEffect{Name: "Confusion", Duration: 2, Stacking: StackRefresh},
+			second:        StatusEffect{Name: "Confusion", Duration: 5, Stacking: StackRefresh},
+			wantIntensity: 0,
+			wantDuration:  5,
+		},
+		{
+			name:          "stack adds intensity",
+			first:         NewPoisonEffect(2, 5),
+			second:        NewPoisonEffect(2, 3),
+			wantIntensity: 4,
+			wantDuration:  3,
+		},
+		{
+			name:          "stack is capped",
+			first:         StatusEffect{Name: "Poison", Intensity: 4, Duration: 5, Stacking: StackIntensity, MaxIntensity: 5},
+			second:        StatusEffect{Name: "Poison", Intensity: 4, Duration: 5, Stacking: StackIntensity, MaxIntensity: 5},
+			wantIntensity: 5,
+			wantDuration:  5,
+		},
+		{
+			name:          "max keeps the stronger effect",
+			first:         NewRegenerationEffect(3, 2),
+			second:        NewRegenerationEffect(1, 6),
+			wantIntensity: 3,
+			wantDuration:  6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			effects := NewStatusEffects()
+			effects.AddEffect(tt.first)
+			effects.AddEffect(tt.second)
+
+			if len(effects.Effects) != 1 {
+				t.Fatalf("Expected 1 effect, got %d", len(effects.Effects))
+			}
+			got := effects.Effects[0]
+			if got.Intensity != tt.wantIntensity || got.Duration != tt.wantDuration {
+				t.Errorf("Got intensity %d, duration %d; want %d, %d", got.Intensity, got.Duration, tt.wantIntensity, tt.wantDuration)
+			}
+		})
+	}
+}
+
+func TestStatusEffects_TickTotals(t *testing.T) {
+	effects := NewStatusEffects()
+	effects.AddEffect(NewPoisonEffect(2, 5))
+	effects.AddEffect(NewRegenerationEffect(1, 5))
+	effects.AddEffect(NewParalysisEffect(1))
+
+	damage, healing := effects.TickTotals()
+	if damage != 2 || healing != 1 {
+		t.Errorf("Expected 2 damage and 1 healing, got %d and %d", damage, healing)
+	}
+	if !effects.IsParalyzed() || effects.IsConfused() {
+		t.Error("Expected paralyzed and not confused")
+	}
+}
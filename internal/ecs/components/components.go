@@ -33,6 +33,11 @@ const (
 	CStatusEffects        ComponentType = "StatusEffects"
 	CTurnActor            ComponentType = "TurnActor"
 	CPathfindingComponent ComponentType = "PathfindingComponent"
+	CSpellbook            ComponentType = "Spellbook"
+	CSummoned             ComponentType = "Summoned"
+	CReward               ComponentType = "Reward"
+	CParent               ComponentType = "Parent"
+	CEquipped             ComponentType = "Equipped"
 )
 
 var TypeToComponent = map[ComponentType]reflect.Type{
@@ -45,7 +50,7 @@ var TypeToComponent = map[ComponentType]reflect.Type{
 	CHealth:               reflect.TypeOf(Health{}),
 	CInventory:            reflect.TypeOf(Inventory{}),
 	CItemPickup:           reflect.TypeOf(ItemPickup{}),
-	CName:                 reflect.TypeOf(""),
+	CName:                 reflect.TypeOf(Name{}),
 	CPlayerTag:            reflect.TypeOf(PlayerTag{}),
 	CPosition:             reflect.TypeOf(gruid.Point{}),
 	CRenderable:           reflect.TypeOf(Renderable{}),
@@ -58,6 +63,11 @@ var TypeToComponent = map[ComponentType]reflect.Type{
 	CStatusEffects:        reflect.TypeOf(StatusEffects{}),
 	CTurnActor:            reflect.TypeOf(TurnActor{}),
 	CPathfindingComponent: reflect.TypeOf(PathfindingComponent{}),
+	CSpellbook:            reflect.TypeOf(Spellbook{}),
+	CSummoned:             reflect.TypeOf(Summoned{}),
+	CReward:               reflect.TypeOf(Reward{}),
+	CParent:               reflect.TypeOf(Parent{}),
+	CEquipped:             reflect.TypeOf(Equipped{}),
 }
 
 // GetGoType returns the corresponding Go type for a ComponentType
@@ -71,6 +81,12 @@ type Name struct {
 	Name string
 }
 
+// Parent component makes an entity part of another one, e.g. an item
+// carried by a creature or lying in a chest. ID is the parent's entity ID.
+type Parent struct {
+	ID int
+}
+
 // Position component represents an entity's position in the game world
 type Position struct {
 	Point gruid.Point
@@ -1,6 +1,8 @@
 package components
 
 import (
+	"encoding/json"
+
 	"codeberg.org/anaseto/gruid"
 	"codeberg.org/anaseto/gruid/rl"
 )
@@ -9,6 +11,8 @@ import (
 type FOV struct {
 	Range   int
 	Visible []uint64
+	Width   int // Size of the map the FOV covers
+	Height  int
 	fov     *rl.FOV
 }
 
@@ -21,10 +25,23 @@ func NewFOVComponent(fovRange, mapWidth, mapHeight int) *FOV {
 	return &FOV{
 		Range:   fovRange,
 		Visible: make([]uint64, bitsetSize),
+		Width:   mapWidth,
+		Height:  mapHeight,
 		fov:     rl.NewFOV(mapRange),
 	}
 }
 
+// UnmarshalJSON decodes a saved FOV and rebuilds its calculator, which is
+// not saved.
+func (f *FOV) UnmarshalJSON(data []byte) error {
+	type savedFOV FOV // Without methods, so decoding doesn't recurse
+	if err := json.Unmarshal(data, (*savedFOV)(f)); err != nil {
+		return err
+	}
+	f.fov = rl.NewFOV(gruid.NewRange(0, 0, f.Width, f.Height))
+	return nil
+}
+
 // IsVisible checks if a point is currently visible according to this component's bitset.
 // Assumes mapWidth is passed correctly or accessible.
 func (f *FOV) IsVisible(p gruid.Point, mapWidth int) bool {
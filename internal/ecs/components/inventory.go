@@ -1,6 +1,11 @@
 package components
 
-import "codeberg.org/anaseto/gruid"
+import (
+	"errors"
+	"fmt"
+
+	"codeberg.org/anaseto/gruid"
+)
 
 // ItemType represents different categories of items
 type ItemType int
@@ -10,8 +15,29 @@ const (
 	ItemTypeArmor
 	ItemTypeConsumable
 	ItemTypeMisc
+	ItemTypeAmmo
+	ItemTypeAccessory
+	ItemTypeKey // Unlocks a locked door, used up in the process
+)
+
+// EquipSlot names the body location an equippable item occupies
+type EquipSlot string
+
+const (
+	SlotWeapon  EquipSlot = "weapon"
+	SlotOffhand EquipSlot = "offhand" // Shields and other off-hand items
+	SlotHead    EquipSlot = "head"
+	SlotBody    EquipSlot = "body"
+	SlotHands   EquipSlot = "hands"
+	SlotFeet    EquipSlot = "feet"
+	SlotRing    EquipSlot = "ring" // Worn on either of two ring fingers
+	SlotAmulet  EquipSlot = "amulet"
 )
 
+// ErrOffhandBlocked is returned when equipping an off-hand item while a
+// two-handed weapon is wielded
+var ErrOffhandBlocked = errors.New("offhand is blocked by a two-handed weapon")
+
 // Item represents a game item
 type Item struct {
 	Name        string
@@ -22,15 +48,78 @@ type Item struct {
 	Value       int
 	Stackable   bool
 	MaxStack    int
+	Weight      int // Per item, counts towards encumbrance
+
+	// Slot the item is worn in. Empty means the default for its type:
+	// weapons go in the weapon slot and armor on the body.
+	Slot      EquipSlot
+	TwoHanded bool // Weapons only: also occupies the offhand
+
+	// Bonuses granted while equipped
+	AttackBonus   int
+	DefenseBonus  int
+	AccuracyBonus int
+	DodgeBonus    int
+	StatBonus     Stats
+
+	// Ranged weapons fire AmmoType items up to Range cells away
+	Range    int
+	AmmoType string
+
+	// Effects applied in order when a consumable is used
+	Effects []ItemEffect
+}
+
+// ItemEffectKind selects what an item effect does
+type ItemEffectKind string
+
+const (
+	EffectHeal        ItemEffectKind = "heal"         // Restore Amount HP
+	EffectRestoreMana ItemEffectKind = "restore_mana" // Restore Amount MP
+	EffectStatus      ItemEffectKind = "status"       // Apply Status to the user
+	EffectTeleport    ItemEffectKind = "teleport"     // Move the user to a random free cell
+	EffectRevealMap   ItemEffectKind = "reveal_map"   // Mark the whole level as explored
+	EffectDamageArea  ItemEffectKind = "damage_area"  // Deal Amount damage to creatures within Radius
+)
+
+// ItemEffect is one step of what happens when an item is used
+type ItemEffect struct {
+	Kind   ItemEffectKind `json:"kind"`
+	Amount int            `json:"amount,omitempty"`
+	Radius int            `json:"radius,omitempty"`
+	Status *StatusEffect  `json:"status,omitempty"`
+}
+
+// EquipSlot returns the slot the item is worn in, or "" if it cannot be
+// equipped
+func (item Item) EquipSlot() EquipSlot {
+	if item.Slot != "" {
+		return item.Slot
+	}
+	switch item.Type {
+	case ItemTypeWeapon:
+		return SlotWeapon
+	case ItemTypeArmor:
+		return SlotBody
+	}
+	return ""
+}
+
+// IsRanged reports whether the item is a weapon that fires projectiles
+func (item Item) IsRanged() bool {
+	return item.Type == ItemTypeWeapon && item.Range > 0
 }
 
 // ItemStack represents a stack of items in inventory
 type ItemStack struct {
 	Item     Item
 	Quantity int
+	Entity   int // Item entity holding the stack, 0 if it has none
 }
 
-// Inventory component for entities that can carry items
+// Inventory component for entities that can carry items. For entities whose
+// items are child entities, it lists the unequipped ones and is kept up to
+// date from them.
 type Inventory struct {
 	Items    []ItemStack
 	Capacity int
@@ -44,53 +133,6 @@ func NewInventory(capacity int) Inventory {
 	}
 }
 
-// AddItem attempts to add an item to the inventory
-func (inv *Inventory) AddItem(item Item, quantity int) bool {
-	// Try to stack with existing items if stackable
-	if item.Stackable {
-		for i := range inv.Items {
-			if inv.Items[i].Item.Name == item.Name {
-				spaceLeft := item.MaxStack - inv.Items[i].Quantity
-				if spaceLeft >= quantity {
-					inv.Items[i].Quantity += quantity
-					return true
-				} else if spaceLeft > 0 {
-					inv.Items[i].Quantity = item.MaxStack
-					quantity -= spaceLeft
-				}
-			}
-		}
-	}
-
-	// Add as new stack if there's space
-	if len(inv.Items) < inv.Capacity {
-		inv.Items = append(inv.Items, ItemStack{
-			Item:     item,
-			Quantity: quantity,
-		})
-		return true
-	}
-
-	return false // Inventory full
-}
-
-// RemoveItem removes a specified quantity of an item
-func (inv *Inventory) RemoveItem(itemName string, quantity int) bool {
-	for i := range inv.Items {
-		if inv.Items[i].Item.Name == itemName {
-			if inv.Items[i].Quantity >= quantity {
-				inv.Items[i].Quantity -= quantity
-				if inv.Items[i].Quantity == 0 {
-					// Remove empty stack
-					inv.Items = append(inv.Items[:i], inv.Items[i+1:]...)
-				}
-				return true
-			}
-		}
-	}
-	return false
-}
-
 // HasItem checks if inventory contains at least the specified quantity of an item
 func (inv *Inventory) HasItem(itemName string, quantity int) bool {
 	for _, stack := range inv.Items {
@@ -120,8 +162,26 @@ func (inv *Inventory) IsFull() bool {
 // Equipment component for entities that can equip items
 type Equipment struct {
 	Weapon    *Item
-	Armor     *Item
-	Accessory *Item
+	Armor     *Item // Body armor
+	Offhand   *Item
+	Head      *Item
+	Hands     *Item
+	Feet      *Item
+	LeftRing  *Item
+	RightRing *Item
+	Amulet    *Item
+}
+
+// Equipped component marks an item entity worn by its parent. Slot is the
+// display name of the equipment slot it occupies, as listed by Slots.
+type Equipped struct {
+	Slot string
+}
+
+// EquippedItem is an occupied equipment slot
+type EquippedItem struct {
+	Slot string // Display name of the slot
+	Item *Item
 }
 
 // NewEquipment creates a new empty equipment set
@@ -129,52 +189,170 @@ func NewEquipment() Equipment {
 	return Equipment{}
 }
 
-// EquipItem equips an item in the appropriate slot
-func (eq *Equipment) EquipItem(item Item) *Item {
-	var oldItem *Item
+// slot returns the field holding the item of the given slot. Rings go to
+// the first free hand, or replace the left ring when both are worn.
+func (eq *Equipment) slot(slot EquipSlot) **Item {
+	switch slot {
+	case SlotWeapon:
+		return &eq.Weapon
+	case SlotOffhand:
+		return &eq.Offhand
+	case SlotHead:
+		return &eq.Head
+	case SlotBody:
+		return &eq.Armor
+	case SlotHands:
+		return &eq.Hands
+	case SlotFeet:
+		return &eq.Feet
+	case SlotRing:
+		if eq.LeftRing != nil && eq.RightRing == nil {
+			return &eq.RightRing
+		}
+		return &eq.LeftRing
+	case SlotAmulet:
+		return &eq.Amulet
+	}
+	return nil
+}
 
-	switch item.Type {
-	case ItemTypeWeapon:
-		oldItem = eq.Weapon
-		eq.Weapon = &item
-	case ItemTypeArmor:
-		oldItem = eq.Armor
-		eq.Armor = &item
-	default:
-		return nil // Cannot equip this item type
+// Equip puts an item in its slot and returns the items it displaced. A
+// two-handed weapon also displaces the offhand item, and off-hand items
+// cannot be equipped while a two-handed weapon is wielded.
+func (eq *Equipment) Equip(item Item) (removed []Item, err error) {
+	target := eq.slot(item.EquipSlot())
+	if target == nil {
+		return nil, fmt.Errorf("%s cannot be equipped", item.Name)
+	}
+	if item.EquipSlot() == SlotOffhand && eq.Weapon != nil && eq.Weapon.TwoHanded {
+		return nil, ErrOffhandBlocked
 	}
 
-	return oldItem
-}
+	if *target != nil {
+		removed = append(removed, **target)
+	}
+	if item.TwoHanded && eq.Offhand != nil {
+		removed = append(removed, *eq.Offhand)
+		eq.Offhand = nil
+	}
+	*target = &item
 
-// UnequipItem removes an item from the specified slot
-func (eq *Equipment) UnequipItem(itemType ItemType) *Item {
-	var item *Item
+	return removed, nil
+}
 
-	switch itemType {
-	case ItemTypeWeapon:
-		item = eq.Weapon
-		eq.Weapon = nil
-	case ItemTypeArmor:
-		item = eq.Armor
-		eq.Armor = nil
+// EquipItem equips an item in its slot and returns the item it replaced in
+// that slot, if any. Items pushed out of other slots, such as the offhand
+// item a two-handed weapon displaces, are not returned: use Equip to get
+// them.
+func (eq *Equipment) EquipItem(item Item) *Item {
+	var replaced *Item
+	if target := eq.slot(item.EquipSlot()); target != nil && *target != nil {
+		old := **target
+		replaced = &old
 	}
+	if _, err := eq.Equip(item); err != nil {
+		return nil
+	}
+	return replaced
+}
 
+// UnequipItem empties the slot with the given display name, as listed by
+// Slots, and returns the item it held
+func (eq *Equipment) UnequipItem(slot string) *Item {
+	field := eq.slotField(slot)
+	if field == nil {
+		return nil
+	}
+	item := *field
+	*field = nil
 	return item
 }
 
-// GetEquippedItem returns the item in the specified slot
-func (eq *Equipment) GetEquippedItem(itemType ItemType) *Item {
-	switch itemType {
-	case ItemTypeWeapon:
-		return eq.Weapon
-	case ItemTypeArmor:
-		return eq.Armor
+// GetEquippedItem returns the item in the slot with the given display name
+func (eq *Equipment) GetEquippedItem(slot string) *Item {
+	if field := eq.slotField(slot); field != nil {
+		return *field
+	}
+	return nil
+}
+
+// Slots lists every equipment slot in display order, empty ones included
+func (eq *Equipment) Slots() []EquippedItem {
+	return []EquippedItem{
+		{"Weapon", eq.Weapon},
+		{"Offhand", eq.Offhand},
+		{"Head", eq.Head},
+		{"Body", eq.Armor},
+		{"Hands", eq.Hands},
+		{"Feet", eq.Feet},
+		{"Left Ring", eq.LeftRing},
+		{"Right Ring", eq.RightRing},
+		{"Amulet", eq.Amulet},
+	}
+}
+
+// slotField returns the field holding the slot with the given display
+// name, or nil if there is no such slot
+func (eq *Equipment) slotField(name string) **Item {
+	switch name {
+	case "Weapon":
+		return &eq.Weapon
+	case "Offhand":
+		return &eq.Offhand
+	case "Head":
+		return &eq.Head
+	case "Body":
+		return &eq.Armor
+	case "Hands":
+		return &eq.Hands
+	case "Feet":
+		return &eq.Feet
+	case "Left Ring":
+		return &eq.LeftRing
+	case "Right Ring":
+		return &eq.RightRing
+	case "Amulet":
+		return &eq.Amulet
 	}
 	return nil
 }
 
-// ItemPickup component for items lying on the ground
+// SetSlot puts an item in the slot with the given display name. It returns
+// false if there is no such slot.
+func (eq *Equipment) SetSlot(name string, item *Item) bool {
+	field := eq.slotField(name)
+	if field != nil {
+		*field = item
+	}
+	return field != nil
+}
+
+// IsFree reports whether an item for the given slot can be equipped without
+// replacing anything
+func (eq *Equipment) IsFree(slot EquipSlot) bool {
+	switch slot {
+	case SlotRing:
+		return eq.LeftRing == nil || eq.RightRing == nil
+	case SlotOffhand:
+		return eq.Offhand == nil && (eq.Weapon == nil || !eq.Weapon.TwoHanded)
+	}
+	target := eq.slot(slot)
+	return target != nil && *target == nil
+}
+
+// Items returns every equipped item
+func (eq *Equipment) Items() []*Item {
+	var items []*Item
+	for _, slot := range eq.Slots() {
+		if slot.Item != nil {
+			items = append(items, slot.Item)
+		}
+	}
+	return items
+}
+
+// ItemPickup component holds what an item entity is, whether it lies on the
+// ground (with a position) or is carried (with a parent)
 type ItemPickup struct {
 	Item     Item
 	Quantity int
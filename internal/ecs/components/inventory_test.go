@@ -1,99 +1,12 @@
 package components
 
 import (
+	"errors"
 	"testing"
 
 	"codeberg.org/anaseto/gruid"
 )
 
-func TestInventory_AddItem(t *testing.T) {
-	inv := NewInventory(5)
-
-	// Test adding a non-stackable item
-	sword := Item{
-		Name:      "Iron Sword",
-		Type:      ItemTypeWeapon,
-		Stackable: false,
-		Value:     100,
-	}
-
-	if !inv.AddItem(sword, 1) {
-		t.Error("Should be able to add item to empty inventory")
-	}
-
-	if len(inv.Items) != 1 {
-		t.Errorf("Expected 1 item in inventory, got %d", len(inv.Items))
-	}
-
-	// Test adding a stackable item
-	potion := Item{
-		Name:      "Health Potion",
-		Type:      ItemTypeConsumable,
-		Stackable: true,
-		MaxStack:  10,
-		Value:     50,
-	}
-
-	if !inv.AddItem(potion, 3) {
-		t.Error("Should be able to add stackable item")
-	}
-
-	if len(inv.Items) != 2 {
-		t.Errorf("Expected 2 different items in inventory, got %d", len(inv.Items))
-	}
-
-	// Test stacking same item
-	if !inv.AddItem(potion, 2) {
-		t.Error("Should be able to stack same item")
-	}
-
-	if len(inv.Items) != 2 {
-		t.Errorf("Expected 2 different items after stacking, got %d", len(inv.Items))
-	}
-
-	// Check quantity
-	if inv.GetItemCount("Health Potion") != 5 {
-		t.Errorf("Expected 5 potions, got %d", inv.GetItemCount("Health Potion"))
-	}
-}
-
-func TestInventory_RemoveItem(t *testing.T) {
-	inv := NewInventory(5)
-
-	potion := Item{
-		Name:      "Health Potion",
-		Type:      ItemTypeConsumable,
-		Stackable: true,
-		MaxStack:  10,
-		Value:     50,
-	}
-
-	inv.AddItem(potion, 5)
-
-	// Remove some items
-	if !inv.RemoveItem("Health Potion", 2) {
-		t.Error("Should be able to remove items")
-	}
-
-	if inv.GetItemCount("Health Potion") != 3 {
-		t.Errorf("Expected 3 potions after removal, got %d", inv.GetItemCount("Health Potion"))
-	}
-
-	// Remove all remaining items
-	if !inv.RemoveItem("Health Potion", 3) {
-		t.Error("Should be able to remove all remaining items")
-	}
-
-	if len(inv.Items) != 0 {
-		t.Errorf("Expected empty inventory after removing all items, got %d items", len(inv.Items))
-	}
-
-	// Try to remove non-existent item
-	if inv.RemoveItem("Non-existent", 1) {
-		t.Error("Should not be able to remove non-existent item")
-	}
-}
-
 func TestInventory_HasItem(t *testing.T) {
 	inv := NewInventory(5)
 
@@ -105,7 +18,7 @@ func TestInventory_HasItem(t *testing.T) {
 		Value:     50,
 	}
 
-	inv.AddItem(potion, 3)
+	inv.Items = append(inv.Items, ItemStack{Item: potion, Quantity: 3})
 
 	if !inv.HasItem("Health Potion", 2) {
 		t.Error("Should have at least 2 potions")
@@ -129,26 +42,20 @@ func TestInventory_IsFull(t *testing.T) {
 
 	sword := Item{Name: "Sword", Type: ItemTypeWeapon, Stackable: false}
 	shield := Item{Name: "Shield", Type: ItemTypeArmor, Stackable: false}
-	helmet := Item{Name: "Helmet", Type: ItemTypeArmor, Stackable: false}
 
 	if inv.IsFull() {
 		t.Error("Empty inventory should not be full")
 	}
 
-	inv.AddItem(sword, 1)
+	inv.Items = append(inv.Items, ItemStack{Item: sword, Quantity: 1})
 	if inv.IsFull() {
 		t.Error("Inventory with 1/2 items should not be full")
 	}
 
-	inv.AddItem(shield, 1)
+	inv.Items = append(inv.Items, ItemStack{Item: shield, Quantity: 1})
 	if !inv.IsFull() {
 		t.Error("Inventory with 2/2 items should be full")
 	}
-
-	// Try to add another item
-	if inv.AddItem(helmet, 1) {
-		t.Error("Should not be able to add item to full inventory")
-	}
 }
 
 func TestEquipment_EquipItem(t *testing.T) {
@@ -200,23 +107,105 @@ func TestEquipment_UnequipItem(t *testing.T) {
 	eq := NewEquipment()
 
 	sword := Item{Name: "Iron Sword", Type: ItemTypeWeapon, Value: 100}
+	ring := Item{Name: "Ruby Ring", Type: ItemTypeAccessory, Slot: SlotRing}
 	eq.EquipItem(sword)
+	eq.EquipItem(ring)
+
+	if got := eq.GetEquippedItem("Left Ring"); got == nil || got.Name != "Ruby Ring" {
+		t.Errorf("Expected the ring in the left ring slot, got %v", got)
+	}
 
 	// Unequip weapon
-	unequipped := eq.UnequipItem(ItemTypeWeapon)
+	unequipped := eq.UnequipItem("Weapon")
 	if unequipped == nil || unequipped.Name != "Iron Sword" {
 		t.Error("Should return unequipped weapon")
 	}
 
-	if eq.Weapon != nil {
+	if eq.Weapon != nil || eq.GetEquippedItem("Weapon") != nil {
 		t.Error("Weapon slot should be empty after unequipping")
 	}
 
 	// Try to unequip from empty slot
-	unequipped = eq.UnequipItem(ItemTypeWeapon)
+	unequipped = eq.UnequipItem("Weapon")
 	if unequipped != nil {
 		t.Error("Should not return item when unequipping from empty slot")
 	}
+	if eq.UnequipItem("Tail") != nil {
+		t.Error("Should not return item from an unknown slot")
+	}
+}
+
+func TestEquipment_EquipItemReturnsSameSlotOnly(t *testing.T) {
+	eq := NewEquipment()
+	eq.EquipItem(Item{Name: "Shield", Type: ItemTypeArmor, Slot: SlotOffhand})
+
+	// The greataxe pushes the shield out, but the weapon slot was empty
+	if old := eq.EquipItem(Item{Name: "Greataxe", Type: ItemTypeWeapon, TwoHanded: true}); old != nil {
+		t.Errorf("Expected nothing replaced in the weapon slot, got %v", old)
+	}
+	if eq.Offhand != nil {
+		t.Error("Expected the shield displaced")
+	}
+}
+
+func TestEquipment_TwoHandedBlocksOffhand(t *testing.T) {
+	eq := NewEquipment()
+
+	shield := Item{Name: "Shield", Type: ItemTypeArmor, Slot: SlotOffhand}
+	greataxe := Item{Name: "Greataxe", Type: ItemTypeWeapon, TwoHanded: true}
+
+	if _, err := eq.Equip(shield); err != nil {
+		t.Fatalf("Unexpected error equipping shield: %v", err)
+	}
+
+	// A two-handed weapon displaces the offhand item
+	removed, err := eq.Equip(greataxe)
+	if err != nil {
+		t.Fatalf("Unexpected error equipping greataxe: %v", err)
+	}
+	if len(removed) != 1 || removed[0].Name != "Shield" {
+		t.Errorf("Expected the shield to be displaced, got %v", removed)
+	}
+	if eq.Offhand != nil || eq.Weapon == nil || eq.Weapon.Name != "Greataxe" {
+		t.Error("Greataxe should be wielded with an empty offhand")
+	}
+
+	// And the offhand stays blocked while it's wielded
+	if eq.IsFree(SlotOffhand) {
+		t.Error("Offhand should not be free while wielding a two-handed weapon")
+	}
+	if _, err := eq.Equip(shield); !errors.Is(err, ErrOffhandBlocked) {
+		t.Errorf("Expected ErrOffhandBlocked, got %v", err)
+	}
+}
+
+func TestEquipment_Rings(t *testing.T) {
+	eq := NewEquipment()
+
+	for _, name := range []string{"Ruby Ring", "Jade Ring"} {
+		removed, err := eq.Equip(Item{Name: name, Type: ItemTypeAccessory, Slot: SlotRing})
+		if err != nil || len(removed) != 0 {
+			t.Fatalf("Equipping %s: removed %v, err %v", name, removed, err)
+		}
+	}
+	if eq.LeftRing == nil || eq.RightRing == nil {
+		t.Fatal("Both ring slots should be filled")
+	}
+	if eq.IsFree(SlotRing) {
+		t.Error("No ring slot should be free")
+	}
+
+	// A third ring replaces the left one
+	removed, _ := eq.Equip(Item{Name: "Iron Ring", Type: ItemTypeAccessory, Slot: SlotRing})
+	if len(removed) != 1 || removed[0].Name != "Ruby Ring" {
+		t.Errorf("Expected the left ring to be replaced, got %v", removed)
+	}
+	if got := len(eq.Items()); got != 2 {
+		t.Errorf("Expected 2 equipped items, got %d", got)
+	}
+	if got := len(eq.Slots()); got != 9 {
+		t.Errorf("Expected 9 slots, got %d", got)
+	}
 }
 
 func TestItemPickup_NewItemPickup(t *testing.T) {
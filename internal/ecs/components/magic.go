@@ -0,0 +1,35 @@
+package components
+
+import "slices"
+
+// Spellbook component lists the spells an entity knows, by spell ID
+type Spellbook struct {
+	Spells []string
+}
+
+// NewSpellbook creates a spellbook containing the given spells
+func NewSpellbook(spells ...string) Spellbook {
+	return Spellbook{
+		Spells: slices.Clone(spells),
+	}
+}
+
+// Knows reports whether the spellbook contains the given spell
+func (sb *Spellbook) Knows(spellID string) bool {
+	return slices.Contains(sb.Spells, spellID)
+}
+
+// Learn adds a spell to the spellbook, returning false if it was already known
+func (sb *Spellbook) Learn(spellID string) bool {
+	if sb.Knows(spellID) {
+		return false
+	}
+	sb.Spells = append(sb.Spells, spellID)
+	return true
+}
+
+// Summoned component marks a creature conjured to fight for the player.
+// It disappears once TurnsLeft reaches zero.
+type Summoned struct {
+	TurnsLeft int
+}
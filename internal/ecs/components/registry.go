@@ -0,0 +1,43 @@
+package components
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// MarshalComponent encodes a component for saving. The component must have
+// the Go type registered for compType in TypeToComponent.
+func MarshalComponent(compType ComponentType, comp any) (json.RawMessage, error) {
+	t, ok := GetGoType(compType)
+	if !ok {
+		return nil, fmt.Errorf("unknown component type %q", compType)
+	}
+	if got := reflect.TypeOf(comp); got != t {
+		return nil, fmt.Errorf("component %s has type %v, expected %v", compType, got, t)
+	}
+	return json.Marshal(comp)
+}
+
+// UnmarshalComponent decodes a saved component into a new value of the Go
+// type registered for compType. Pointer components, like *FOV, are decoded
+// into a freshly allocated value.
+func UnmarshalComponent(compType ComponentType, data []byte) (any, error) {
+	t, ok := GetGoType(compType)
+	if !ok {
+		return nil, fmt.Errorf("unknown component type %q", compType)
+	}
+
+	isPointer := t.Kind() == reflect.Pointer
+	if isPointer {
+		t = t.Elem()
+	}
+	v := reflect.New(t)
+	if err := json.Unmarshal(data, v.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to decode %s component: %w", compType, err)
+	}
+	if isPointer {
+		return v.Interface(), nil
+	}
+	return v.Elem().Interface(), nil
+}
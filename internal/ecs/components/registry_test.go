@@ -0,0 +1,79 @@
+package components
+
+import (
+	"reflect"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+)
+
+func TestRegistry_RoundTripsEveryComponentType(t *testing.T) {
+	for compType, goType := range TypeToComponent {
+		var comp any
+		if goType.Kind() == reflect.Pointer {
+			comp = reflect.New(goType.Elem()).Interface()
+		} else {
+			comp = reflect.Zero(goType).Interface()
+		}
+
+		data, err := MarshalComponent(compType, comp)
+		if err != nil {
+			t.Errorf("%s: %v", compType, err)
+			continue
+		}
+		decoded, err := UnmarshalComponent(compType, data)
+		if err != nil {
+			t.Errorf("%s: %v", compType, err)
+			continue
+		}
+		if got := reflect.TypeOf(decoded); got != goType {
+			t.Errorf("%s: expected a %v back, got %v", compType, goType, got)
+		}
+	}
+}
+
+func TestRegistry_RestoresUnsavedState(t *testing.T) {
+	fov := NewFOVComponent(5, 10, 8)
+	fov.SetVisible(gruid.Point{X: 3, Y: 2}, 10)
+	data, err := MarshalComponent(CFOV, fov)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := UnmarshalComponent(CFOV, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := decoded.(*FOV)
+	if loaded == fov || loaded.Range != 5 || !loaded.IsVisible(gruid.Point{X: 3, Y: 2}, 10) {
+		t.Errorf("Expected a fresh copy of the FOV, got %+v", loaded)
+	}
+	if loaded.GetFOVCalculator() == nil {
+		t.Error("Expected the FOV calculator rebuilt")
+	}
+
+	data, err = MarshalComponent(CTurnActor, NewTurnActor(120))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err = UnmarshalComponent(CTurnActor, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actor := decoded.(TurnActor)
+	actor.AddAction("wait") // Panics without an action queue
+	if actor.Speed != 120 || actor.NextAction() != "wait" {
+		t.Errorf("Expected a working actor with speed 120, got %+v", actor)
+	}
+}
+
+func TestRegistry_RejectsUnknownAndMismatchedTypes(t *testing.T) {
+	if _, err := UnmarshalComponent("Wings", []byte(`{}`)); err == nil {
+		t.Error("Expected decoding an unknown component type to fail")
+	}
+	if _, err := MarshalComponent(CHealth, Mana{}); err == nil {
+		t.Error("Expected encoding a component under the wrong type to fail")
+	}
+	if _, err := UnmarshalComponent(CHealth, []byte(`{"CurrentHP": "full"}`)); err == nil {
+		t.Error("Expected malformed component data to fail")
+	}
+}
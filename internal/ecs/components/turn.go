@@ -2,6 +2,7 @@ package components
 
 import (
 	"container/list"
+	"encoding/json"
 )
 
 // TurnActor represents an entity that takes turns in the game
@@ -22,6 +23,17 @@ func NewTurnActor(speed uint64) TurnActor {
 	}
 }
 
+// UnmarshalJSON decodes a saved actor. Queued actions are not saved, so
+// the actor starts with an empty queue.
+func (ta *TurnActor) UnmarshalJSON(data []byte) error {
+	type savedActor TurnActor // Without methods, so decoding doesn't recurse
+	if err := json.Unmarshal(data, (*savedActor)(ta)); err != nil {
+		return err
+	}
+	ta.actions = list.New()
+	return nil
+}
+
 // QueueAction adds an action to the back of the action queue
 func (ta *TurnActor) QueueAction(action any) *TurnActor {
 	ta.actions.PushBack(action)
@@ -10,52 +10,89 @@ import (
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
 )
 
-// EntityID represents a unique identifier for an entity.
-type EntityID int
-
 // ECS manages entities and their components.
 type ECS struct {
-	nextEntityID EntityID
-	mu           sync.RWMutex
-	entities     map[EntityID]struct{}                         // Just tracks valid entities
-	components   map[components.ComponentType]map[EntityID]any // Generic component storage
+	mu      sync.RWMutex
+	slots   []entitySlot                                 // Generation and state of each entity index
+	free    []uint32                                     // Indices of removed entities, reused by AddEntity
+	stores  map[components.ComponentType]componentStore  // Typed component storage, one store per type
+	hooks   map[components.ComponentType]*componentHooks // Lifecycle hooks by component type
+	pending []componentEvent                             // Changes whose hooks run once the lock is released
 }
 
 // NewECS creates and initializes a new ECS.
 func NewECS() *ECS {
 	return &ECS{
-		nextEntityID: 1,
-		entities:     make(map[EntityID]struct{}),
-		components:   make(map[components.ComponentType]map[EntityID]any),
+		slots:  newEntitySlots(),
+		stores: make(map[components.ComponentType]componentStore),
+		hooks:  make(map[components.ComponentType]*componentHooks),
 	}
 }
 
-// AddEntity creates a new entity and returns its ID.
+// AddEntity creates a new entity and returns its ID. Indices of removed
+// entities are reused under a new generation.
 func (ecs *ECS) AddEntity() EntityID {
 	ecs.mu.Lock()
 	defer ecs.mu.Unlock()
 
-	id := ecs.nextEntityID
-	ecs.entities[id] = struct{}{}
-	ecs.nextEntityID++
-	return id
+	return ecs.allocateSlot(slotAlive)
 }
 
 // AddEntityWithID creates an entity with a specific ID (used for save/load).
-// Returns an error if the ID already exists.
+// Returns an error if the ID already exists or is stale.
 func (ecs *ECS) AddEntityWithID(id EntityID) error {
 	ecs.mu.Lock()
 	defer ecs.mu.Unlock()
 
-	if _, exists := ecs.entities[id]; exists {
-		return fmt.Errorf("entity with ID %d already exists", id)
+	return ecs.claimSlot(id, slotAlive)
+}
+
+// ReserveEntityID keeps AddEntity from handing out id's index. Used when
+// entities live outside this ECS (e.g. on another dungeon level) but must
+// keep globally unique IDs; AddEntityWithID(id) still brings them back.
+func (ecs *ECS) ReserveEntityID(id EntityID) {
+	ecs.mu.Lock()
+	defer ecs.mu.Unlock()
+
+	if err := ecs.claimSlot(id, slotReserved); err != nil {
+		slog.Warn("Failed to reserve entity ID", "entityId", id, "error", err)
+	}
+}
+
+// TransferEntity moves an entity and all of its components into dst,
+// keeping its ID, along with its descendants. The entities no longer exist
+// in the source ECS afterwards, but their indices stay reserved there so
+// they can come back.
+func (ecs *ECS) TransferEntity(id EntityID, dst *ECS) error {
+	ecs.mu.Lock()
+	if !ecs.entityExists(id) {
+		ecs.mu.Unlock()
+		return fmt.Errorf("entity %d does not exist", id)
 	}
 
-	ecs.entities[id] = struct{}{}
+	moved := append([]EntityID{id}, ecs.descendants(id)...)
+	comps := make([]map[components.ComponentType]any, len(moved))
+	for i, movedID := range moved {
+		comps[i] = make(map[components.ComponentType]any)
+		for compType, store := range ecs.stores {
+			if comp, ok := store.get(movedID); ok {
+				comps[i][compType] = comp
+				store.remove(movedID)
+				ecs.record(hookRemove, movedID, compType, comp, nil)
+			}
+		}
+		ecs.slots[movedID.Index()].state = slotReserved
+	}
+	ecs.unlock()
 
-	// Update nextEntityID if necessary to avoid conflicts
-	if id >= ecs.nextEntityID {
-		ecs.nextEntityID = id + 1
+	// Parents arrive first, so hooks in dst see each child's parent
+	for i, movedID := range moved {
+		if err := dst.AddEntityWithID(movedID); err != nil {
+			return err
+		}
+		for compType, comp := range comps[i] {
+			dst.AddComponent(movedID, compType, comp)
+		}
 	}
 
 	return nil
@@ -70,25 +107,26 @@ func (ecs *ECS) AddEntityWithID(id EntityID) error {
 // and usability, prefer type-specific methods like UpdateAIComponent.
 func (ecs *ECS) UpdateComponent(entityID EntityID, componentType components.ComponentType, updateFunc func(component interface{}) error) error {
 	ecs.mu.Lock()
-	defer ecs.mu.Unlock()
+	defer ecs.unlock()
 
 	// Check if entity exists
-	if _, exists := ecs.entities[entityID]; !exists {
+	if !ecs.entityExists(entityID) {
 		return fmt.Errorf("entity %d does not exist", entityID)
 	}
 
 	// Check if component exists
-	componentMap, exists := ecs.components[componentType]
+	store, exists := ecs.stores[componentType]
 	if !exists {
 		return fmt.Errorf("component type %s not registered", componentType)
 	}
 
-	component, exists := componentMap[entityID]
+	component, exists := store.get(entityID)
 	if !exists {
 		return fmt.Errorf("entity %d does not have component %s", entityID, componentType)
 	}
 
 	// Create a pointer to the component for mutation
+	old := component
 	componentPtr := &component
 
 	// Call the update function with the component pointer
@@ -98,7 +136,10 @@ func (ecs *ECS) UpdateComponent(entityID EntityID, componentType components.Comp
 	}
 
 	// Store the modified component back
-	componentMap[entityID] = *componentPtr
+	if !store.set(entityID, *componentPtr) {
+		return fmt.Errorf("updated component has the wrong type for %s", componentType)
+	}
+	ecs.record(hookChange, entityID, componentType, old, *componentPtr)
 	return nil
 }
 
@@ -113,79 +154,121 @@ func (ecs *ECS) UpdateComponent(entityID EntityID, componentType components.Comp
 // - Prevents the state loss bug that occurred with pointer-to-copy patterns
 func (ecs *ECS) UpdateAIComponent(entityID EntityID, updateFunc func(*components.AIComponent) error) error {
 	ecs.mu.Lock()
-	defer ecs.mu.Unlock()
+	defer ecs.unlock()
 
 	// Check if entity exists
-	if _, exists := ecs.entities[entityID]; !exists {
+	if !ecs.entityExists(entityID) {
 		return fmt.Errorf("entity %d does not exist", entityID)
 	}
 
 	// Check if component exists
-	componentMap, exists := ecs.components[components.CAIComponent]
+	store, exists := ecs.stores[components.CAIComponent].(*sparseSet[components.AIComponent])
 	if !exists {
 		return fmt.Errorf("AI component type not registered")
 	}
 
-	component, exists := componentMap[entityID]
-	if !exists {
+	aiComp := store.ptr(entityID)
+	if aiComp == nil {
 		return fmt.Errorf("entity %d does not have AI component", entityID)
 	}
 
-	// Type assert to AIComponent
-	aiComp, ok := component.(components.AIComponent)
-	if !ok {
-		return fmt.Errorf("component is not an AIComponent")
-	}
-
-	// Call the update function with a pointer to the component
-	err := updateFunc(&aiComp)
-	if err != nil {
+	// Call the update function with a pointer to a copy, so a failed update
+	// leaves the stored component untouched
+	updated := *aiComp
+	if err := updateFunc(&updated); err != nil {
 		return err
 	}
 
 	// Store the modified component back
-	componentMap[entityID] = aiComp
+	if ecs.observed(components.CAIComponent) {
+		ecs.record(hookChange, entityID, components.CAIComponent, *aiComp, updated)
+	}
+	*aiComp = updated
 	return nil
 }
 
-// RemoveEntity removes an entity and all its components.
+// RemoveEntity removes an entity and all its components, along with its
+// descendants. Stale handles to it are ignored.
 func (ecs *ECS) RemoveEntity(id EntityID) {
 	ecs.mu.Lock()
-	defer ecs.mu.Unlock()
-	delete(ecs.entities, id)
-	for _, components := range ecs.components {
-		delete(components, id)
+	defer ecs.unlock()
+
+	if !ecs.entityExists(id) {
+		return
+	}
+	for _, removed := range append([]EntityID{id}, ecs.descendants(id)...) {
+		for compType := range ecs.stores {
+			ecs.removeComponent(removed, compType)
+		}
+		ecs.releaseSlot(removed)
 	}
 }
 
-// EntityExists checks if an entity exists.
+// EntityExists checks if an entity exists. Handles to removed entities
+// report false even once their index has been reused.
 func (ecs *ECS) EntityExists(id EntityID) bool {
 	ecs.mu.RLock()
 	defer ecs.mu.RUnlock()
-	_, ok := ecs.entities[id]
-	return ok
+	return ecs.entityExists(id)
 }
 
 // HasComponent checks if an entity has a specific component.
 func (ecs *ECS) HasComponent(id EntityID, compType components.ComponentType) bool {
-	_, exists := ecs.getComponent(id, compType)
-	return exists
+	ecs.mu.RLock()
+	defer ecs.mu.RUnlock()
+	return ecs.hasComponent(id, compType)
+}
+
+// ComponentsOf returns every component of an entity, by type.
+func (ecs *ECS) ComponentsOf(id EntityID) map[components.ComponentType]any {
+	ecs.mu.RLock()
+	defer ecs.mu.RUnlock()
+
+	comps := make(map[components.ComponentType]any)
+	for compType, store := range ecs.stores {
+		if comp, ok := store.get(id); ok {
+			comps[compType] = comp
+		}
+	}
+	return comps
 }
 
 // AddComponent adds or updates a component for an entity.
 func (ecs *ECS) AddComponent(id EntityID, compType components.ComponentType, component any) {
-	if !ecs.EntityExists(id) {
+	ecs.mu.Lock()
+	defer ecs.unlock()
+
+	if !ecs.entityExists(id) {
 		slog.Debug("Warning: Attempted to add component to non-existent entity", "componentType", compType, "entityId", id)
 		return
 	}
+	ecs.addComponent(id, compType, component)
+}
 
-	ecs.mu.Lock()
-	defer ecs.mu.Unlock()
-	if ecs.components[compType] == nil {
-		ecs.components[compType] = make(map[EntityID]any)
+// addComponent stores a component and records the addition or change for
+// hooks. The caller holds the lock.
+func (ecs *ECS) addComponent(id EntityID, compType components.ComponentType, component any) {
+	store, ok := ecs.stores[compType]
+	if !ok {
+		store = newStore(compType)
+		ecs.stores[compType] = store
 	}
 
-	ecs.components[compType][id] = component
+	// Only observed types pay for boxing the old value
+	var old any
+	had := false
+	if ecs.observed(compType) {
+		old, had = store.get(id)
+	}
+	if !store.set(id, component) {
+		slog.Warn("Component has the wrong type for its storage", "componentType", compType, "type", fmt.Sprintf("%T", component), "entityId", id)
+		return
+	}
+	if had {
+		ecs.record(hookChange, id, compType, old, component)
+	} else {
+		ecs.record(hookAdd, id, compType, nil, component)
+	}
 }
 
 // AddComponents adds multiple components to an entity at once.
@@ -236,11 +319,9 @@ func (ecs *ECS) AddComponents(id EntityID, comps ...any) {
 // RemoveComponent removes a component from an entity.
 func (ecs *ECS) RemoveComponent(id EntityID, compType components.ComponentType) {
 	ecs.mu.Lock()
-	defer ecs.mu.Unlock()
+	defer ecs.unlock()
 
-	if components, ok := ecs.components[compType]; ok {
-		delete(components, id)
-	}
+	ecs.removeComponent(id, compType)
 }
 
 // RemoveComponents removes multiple components from an entity.
@@ -253,8 +334,19 @@ func (ecs *ECS) RemoveComponents(id EntityID, compTypes ...components.ComponentT
 // GetComponentTyped retrieves a component for an entity and returns it as the concrete type T.
 // Returns the zero value of T and false if the component doesn't exist or if type assertion fails.
 func GetComponentTyped[T any](ecs *ECS, id EntityID, compType components.ComponentType) (T, bool) {
+	ecs.mu.RLock()
+	defer ecs.mu.RUnlock()
+
 	var result T
-	comp, ok := ecs.getComponent(id, compType)
+	store, ok := ecs.stores[compType]
+	if !ok {
+		return result, false
+	}
+	// Typed stores hand the value out directly, without boxing
+	if typed, ok := store.(*sparseSet[T]); ok {
+		return typed.getTyped(id)
+	}
+	comp, ok := store.get(id)
 	if !ok {
 		return result, false
 	}
@@ -268,15 +360,36 @@ func GetComponentTyped[T any](ecs *ECS, id EntityID, compType components.Compone
 
 // --- Helper Functions ---
 
+// removeComponent removes a component and records the removal for hooks.
+// The caller holds the lock.
+func (ecs *ECS) removeComponent(id EntityID, compType components.ComponentType) {
+	store, ok := ecs.stores[compType]
+	if !ok {
+		return
+	}
+	if !ecs.observed(compType) {
+		store.remove(id)
+		return
+	}
+	if comp, ok := store.get(id); ok {
+		store.remove(id)
+		ecs.record(hookRemove, id, compType, comp, nil)
+	}
+}
+
+// hasComponent checks for a component without acquiring the lock.
+func (ecs *ECS) hasComponent(id EntityID, compType components.ComponentType) bool {
+	store, ok := ecs.stores[compType]
+	return ok && store.has(id)
+}
+
 // GetComponent retrieves a component for an entity.
 func (ecs *ECS) getComponent(id EntityID, compType components.ComponentType) (any, bool) {
 	ecs.mu.RLock()
 	defer ecs.mu.RUnlock()
 
-	if componentMap, ok := ecs.components[compType]; ok {
-		if component, exists := componentMap[id]; exists {
-			return component, true
-		}
+	if store, ok := ecs.stores[compType]; ok {
+		return store.get(id)
 	}
 
 	return nil, false
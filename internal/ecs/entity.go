@@ -0,0 +1,168 @@
+package ecs
+
+import (
+	"fmt"
+	"slices"
+)
+
+// EntityID represents a unique identifier for an entity. The low bits hold
+// the index of the entity's slot and the high bits its generation. A slot
+// is reused once its entity is removed, with a bumped generation, so handles
+// to the removed entity stay stale instead of pointing at the newcomer.
+// Zero is never a valid entity and is used as "no entity".
+type EntityID int
+
+const (
+	entityIndexBits = 32
+	indexMask       = 1<<entityIndexBits - 1
+	generationMask  = 1<<31 - 1 // Keeps IDs positive
+)
+
+// NewEntityID builds an ID from a slot index and a generation.
+func NewEntityID(index, generation uint32) EntityID {
+	return EntityID(uint64(generation&generationMask)<<entityIndexBits | uint64(index))
+}
+
+// Index returns the slot index of the entity.
+func (id EntityID) Index() uint32 {
+	return uint32(id & indexMask)
+}
+
+// Generation returns how many earlier entities used the same slot.
+func (id EntityID) Generation() uint32 {
+	return uint32(id >> entityIndexBits)
+}
+
+// slotState tells whether a slot holds a live entity
+type slotState uint8
+
+const (
+	slotFree     slotState = iota // Available for AddEntity at its generation
+	slotAlive                     // Holds a live entity of its generation
+	slotReserved                  // Held for an entity living in another ECS
+)
+
+// entitySlot tracks the current generation of an entity index
+type entitySlot struct {
+	generation uint32
+	state      slotState
+}
+
+// newEntitySlots returns the initial slots. Slot 0 is reserved forever so
+// no entity gets ID 0.
+func newEntitySlots() []entitySlot {
+	return []entitySlot{{state: slotReserved}}
+}
+
+// entityExists checks a handle against its slot without acquiring the lock.
+func (ecs *ECS) entityExists(id EntityID) bool {
+	slot, ok := ecs.slot(id)
+	return ok && slot.state == slotAlive && slot.generation == id.Generation()
+}
+
+// slot returns the slot for the ID's index, if it was ever allocated.
+func (ecs *ECS) slot(id EntityID) (*entitySlot, bool) {
+	if id <= 0 || int(id.Index()) >= len(ecs.slots) {
+		return nil, false
+	}
+	return &ecs.slots[id.Index()], true
+}
+
+// allocateSlot takes a free slot, or a new one, and gives it state. The
+// caller holds the lock.
+func (ecs *ECS) allocateSlot(state slotState) EntityID {
+	var index uint32
+	if n := len(ecs.free); n > 0 {
+		index = ecs.free[n-1]
+		ecs.free = ecs.free[:n-1]
+	} else {
+		index = uint32(len(ecs.slots))
+		ecs.slots = append(ecs.slots, entitySlot{})
+	}
+
+	slot := &ecs.slots[index]
+	slot.state = state
+	return NewEntityID(index, slot.generation)
+}
+
+// growSlots allocates slots until there are n. New slots are free for
+// AddEntity.
+func (ecs *ECS) growSlots(n int) {
+	for i := len(ecs.slots); i < n; i++ {
+		ecs.slots = append(ecs.slots, entitySlot{})
+		ecs.free = append(ecs.free, uint32(i))
+	}
+}
+
+// unfree takes a slot off the free list. Recently grown slots sit at the
+// end, so search from there.
+func (ecs *ECS) unfree(index uint32) {
+	for i := len(ecs.free) - 1; i >= 0; i-- {
+		if ecs.free[i] == index {
+			ecs.free = slices.Delete(ecs.free, i, i+1)
+			return
+		}
+	}
+}
+
+// claimSlot marks the ID's slot as alive or reserved for it. A free slot may
+// be claimed at its generation or any later one, a reserved slot only by
+// the entity it was reserved for.
+func (ecs *ECS) claimSlot(id EntityID, state slotState) error {
+	if id <= 0 || id.Index() == 0 {
+		return fmt.Errorf("invalid entity ID %d", id)
+	}
+
+	index := id.Index()
+	ecs.growSlots(int(index) + 1)
+
+	slot := &ecs.slots[index]
+	switch {
+	case slot.state == slotAlive:
+		return fmt.Errorf("entity with ID %d already exists", NewEntityID(index, slot.generation))
+	case slot.state == slotReserved && slot.generation != id.Generation():
+		return fmt.Errorf("entity slot %d is reserved for generation %d, not %d", index, slot.generation, id.Generation())
+	case slot.state == slotFree && id.Generation() < slot.generation:
+		return fmt.Errorf("entity ID %d is stale, slot %d is at generation %d", id, index, slot.generation)
+	case slot.state == slotFree:
+		ecs.unfree(index)
+	}
+	slot.generation = id.Generation()
+	slot.state = state
+	return nil
+}
+
+// releaseSlot frees the entity's slot for reuse under the next generation.
+func (ecs *ECS) releaseSlot(id EntityID) {
+	slot := &ecs.slots[id.Index()]
+	slot.generation = (slot.generation + 1) & generationMask
+	slot.state = slotFree
+	ecs.free = append(ecs.free, id.Index())
+}
+
+// SlotGenerations returns the current generation of every entity slot, so a
+// save can keep removed entities' handles stale after loading.
+func (ecs *ECS) SlotGenerations() []uint32 {
+	ecs.mu.RLock()
+	defer ecs.mu.RUnlock()
+
+	generations := make([]uint32, len(ecs.slots))
+	for i, slot := range ecs.slots {
+		generations[i] = slot.generation
+	}
+	return generations
+}
+
+// RestoreSlotGenerations sets the generations of free slots from a save.
+// Call it on an empty ECS, before recreating entities.
+func (ecs *ECS) RestoreSlotGenerations(generations []uint32) {
+	ecs.mu.Lock()
+	defer ecs.mu.Unlock()
+
+	ecs.growSlots(len(generations))
+	for i, generation := range generations {
+		if i > 0 && ecs.slots[i].state == slotFree {
+			ecs.slots[i].generation = generation & generationMask
+		}
+	}
+}
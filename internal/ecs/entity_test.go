@@ -0,0 +1,116 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func TestEntityID_IndexAndGeneration(t *testing.T) {
+	id := NewEntityID(7, 3)
+	if id.Index() != 7 || id.Generation() != 3 {
+		t.Errorf("Expected index 7 generation 3, got %d, %d", id.Index(), id.Generation())
+	}
+	if id <= 0 {
+		t.Errorf("Expected a positive ID, got %d", id)
+	}
+}
+
+func TestECS_StaleHandleAfterReuse(t *testing.T) {
+	world := NewECS()
+	old := world.AddEntity()
+	world.AddComponent(old, components.CPosition, gruid.Point{X: 1, Y: 1})
+	world.RemoveEntity(old)
+
+	reused := world.AddEntity()
+	world.AddComponent(reused, components.CPosition, gruid.Point{X: 2, Y: 2})
+
+	if reused.Index() != old.Index() || reused.Generation() != old.Generation()+1 {
+		t.Fatalf("Expected the index to be reused with the next generation, got %d after %d", reused, old)
+	}
+	if world.EntityExists(old) {
+		t.Error("Expected the stale handle to no longer exist")
+	}
+	if _, ok := world.GetPosition(old); ok {
+		t.Error("Expected the stale handle to have no components")
+	}
+
+	world.AddComponent(old, components.CPosition, gruid.Point{X: 9, Y: 9})
+	world.RemoveComponent(old, components.CPosition)
+	world.RemoveEntity(old)
+	if pos, ok := world.GetPosition(reused); !ok || pos != (gruid.Point{X: 2, Y: 2}) {
+		t.Errorf("Expected the stale handle to leave the new entity alone, got %v, %v", pos, ok)
+	}
+	if got := world.GetAllEntities(); !slices.Equal(got, []EntityID{reused}) {
+		t.Errorf("Expected only the new entity, got %v", got)
+	}
+}
+
+func TestECS_AddEntityWithID(t *testing.T) {
+	world := NewECS()
+	id := NewEntityID(4, 2)
+	if err := world.AddEntityWithID(id); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := world.AddEntityWithID(id); err == nil {
+		t.Error("Expected an error for a live ID")
+	}
+	world.RemoveEntity(id)
+	if err := world.AddEntityWithID(id); err == nil {
+		t.Error("Expected an error for a stale ID")
+	}
+	if err := world.AddEntityWithID(NewEntityID(4, 3)); err != nil {
+		t.Errorf("Expected the next generation to be accepted: %v", err)
+	}
+
+	// Lower indices were allocated as free slots and are handed out first
+	if next := world.AddEntity(); next.Index() >= 4 || next.Generation() != 0 {
+		t.Errorf("Expected a fresh low index, got %d", next)
+	}
+}
+
+func TestECS_TransferKeepsSlotReserved(t *testing.T) {
+	world, other := NewECS(), NewECS()
+	id := world.AddEntity()
+	world.AddComponent(id, components.CName, components.Name{Name: "rat"})
+
+	if err := world.TransferEntity(id, other); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if world.EntityExists(id) || !other.EntityExists(id) {
+		t.Fatal("Expected the entity to move to the other ECS")
+	}
+	if next := world.AddEntity(); next.Index() == id.Index() {
+		t.Error("Expected the transferred entity's index to stay reserved")
+	}
+	if err := world.AddEntityWithID(NewEntityID(id.Index(), id.Generation()+1)); err == nil {
+		t.Error("Expected a reserved slot to reject another generation")
+	}
+
+	if err := other.TransferEntity(id, world); err != nil {
+		t.Fatalf("Unexpected error transferring back: %v", err)
+	}
+	if name, ok := world.GetName(id); !ok || name != "rat" {
+		t.Errorf("Expected the name to come back, got %q, %v", name, ok)
+	}
+}
+
+func TestECS_RestoreSlotGenerations(t *testing.T) {
+	world := NewECS()
+	kept, removed := world.AddEntity(), world.AddEntity()
+	world.RemoveEntity(removed)
+
+	loaded := NewECS()
+	loaded.RestoreSlotGenerations(world.SlotGenerations())
+	if err := loaded.AddEntityWithID(kept); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := loaded.AddEntityWithID(removed); err == nil {
+		t.Error("Expected the removed entity's ID to stay stale after restoring")
+	}
+	if next := loaded.AddEntity(); next.Index() != removed.Index() || next == removed {
+		t.Errorf("Expected the removed entity's index under a new generation, got %d", next)
+	}
+}
@@ -0,0 +1,129 @@
+package ecs
+
+import "github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+
+// Component hooks let systems keep derived state, such as a spatial index,
+// in sync with the components it mirrors instead of being updated by hand
+// at every call site. Hooks run after the change, once the ECS lock is
+// released, so they may use the rest of the ECS API.
+//
+// Components mutated in place through query pointers do not trigger hooks.
+
+// AddHook is called when an entity gains a component it did not have.
+type AddHook func(id EntityID, comp any)
+
+// RemoveHook is called when an entity loses a component, including when the
+// entity itself is removed or transferred. comp is the removed value.
+type RemoveHook func(id EntityID, comp any)
+
+// ChangeHook is called when an existing component is replaced, e.g. by
+// AddComponent, MoveEntity or UpdateComponent.
+type ChangeHook func(id EntityID, old, new any)
+
+// componentHooks holds the hooks registered for one component type
+type componentHooks struct {
+	onAdd    []AddHook
+	onRemove []RemoveHook
+	onChange []ChangeHook
+}
+
+// hookKind tells which hooks an event triggers
+type hookKind uint8
+
+const (
+	hookAdd hookKind = iota
+	hookRemove
+	hookChange
+)
+
+// componentEvent records a component change until its hooks can run
+type componentEvent struct {
+	kind     hookKind
+	id       EntityID
+	compType components.ComponentType
+	old, new any
+}
+
+// OnAdd registers fn to run whenever an entity gains a component of compType.
+func (ecs *ECS) OnAdd(compType components.ComponentType, fn AddHook) {
+	ecs.mu.Lock()
+	defer ecs.mu.Unlock()
+	hooks := ecs.hooksFor(compType)
+	hooks.onAdd = append(hooks.onAdd, fn)
+}
+
+// OnRemove registers fn to run whenever an entity loses a component of
+// compType.
+func (ecs *ECS) OnRemove(compType components.ComponentType, fn RemoveHook) {
+	ecs.mu.Lock()
+	defer ecs.mu.Unlock()
+	hooks := ecs.hooksFor(compType)
+	hooks.onRemove = append(hooks.onRemove, fn)
+}
+
+// OnChange registers fn to run whenever a component of compType is replaced.
+func (ecs *ECS) OnChange(compType components.ComponentType, fn ChangeHook) {
+	ecs.mu.Lock()
+	defer ecs.mu.Unlock()
+	hooks := ecs.hooksFor(compType)
+	hooks.onChange = append(hooks.onChange, fn)
+}
+
+// hooksFor returns the hooks of compType, creating them if needed. The
+// caller holds the lock.
+func (ecs *ECS) hooksFor(compType components.ComponentType) *componentHooks {
+	hooks, ok := ecs.hooks[compType]
+	if !ok {
+		hooks = &componentHooks{}
+		ecs.hooks[compType] = hooks
+	}
+	return hooks
+}
+
+// observed reports whether any hook watches compType. Unobserved changes
+// skip recording events, so typed stores do not box the old value. The
+// caller holds the lock.
+func (ecs *ECS) observed(compType components.ComponentType) bool {
+	_, ok := ecs.hooks[compType]
+	return ok
+}
+
+// record queues the hooks of a change. The caller holds the write lock and
+// releases it with unlock.
+func (ecs *ECS) record(kind hookKind, id EntityID, compType components.ComponentType, old, new any) {
+	if ecs.observed(compType) {
+		ecs.pending = append(ecs.pending, componentEvent{kind: kind, id: id, compType: compType, old: old, new: new})
+	}
+}
+
+// unlock releases the write lock, then runs the hooks of the changes made
+// while holding it.
+func (ecs *ECS) unlock() {
+	events := ecs.pending
+	ecs.pending = nil
+	ecs.mu.Unlock()
+
+	for _, event := range events {
+		ecs.mu.RLock()
+		var hooks componentHooks
+		if registered, ok := ecs.hooks[event.compType]; ok {
+			hooks = *registered
+		}
+		ecs.mu.RUnlock()
+
+		switch event.kind {
+		case hookAdd:
+			for _, fn := range hooks.onAdd {
+				fn(event.id, event.new)
+			}
+		case hookRemove:
+			for _, fn := range hooks.onRemove {
+				fn(event.id, event.old)
+			}
+		case hookChange:
+			for _, fn := range hooks.onChange {
+				fn(event.id, event.old, event.new)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func TestHooks_AddChangeRemove(t *testing.T) {
+	world := NewECS()
+	var log []string
+	world.OnAdd(components.CPosition, func(id EntityID, comp any) {
+		log = append(log, "add "+comp.(gruid.Point).String())
+	})
+	world.OnChange(components.CPosition, func(id EntityID, old, new any) {
+		log = append(log, "change "+old.(gruid.Point).String()+" "+new.(gruid.Point).String())
+	})
+	world.OnRemove(components.CPosition, func(id EntityID, comp any) {
+		log = append(log, "remove "+comp.(gruid.Point).String())
+	})
+
+	id := world.AddEntity()
+	world.AddComponent(id, components.CPosition, gruid.Point{X: 1, Y: 1})
+	if err := world.MoveEntity(id, gruid.Point{X: 2, Y: 1}); err != nil {
+		t.Fatal(err)
+	}
+	world.RemoveComponent(id, components.CPosition)
+	world.RemoveComponent(id, components.CPosition) // Absent, no hook
+	world.AddComponent(id, components.CPosition, gruid.Point{X: 3, Y: 3})
+	world.AddComponent(id, components.CName, components.Name{Name: "unobserved"})
+	world.RemoveEntity(id)
+
+	want := []string{"add (1,1)", "change (1,1) (2,1)", "remove (2,1)", "add (3,3)", "remove (3,3)"}
+	if !slices.Equal(log, want) {
+		t.Errorf("Expected %v, got %v", want, log)
+	}
+}
+
+func TestHooks_TransferAndUpdate(t *testing.T) {
+	src, dst := NewECS(), NewECS()
+	var removed, added, changed int
+	src.OnRemove(components.CHealth, func(EntityID, any) { removed++ })
+	dst.OnAdd(components.CHealth, func(EntityID, any) { added++ })
+	dst.OnChange(components.CHealth, func(_ EntityID, old, new any) {
+		if old.(components.Health).CurrentHP != 10 || new.(components.Health).CurrentHP != 4 {
+			t.Errorf("Unexpected change %v -> %v", old, new)
+		}
+		changed++
+	})
+
+	id := src.AddEntity()
+	src.AddComponent(id, components.CHealth, components.NewHealth(10))
+	if err := src.TransferEntity(id, dst); err != nil {
+		t.Fatal(err)
+	}
+	err := dst.UpdateComponent(id, components.CHealth, func(comp any) error {
+		health := (*comp.(*any)).(components.Health)
+		health.CurrentHP = 4
+		*comp.(*any) = health
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if removed != 1 || added != 1 || changed != 1 {
+		t.Errorf("Expected one removal, addition and change, got %d, %d, %d", removed, added, changed)
+	}
+}
+
+func TestHooks_CanUseECS(t *testing.T) {
+	world := NewECS()
+
+	// A hook may mutate the ECS, triggering further hooks
+	world.OnAdd(components.CCorpseTag, func(id EntityID, _ any) {
+		world.RemoveComponent(id, components.CHealth)
+	})
+	var removedHealth bool
+	world.OnRemove(components.CHealth, func(EntityID, any) { removedHealth = true })
+
+	id := world.AddEntity()
+	world.AddComponents(id, components.NewHealth(5), components.CorpseTag{})
+
+	if world.HasComponent(id, components.CHealth) || !removedHealth {
+		t.Error("Expected the corpse hook to strip health and trigger its removal hook")
+	}
+}
@@ -1,29 +1,30 @@
 package ecs
 
 import (
-	"log/slog"
+	"slices"
 
 	"codeberg.org/anaseto/gruid"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
 )
 
-// Helper method that doesn't acquire a lock (for use in methods that already have the lock)
-func (ecs *ECS) entityExists(id EntityID) bool {
-	_, ok := ecs.entities[id]
-	return ok
-}
-
 // EntitiesAt returns a slice of EntityIDs located at the given point.
 func (ecs *ECS) EntitiesAt(p gruid.Point) []EntityID {
 	ecs.mu.RLock()
 	defer ecs.mu.RUnlock()
+	return ecs.entitiesAt(p)
+}
+
+// entitiesAt scans the packed positions without acquiring the lock.
+func (ecs *ECS) entitiesAt(p gruid.Point) []EntityID {
+	positions, ok := ecs.stores[components.CPosition].(*sparseSet[gruid.Point])
+	if !ok {
+		return nil
+	}
 
 	var ids []EntityID
-	if posMap, ok := ecs.components[components.CPosition]; ok {
-		for id, comp := range posMap {
-			if pos, ok := comp.(gruid.Point); ok && pos == p {
-				ids = append(ids, id)
-			}
+	for i, pos := range positions.data {
+		if pos == p {
+			ids = append(ids, positions.ids[i])
 		}
 	}
 	return ids
@@ -33,10 +34,10 @@ func (ecs *ECS) GetEntitiesAtWithComponents(p gruid.Point, compType components.C
 	ecs.mu.RLock()
 	defer ecs.mu.RUnlock()
 
-	ids := ecs.EntitiesAt(p)
+	ids := ecs.entitiesAt(p)
 	results := make([]EntityID, 0, len(ids))
 	for _, id := range ids {
-		if ecs.HasComponent(id, compType) {
+		if ecs.hasComponent(id, compType) {
 			results = append(results, id)
 		}
 	}
@@ -48,9 +49,11 @@ func (ecs *ECS) GetEntitiesAtWithComponents(p gruid.Point, compType components.C
 func (ecs *ECS) GetAllEntities() []EntityID {
 	ecs.mu.RLock()
 	defer ecs.mu.RUnlock()
-	ids := make([]EntityID, 0, len(ecs.entities))
-	for id := range ecs.entities {
-		ids = append(ids, id)
+	var ids []EntityID
+	for i, slot := range ecs.slots {
+		if slot.state == slotAlive {
+			ids = append(ids, NewEntityID(uint32(i), slot.generation))
+		}
 	}
 	return ids
 }
@@ -60,30 +63,43 @@ func (ecs *ECS) GetEntitiesWithComponent(compType components.ComponentType) []En
 	ecs.mu.RLock()
 	defer ecs.mu.RUnlock()
 
-	var entities []EntityID
-	if components, ok := ecs.components[compType]; ok {
-		entities = make([]EntityID, 0, len(components))
-		for id := range components {
-			entities = append(entities, id)
-		}
+	if store, ok := ecs.stores[compType]; ok && store.len() > 0 {
+		return slices.Clone(store.entities())
 	}
-	return entities
+	return nil
 }
 
 // GetEntitiesWithComponents returns entities that have all specified components.
 func (ecs *ECS) GetEntitiesWithComponents(compTypes ...components.ComponentType) []EntityID {
+	ecs.mu.RLock()
+	defer ecs.mu.RUnlock()
+	return ecs.entitiesWith(compTypes)
+}
+
+// entitiesWith returns the entities having all the given components, without
+// acquiring the lock. It walks the smallest store and probes the others.
+func (ecs *ECS) entitiesWith(compTypes []components.ComponentType) []EntityID {
 	if len(compTypes) == 0 {
 		return nil
 	}
-	entities := ecs.GetEntitiesWithComponent(compTypes[0])
-	if len(entities) == 0 {
-		return nil
+
+	stores := make([]componentStore, len(compTypes))
+	for i, ct := range compTypes {
+		store, ok := ecs.stores[ct]
+		if !ok || store.len() == 0 {
+			return nil
+		}
+		stores[i] = store
 	}
+	smallest := slices.MinFunc(stores, func(a, b componentStore) int {
+		return a.len() - b.len()
+	})
+
 	var result []EntityID
-	for _, id := range entities {
+	for _, id := range smallest.entities() {
 		hasAll := true
-		for _, ct := range compTypes[1:] {
-			if !ecs.HasComponent(id, ct) {
+		for _, store := range stores {
+			if store != smallest && !store.has(id) {
 				hasAll = false
 				break
 			}
@@ -107,26 +123,11 @@ type PositionedRenderableEntity struct {
 
 // GetEntitiesWithPositionAndRenderable queries entities having both Position and Renderable components.
 func (ecs *ECS) GetEntitiesWithPositionAndRenderable() []PositionedRenderableEntity {
-	entities := ecs.GetEntitiesWithComponents(components.CPosition, components.CRenderable)
-	result := make([]PositionedRenderableEntity, 0, len(entities))
-
-	for _, id := range entities {
-		pos, posOk := ecs.GetPosition(id)
-		renderable, renderableOk := ecs.GetRenderable(id)
-		if !posOk {
-			slog.Error("Entity has Position component but no position", "entityId", id)
-		}
-		if !renderableOk {
-			slog.Error("Entity has Renderable component but no renderable", "entityId", id)
-		}
-
-		result = append(result, PositionedRenderableEntity{
-			ID:         id,
-			Position:   pos,
-			Renderable: renderable,
+	var result []PositionedRenderableEntity
+	NewQuery2[gruid.Point, components.Renderable](ecs, components.CPosition, components.CRenderable).
+		Each(func(id EntityID, pos *gruid.Point, renderable *components.Renderable) {
+			result = append(result, PositionedRenderableEntity{ID: id, Position: *pos, Renderable: *renderable})
 		})
-	}
-
 	return result
 }
 
@@ -138,27 +139,11 @@ type PositionedFOVEntity struct {
 
 // GetEntitiesWithPositionAndFOV queries entities having both Position and FOV components.
 func (ecs *ECS) GetEntitiesWithPositionAndFOV() []PositionedFOVEntity {
-	entities := ecs.GetEntitiesWithComponents(components.CPosition, components.CFOV)
-	result := make([]PositionedFOVEntity, 0, len(entities))
-
-	for _, id := range entities {
-		pos, posOk := ecs.GetPosition(id)
-		fov, fovOk := ecs.GetFOV(id)
-
-		if !posOk {
-			slog.Error("Entity has Position component but no position", "entityId", id)
-		}
-		if !fovOk {
-			slog.Error("Entity has FOV component but no FOV", "entityId", id)
-		}
-
-		result = append(result, PositionedFOVEntity{
-			ID:       id,
-			Position: pos,
-			FOV:      fov,
+	var result []PositionedFOVEntity
+	NewQuery2[gruid.Point, *components.FOV](ecs, components.CPosition, components.CFOV).
+		Each(func(id EntityID, pos *gruid.Point, fov **components.FOV) {
+			result = append(result, PositionedFOVEntity{ID: id, Position: *pos, FOV: *fov})
 		})
-	}
-
 	return result
 }
 
@@ -208,6 +193,11 @@ func (ecs *ECS) GetItemPickup(id EntityID) (components.ItemPickup, bool) {
 	return GetComponentTyped[components.ItemPickup](ecs, id, components.CItemPickup)
 }
 
+// GetEquipped returns the Equipped component for an entity.
+func (ecs *ECS) GetEquipped(id EntityID) (components.Equipped, bool) {
+	return GetComponentTyped[components.Equipped](ecs, id, components.CEquipped)
+}
+
 // GetAIComponent returns the AIComponent for an entity.
 func (ecs *ECS) GetAIComponent(id EntityID) (components.AIComponent, bool) {
 	return GetComponentTyped[components.AIComponent](ecs, id, components.CAIComponent)
@@ -248,6 +238,21 @@ func (ecs *ECS) GetStatusEffects(id EntityID) (components.StatusEffects, bool) {
 	return GetComponentTyped[components.StatusEffects](ecs, id, components.CStatusEffects)
 }
 
+// GetSpellbook returns the Spellbook component for an entity.
+func (ecs *ECS) GetSpellbook(id EntityID) (components.Spellbook, bool) {
+	return GetComponentTyped[components.Spellbook](ecs, id, components.CSpellbook)
+}
+
+// GetSummoned returns the Summoned component for an entity.
+func (ecs *ECS) GetSummoned(id EntityID) (components.Summoned, bool) {
+	return GetComponentTyped[components.Summoned](ecs, id, components.CSummoned)
+}
+
+// GetReward returns the Reward component for an entity.
+func (ecs *ECS) GetReward(id EntityID) (components.Reward, bool) {
+	return GetComponentTyped[components.Reward](ecs, id, components.CReward)
+}
+
 // GetPlayerTag returns the PlayerTag component for an entity.
 func (ecs *ECS) GetPlayerTag(id EntityID) (components.PlayerTag, bool) {
 	return GetComponentTyped[components.PlayerTag](ecs, id, components.CPlayerTag)
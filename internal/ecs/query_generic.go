@@ -0,0 +1,141 @@
+package ecs
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// queryFilter narrows a query to entities that also have, or lack, some
+// components.
+type queryFilter struct {
+	with    []components.ComponentType
+	without []components.ComponentType
+}
+
+// Query2 iterates over the entities having components A and B. Each hands
+// out pointers into the component storage, so systems can mutate components
+// in place instead of copying them and calling AddComponent.
+//
+// The pointers stay valid until components of the queried types are added
+// to or removed from other entities; queries must run on the game goroutine.
+type Query2[A, B any] struct {
+	ecs   *ECS
+	types [2]components.ComponentType
+	queryFilter
+}
+
+// NewQuery2 creates a query over two component types. It panics if a type
+// is not stored as the matching type parameter.
+func NewQuery2[A, B any](ecs *ECS, a, b components.ComponentType) *Query2[A, B] {
+	mustStoreAs[A](a)
+	mustStoreAs[B](b)
+	return &Query2[A, B]{ecs: ecs, types: [2]components.ComponentType{a, b}}
+}
+
+// With restricts the query to entities that also have the given components.
+func (q *Query2[A, B]) With(compTypes ...components.ComponentType) *Query2[A, B] {
+	q.with = append(q.with, compTypes...)
+	return q
+}
+
+// Without excludes entities having any of the given components.
+func (q *Query2[A, B]) Without(compTypes ...components.ComponentType) *Query2[A, B] {
+	q.without = append(q.without, compTypes...)
+	return q
+}
+
+// Each calls fn for every matching entity. The ECS lock is not held while fn
+// runs, so fn may use the rest of the ECS API.
+func (q *Query2[A, B]) Each(fn func(id EntityID, a *A, b *B)) {
+	for _, id := range q.ecs.queryEntities(q.types[:], q.queryFilter) {
+		q.ecs.mu.RLock()
+		a := componentPtr[A](q.ecs, q.types[0], id)
+		b := componentPtr[B](q.ecs, q.types[1], id)
+		q.ecs.mu.RUnlock()
+
+		// An earlier callback may have removed a component
+		if a != nil && b != nil {
+			fn(id, a, b)
+		}
+	}
+}
+
+// Query3 iterates over the entities having components A, B and C. See
+// Query2 for how long the handed out pointers stay valid.
+type Query3[A, B, C any] struct {
+	ecs   *ECS
+	types [3]components.ComponentType
+	queryFilter
+}
+
+// NewQuery3 creates a query over three component types. It panics if a type
+// is not stored as the matching type parameter.
+func NewQuery3[A, B, C any](ecs *ECS, a, b, c components.ComponentType) *Query3[A, B, C] {
+	mustStoreAs[A](a)
+	mustStoreAs[B](b)
+	mustStoreAs[C](c)
+	return &Query3[A, B, C]{ecs: ecs, types: [3]components.ComponentType{a, b, c}}
+}
+
+// With restricts the query to entities that also have the given components.
+func (q *Query3[A, B, C]) With(compTypes ...components.ComponentType) *Query3[A, B, C] {
+	q.with = append(q.with, compTypes...)
+	return q
+}
+
+// Without excludes entities having any of the given components.
+func (q *Query3[A, B, C]) Without(compTypes ...components.ComponentType) *Query3[A, B, C] {
+	q.without = append(q.without, compTypes...)
+	return q
+}
+
+// Each calls fn for every matching entity. The ECS lock is not held while fn
+// runs, so fn may use the rest of the ECS API.
+func (q *Query3[A, B, C]) Each(fn func(id EntityID, a *A, b *B, c *C)) {
+	for _, id := range q.ecs.queryEntities(q.types[:], q.queryFilter) {
+		q.ecs.mu.RLock()
+		a := componentPtr[A](q.ecs, q.types[0], id)
+		b := componentPtr[B](q.ecs, q.types[1], id)
+		c := componentPtr[C](q.ecs, q.types[2], id)
+		q.ecs.mu.RUnlock()
+
+		if a != nil && b != nil && c != nil {
+			fn(id, a, b, c)
+		}
+	}
+}
+
+// queryEntities returns the entities having every component of compTypes
+// and filter.with, and none of filter.without.
+func (ecs *ECS) queryEntities(compTypes []components.ComponentType, filter queryFilter) []EntityID {
+	ecs.mu.RLock()
+	defer ecs.mu.RUnlock()
+
+	ids := ecs.entitiesWith(slices.Concat(compTypes, filter.with))
+	return slices.DeleteFunc(ids, func(id EntityID) bool {
+		return slices.ContainsFunc(filter.without, func(ct components.ComponentType) bool {
+			return ecs.hasComponent(id, ct)
+		})
+	})
+}
+
+// componentPtr returns a pointer to the entity's component in its typed
+// store, or nil if it has none. The caller holds the lock.
+func componentPtr[T any](ecs *ECS, compType components.ComponentType, id EntityID) *T {
+	store, ok := ecs.stores[compType].(*sparseSet[T])
+	if !ok {
+		return nil
+	}
+	return store.ptr(id)
+}
+
+// mustStoreAs panics if components of compType are not stored as T, which
+// would make every query over it come back empty.
+func mustStoreAs[T any](compType components.ComponentType) {
+	if _, ok := newStore(compType).(*sparseSet[T]); !ok {
+		var zero T
+		panic(fmt.Sprintf("ecs: component %s is not stored as %T", compType, zero))
+	}
+}
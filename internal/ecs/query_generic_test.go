@@ -0,0 +1,118 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// newQueryWorld creates a player, two monsters (one a corpse) and an item,
+// all positioned and with health except the item
+func newQueryWorld() (world *ECS, player, monster, corpse, item EntityID) {
+	world = NewECS()
+	player, monster, corpse, item = world.AddEntity(), world.AddEntity(), world.AddEntity(), world.AddEntity()
+	for i, id := range []EntityID{player, monster, corpse} {
+		world.AddComponents(id, gruid.Point{X: i}, components.NewHealth(10), components.Renderable{Glyph: 'x'})
+	}
+	world.AddComponents(player, components.PlayerTag{})
+	world.AddComponents(monster, components.AITag{})
+	world.AddComponents(corpse, components.AITag{}, components.CorpseTag{})
+	world.AddComponents(item, gruid.Point{X: 9}, components.Renderable{Glyph: '!'})
+	return world, player, monster, corpse, item
+}
+
+func collect2[A, B any](q *Query2[A, B]) []EntityID {
+	var ids []EntityID
+	q.Each(func(id EntityID, _ *A, _ *B) { ids = append(ids, id) })
+	slices.Sort(ids)
+	return ids
+}
+
+func TestQuery2_Filters(t *testing.T) {
+	world, player, monster, corpse, _ := newQueryWorld()
+
+	newQuery := func() *Query2[gruid.Point, components.Health] {
+		return NewQuery2[gruid.Point, components.Health](world, components.CPosition, components.CHealth)
+	}
+
+	if got := collect2(newQuery()); !slices.Equal(got, []EntityID{player, monster, corpse}) {
+		t.Errorf("Unfiltered query got %v", got)
+	}
+	if got := collect2(newQuery().With(components.CAITag)); !slices.Equal(got, []EntityID{monster, corpse}) {
+		t.Errorf("With(AITag) got %v", got)
+	}
+	if got := collect2(newQuery().With(components.CAITag).Without(components.CCorpseTag)); !slices.Equal(got, []EntityID{monster}) {
+		t.Errorf("With(AITag).Without(CorpseTag) got %v", got)
+	}
+}
+
+func TestQuery2_MutatesInPlace(t *testing.T) {
+	world, player, monster, _, _ := newQueryWorld()
+
+	NewQuery2[gruid.Point, components.Health](world, components.CPosition, components.CHealth).
+		Without(components.CCorpseTag).
+		Each(func(id EntityID, pos *gruid.Point, health *components.Health) {
+			pos.Y = 5
+			health.CurrentHP -= 3
+		})
+
+	for _, id := range []EntityID{player, monster} {
+		if pos := world.GetPositionSafe(id); pos.Y != 5 {
+			t.Errorf("Entity %d: expected the position change to persist, got %v", id, pos)
+		}
+		if hp := world.GetHealthSafe(id).CurrentHP; hp != 7 {
+			t.Errorf("Entity %d: expected 7 HP, got %d", id, hp)
+		}
+	}
+}
+
+func TestQuery2_CallbackCanUseECS(t *testing.T) {
+	world, player, _, _, _ := newQueryWorld()
+
+	// The player comes first and strips the others' health, so they are
+	// skipped instead of handing out stale pointers
+	visited := 0
+	NewQuery2[gruid.Point, components.Health](world, components.CPosition, components.CHealth).
+		Each(func(id EntityID, _ *gruid.Point, _ *components.Health) {
+			visited++
+			for _, other := range world.GetEntitiesWithComponent(components.CHealth) {
+				if other != id && other != player {
+					world.RemoveComponent(other, components.CHealth)
+				}
+			}
+		})
+	if visited != 1 {
+		t.Errorf("Expected only the player to be visited, got %d", visited)
+	}
+}
+
+func TestQuery3(t *testing.T) {
+	world, _, monster, _, _ := newQueryWorld()
+
+	var ids []EntityID
+	NewQuery3[gruid.Point, components.Health, components.Renderable](world, components.CPosition, components.CHealth, components.CRenderable).
+		With(components.CAITag).
+		Without(components.CCorpseTag).
+		Each(func(id EntityID, _ *gruid.Point, _ *components.Health, r *components.Renderable) {
+			r.Glyph = 'M'
+			ids = append(ids, id)
+		})
+
+	if !slices.Equal(ids, []EntityID{monster}) {
+		t.Errorf("Expected only the monster, got %v", ids)
+	}
+	if glyph := world.GetRenderableSafe(monster).Glyph; glyph != 'M' {
+		t.Errorf("Expected the glyph change to persist, got %q", glyph)
+	}
+}
+
+func TestNewQuery2_PanicsOnTypeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for a component stored under another type")
+		}
+	}()
+	NewQuery2[components.Health, gruid.Point](NewECS(), components.CPosition, components.CHealth)
+}
@@ -0,0 +1,96 @@
+package ecs
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// SetParent makes child part of parent, e.g. an item carried by a creature.
+// Children are removed and transferred along with their parent. It fails
+// if either entity does not exist or if parent is child or one of its
+// descendants.
+func (ecs *ECS) SetParent(child, parent EntityID) error {
+	ecs.mu.Lock()
+	defer ecs.unlock()
+
+	if !ecs.entityExists(child) {
+		return fmt.Errorf("entity %d does not exist", child)
+	}
+	if !ecs.entityExists(parent) {
+		return fmt.Errorf("parent %d does not exist", parent)
+	}
+	for ancestor := parent; ancestor != 0; ancestor = ecs.parent(ancestor) {
+		if ancestor == child {
+			return fmt.Errorf("entity %d cannot be its own ancestor", child)
+		}
+	}
+
+	ecs.addComponent(child, components.CParent, components.Parent{ID: int(parent)})
+	return nil
+}
+
+// ClearParent detaches an entity from its parent, if it has one.
+func (ecs *ECS) ClearParent(child EntityID) {
+	ecs.RemoveComponent(child, components.CParent)
+}
+
+// Parent returns the entity's parent, or false if it has none.
+func (ecs *ECS) Parent(child EntityID) (EntityID, bool) {
+	ecs.mu.RLock()
+	defer ecs.mu.RUnlock()
+
+	parent := ecs.parent(child)
+	return parent, parent != 0
+}
+
+// Children returns the entities whose parent is the given one, in ascending
+// order.
+func (ecs *ECS) Children(parent EntityID) []EntityID {
+	ecs.mu.RLock()
+	defer ecs.mu.RUnlock()
+
+	return ecs.children(parent)
+}
+
+// parent returns the entity's parent, or 0. The caller holds the lock.
+func (ecs *ECS) parent(child EntityID) EntityID {
+	store, ok := ecs.stores[components.CParent]
+	if !ok {
+		return 0
+	}
+	comp, ok := store.get(child)
+	if !ok {
+		return 0
+	}
+	return EntityID(comp.(components.Parent).ID)
+}
+
+// children returns the entity's children, sorted. The caller holds the lock.
+func (ecs *ECS) children(parent EntityID) []EntityID {
+	store, ok := ecs.stores[components.CParent]
+	if !ok {
+		return nil
+	}
+
+	var children []EntityID
+	for _, id := range store.entities() {
+		if comp, _ := store.get(id); EntityID(comp.(components.Parent).ID) == parent {
+			children = append(children, id)
+		}
+	}
+	slices.Sort(children)
+	return children
+}
+
+// descendants returns the entity's children, their children and so on,
+// each before its own children. The caller holds the lock.
+func (ecs *ECS) descendants(parent EntityID) []EntityID {
+	var all []EntityID
+	for _, child := range ecs.children(parent) {
+		all = append(all, child)
+		all = append(all, ecs.descendants(child)...)
+	}
+	return all
+}
@@ -0,0 +1,105 @@
+package ecs
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func TestSetParent_TracksChildren(t *testing.T) {
+	world := NewECS()
+	chest, sword, gem := world.AddEntity(), world.AddEntity(), world.AddEntity()
+
+	if err := world.SetParent(gem, chest); err != nil {
+		t.Fatal(err)
+	}
+	if err := world.SetParent(sword, chest); err != nil {
+		t.Fatal(err)
+	}
+	if got := world.Children(chest); !slices.Equal(got, []EntityID{sword, gem}) {
+		t.Errorf("Expected children %v, got %v", []EntityID{sword, gem}, got)
+	}
+	if parent, ok := world.Parent(sword); !ok || parent != chest {
+		t.Errorf("Expected the sword's parent to be %d, got %d", chest, parent)
+	}
+
+	world.ClearParent(sword)
+	if _, ok := world.Parent(sword); ok {
+		t.Error("Expected the sword to have no parent")
+	}
+	if got := world.Children(chest); !slices.Equal(got, []EntityID{gem}) {
+		t.Errorf("Expected only the gem left, got %v", got)
+	}
+}
+
+func TestSetParent_RejectsCyclesAndMissingEntities(t *testing.T) {
+	world := NewECS()
+	bag, pouch := world.AddEntity(), world.AddEntity()
+	if err := world.SetParent(pouch, bag); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := world.SetParent(bag, pouch); err == nil {
+		t.Error("Expected an error putting a bag inside its own content")
+	}
+	if err := world.SetParent(bag, bag); err == nil {
+		t.Error("Expected an error parenting an entity to itself")
+	}
+	gone := world.AddEntity()
+	world.RemoveEntity(gone)
+	if err := world.SetParent(bag, gone); err == nil {
+		t.Error("Expected an error parenting to a removed entity")
+	}
+	if _, ok := world.Parent(bag); ok {
+		t.Error("Expected rejected parents to leave the bag alone")
+	}
+}
+
+func TestRemoveEntity_RemovesDescendants(t *testing.T) {
+	world := NewECS()
+	var removed []EntityID
+	world.OnRemove(components.CName, func(id EntityID, _ any) { removed = append(removed, id) })
+
+	owner, bag, coin, bystander := world.AddEntity(), world.AddEntity(), world.AddEntity(), world.AddEntity()
+	for _, id := range []EntityID{owner, bag, coin, bystander} {
+		world.AddComponents(id, components.Name{Name: "thing"})
+	}
+	world.SetParent(bag, owner)
+	world.SetParent(coin, bag)
+
+	world.RemoveEntity(owner)
+
+	for _, id := range []EntityID{owner, bag, coin} {
+		if world.EntityExists(id) {
+			t.Errorf("Expected entity %d removed with its ancestor", id)
+		}
+	}
+	if !world.EntityExists(bystander) {
+		t.Error("Expected unrelated entities kept")
+	}
+	if !slices.Equal(removed, []EntityID{owner, bag, coin}) {
+		t.Errorf("Expected hooks to see every removal, got %v", removed)
+	}
+}
+
+func TestTransferEntity_MovesDescendants(t *testing.T) {
+	src, dst := NewECS(), NewECS()
+	owner, item := src.AddEntity(), src.AddEntity()
+	src.AddComponents(item, components.Name{Name: "Sword"})
+	src.SetParent(item, owner)
+
+	if err := src.TransferEntity(owner, dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if src.EntityExists(item) {
+		t.Error("Expected the child gone from the source")
+	}
+	if parent, ok := dst.Parent(item); !ok || parent != owner {
+		t.Errorf("Expected the child still under %d, got %d", owner, parent)
+	}
+	if name := dst.GetNameSafe(item); name != "Sword" {
+		t.Errorf("Expected the child's components moved, got name %q", name)
+	}
+}
@@ -110,6 +110,12 @@ func (ecs *ECS) GetStatusEffectsSafe(id EntityID) components.StatusEffects {
 	return comp
 }
 
+// GetSpellbookSafe returns the Spellbook component for an entity, or zero value if not found.
+func (ecs *ECS) GetSpellbookSafe(id EntityID) components.Spellbook {
+	comp, _ := ecs.GetSpellbook(id)
+	return comp
+}
+
 // GetPlayerTagSafe returns the PlayerTag component for an entity, or zero PlayerTag if not found.
 func (ecs *ECS) GetPlayerTagSafe(id EntityID) components.PlayerTag {
 	tag, _ := ecs.GetPlayerTag(id)
@@ -203,6 +209,21 @@ func (ecs *ECS) HasStatusEffectsSafe(id EntityID) bool {
 	return ecs.HasComponent(id, components.CStatusEffects)
 }
 
+// HasSpellbookSafe returns true if the entity has a Spellbook component.
+func (ecs *ECS) HasSpellbookSafe(id EntityID) bool {
+	return ecs.HasComponent(id, components.CSpellbook)
+}
+
+// HasSummonedSafe returns true if the entity is a summoned creature.
+func (ecs *ECS) HasSummonedSafe(id EntityID) bool {
+	return ecs.HasComponent(id, components.CSummoned)
+}
+
+// HasRewardSafe returns true if the entity yields a reward when killed.
+func (ecs *ECS) HasRewardSafe(id EntityID) bool {
+	return ecs.HasComponent(id, components.CReward)
+}
+
 // GetPathfindingComponentSafe returns the PathfindingComponent for an entity, or nil if not found.
 func (ecs *ECS) GetPathfindingComponentSafe(id EntityID) *components.PathfindingComponent {
 	comp, _ := ecs.GetPathfindingComponent(id)
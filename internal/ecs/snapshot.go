@@ -0,0 +1,244 @@
+package ecs
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// Snapshot is an immutable copy of the entities and components of an ECS.
+// Components are held encoded by components.MarshalComponent, so neither
+// later changes to the world nor changes to values read from the snapshot
+// show in it.
+type Snapshot struct {
+	entities map[EntityID]map[components.ComponentType]json.RawMessage
+	slots    []entitySlot
+	free     []uint32
+}
+
+// Snapshot copies every entity and component. It fails if a component type
+// is not registered in components.TypeToComponent.
+func (ecs *ECS) Snapshot() (*Snapshot, error) {
+	ecs.mu.RLock()
+	defer ecs.mu.RUnlock()
+
+	snap := &Snapshot{
+		entities: make(map[EntityID]map[components.ComponentType]json.RawMessage),
+		slots:    slices.Clone(ecs.slots),
+		free:     slices.Clone(ecs.free),
+	}
+	for index, slot := range ecs.slots {
+		if slot.state == slotAlive {
+			snap.entities[NewEntityID(uint32(index), slot.generation)] = make(map[components.ComponentType]json.RawMessage)
+		}
+	}
+	for compType, store := range ecs.stores {
+		for _, id := range store.entities() {
+			comp, _ := store.get(id)
+			data, err := components.MarshalComponent(compType, comp)
+			if err != nil {
+				return nil, fmt.Errorf("entity %d: %w", id, err)
+			}
+			if comps, ok := snap.entities[id]; ok {
+				comps[compType] = data
+			}
+		}
+	}
+	return snap, nil
+}
+
+// Entities returns the IDs of the snapshot's entities in ascending order.
+func (s *Snapshot) Entities() []EntityID {
+	return slices.Sorted(maps.Keys(s.entities))
+}
+
+// Has reports whether the entity existed when the snapshot was taken.
+func (s *Snapshot) Has(id EntityID) bool {
+	_, ok := s.entities[id]
+	return ok
+}
+
+// ComponentTypes returns the types of the entity's components, sorted.
+func (s *Snapshot) ComponentTypes(id EntityID) []components.ComponentType {
+	return slices.Sorted(maps.Keys(s.entities[id]))
+}
+
+// Component returns a fresh copy of one of the entity's components.
+func (s *Snapshot) Component(id EntityID, compType components.ComponentType) (any, bool) {
+	data, ok := s.entities[id][compType]
+	if !ok {
+		return nil, false
+	}
+	comp, err := components.UnmarshalComponent(compType, data)
+	return comp, err == nil
+}
+
+// ChangeKind tells how an entity or component differs between snapshots
+type ChangeKind uint8
+
+const (
+	ChangeAdded ChangeKind = iota
+	ChangeRemoved
+	ChangeModified
+)
+
+// String returns the name of the change kind
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	default:
+		return "unknown"
+	}
+}
+
+// Change is one difference between two snapshots. An entity added or
+// removed as a whole gets a change with an empty Component, followed by one
+// change per component it had.
+type Change struct {
+	Kind      ChangeKind
+	Entity    EntityID
+	Component components.ComponentType // Empty for the entity itself
+	Before    any                      // Component value in the first snapshot, nil if absent
+	After     any                      // Component value in the second snapshot, nil if absent
+}
+
+// String describes the change, e.g. "entity 4: Health modified"
+func (c Change) String() string {
+	if c.Component == "" {
+		return fmt.Sprintf("entity %d %s", c.Entity, c.Kind)
+	}
+	return fmt.Sprintf("entity %d: %s %s", c.Entity, c.Component, c.Kind)
+}
+
+// Diff lists what changed from snapshot a to snapshot b, ordered by entity
+// then component type.
+func Diff(a, b *Snapshot) []Change {
+	ids := slices.Sorted(maps.Keys(a.entities))
+	for id := range b.entities {
+		if !a.Has(id) {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	var changes []Change
+	for _, id := range ids {
+		before, inA := a.entities[id]
+		after, inB := b.entities[id]
+		switch {
+		case !inA:
+			changes = append(changes, Change{Kind: ChangeAdded, Entity: id})
+		case !inB:
+			changes = append(changes, Change{Kind: ChangeRemoved, Entity: id})
+		}
+
+		compTypes := slices.Collect(maps.Keys(before))
+		for compType := range after {
+			if _, ok := before[compType]; !ok {
+				compTypes = append(compTypes, compType)
+			}
+		}
+		slices.Sort(compTypes)
+
+		for _, compType := range compTypes {
+			old, hadOld := before[compType]
+			cur, hasNew := after[compType]
+			change := Change{Entity: id, Component: compType}
+			switch {
+			case !hadOld:
+				change.Kind = ChangeAdded
+			case !hasNew:
+				change.Kind = ChangeRemoved
+			case !bytes.Equal(old, cur):
+				change.Kind = ChangeModified
+			default:
+				continue
+			}
+			if hadOld {
+				change.Before, _ = components.UnmarshalComponent(compType, old)
+			}
+			if hasNew {
+				change.After, _ = components.UnmarshalComponent(compType, cur)
+			}
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// Restore brings the world back to a snapshot. Only what differs is
+// touched: entities created since are removed, removed ones come back under
+// their IDs, and changed components get their snapshot values, with
+// component hooks seeing each of these changes. Handles to entities created
+// after the snapshot stay stale.
+func (ecs *ECS) Restore(snap *Snapshot) error {
+	current, err := ecs.Snapshot()
+	if err != nil {
+		return err
+	}
+	changes := Diff(current, snap)
+
+	// Decoding is the only step that can fail, so check it before changing
+	// anything
+	for _, change := range changes {
+		if change.Component != "" && change.Kind != ChangeRemoved && change.After == nil {
+			return fmt.Errorf("entity %d: failed to decode %s", change.Entity, change.Component)
+		}
+	}
+
+	ecs.mu.Lock()
+	defer ecs.unlock()
+
+	// Removals first, so hooks never see an entity with a mix of old and
+	// restored components
+	for _, change := range changes {
+		if change.Kind == ChangeRemoved && change.Component != "" {
+			ecs.removeComponent(change.Entity, change.Component)
+		}
+	}
+	ecs.restoreSlots(snap)
+	for _, change := range changes {
+		if change.Component != "" && change.Kind != ChangeRemoved {
+			ecs.addComponent(change.Entity, change.Component, change.After)
+		}
+	}
+	return nil
+}
+
+// restoreSlots sets the slots to the snapshot's. Slots free in the snapshot
+// keep the highest generation seen, bumped if they are in use now, so that
+// no handle given out since the snapshot becomes valid again. The caller
+// holds the lock.
+func (ecs *ECS) restoreSlots(snap *Snapshot) {
+	slots := slices.Clone(snap.slots)
+	var extra []uint32 // Slots created since the snapshot, reused last
+	for index := range max(len(slots), len(ecs.slots)) {
+		if index >= len(slots) {
+			slots = append(slots, entitySlot{state: slotFree})
+			extra = append(extra, uint32(index))
+		}
+		if slots[index].state != slotFree || index >= len(ecs.slots) {
+			continue
+		}
+		cur := ecs.slots[index]
+		generation := cur.generation
+		if cur.state != slotFree {
+			generation = (generation + 1) & generationMask
+		}
+		slots[index].generation = max(slots[index].generation, generation)
+	}
+
+	slices.SortFunc(extra, func(a, b uint32) int { return cmp.Compare(b, a) })
+	ecs.slots = slots
+	ecs.free = append(extra, snap.free...)
+}
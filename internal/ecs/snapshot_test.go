@@ -0,0 +1,125 @@
+package ecs
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// mustSnapshot takes a snapshot, failing the test on error
+func mustSnapshot(t *testing.T, world *ECS) *Snapshot {
+	t.Helper()
+	snap, err := world.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snap
+}
+
+// changeStrings describes changes for comparing them in tests
+func changeStrings(changes []Change) []string {
+	var out []string
+	for _, change := range changes {
+		out = append(out, change.String())
+	}
+	return out
+}
+
+func TestSnapshot_IsImmutable(t *testing.T) {
+	world := NewECS()
+	id := world.AddEntity()
+	world.AddComponents(id, components.NewHealth(10), components.NewSpellbook("bolt"))
+	snap := mustSnapshot(t, world)
+
+	// Neither the world nor values read from the snapshot reach it
+	world.AddComponent(id, components.CHealth, components.Health{CurrentHP: 1, MaxHP: 10})
+	book, _ := snap.Component(id, components.CSpellbook)
+	spellbook := book.(components.Spellbook)
+	spellbook.Spells[0] = "changed"
+
+	health, _ := snap.Component(id, components.CHealth)
+	if health.(components.Health).CurrentHP != 10 {
+		t.Errorf("Expected the snapshot to keep 10 HP, got %+v", health)
+	}
+	book, _ = snap.Component(id, components.CSpellbook)
+	if got := book.(components.Spellbook).Spells; !slices.Equal(got, []string{"bolt"}) {
+		t.Errorf("Expected the snapshot's spellbook unchanged, got %v", got)
+	}
+	if !slices.Equal(snap.ComponentTypes(id), []components.ComponentType{components.CHealth, components.CSpellbook}) {
+		t.Errorf("Unexpected component types %v", snap.ComponentTypes(id))
+	}
+}
+
+func TestDiff_ListsExactChanges(t *testing.T) {
+	world := NewECS()
+	attacker, target, bystander := world.AddEntity(), world.AddEntity(), world.AddEntity()
+	for _, id := range []EntityID{attacker, target, bystander} {
+		world.AddComponents(id, gruid.Point{X: int(id.Index())}, components.NewHealth(10))
+	}
+	before := mustSnapshot(t, world)
+
+	if diff := Diff(before, mustSnapshot(t, world)); len(diff) != 0 {
+		t.Fatalf("Expected no changes without mutations, got %v", changeStrings(diff))
+	}
+
+	world.AddComponent(target, components.CHealth, components.Health{CurrentHP: 4, MaxHP: 10})
+	world.RemoveEntity(bystander)
+	spawned := world.AddEntity()
+	world.AddComponent(spawned, components.CCorpseTag, components.CorpseTag{})
+	changes := Diff(before, mustSnapshot(t, world))
+
+	want := []string{
+		fmt.Sprintf("entity %d: Health modified", target),
+		fmt.Sprintf("entity %d removed", bystander),
+		fmt.Sprintf("entity %d: Health removed", bystander),
+		fmt.Sprintf("entity %d: Position removed", bystander),
+		fmt.Sprintf("entity %d added", spawned),
+		fmt.Sprintf("entity %d: CorpseTag added", spawned),
+	}
+	if got := changeStrings(changes); !slices.Equal(got, want) {
+		t.Fatalf("Expected changes %v, got %v", want, got)
+	}
+	if changes[0].Before.(components.Health).CurrentHP != 10 || changes[0].After.(components.Health).CurrentHP != 4 {
+		t.Errorf("Expected before and after values, got %+v", changes[0])
+	}
+}
+
+func TestRestore_RewindsWorldAndKeepsHandlesStale(t *testing.T) {
+	world := NewECS()
+	var removed []EntityID
+	world.OnRemove(components.CPosition, func(id EntityID, _ any) { removed = append(removed, id) })
+
+	kept, doomed := world.AddEntity(), world.AddEntity()
+	world.AddComponents(kept, gruid.Point{X: 1}, components.NewHealth(10))
+	world.AddComponents(doomed, gruid.Point{X: 2}, components.NewFOVComponent(4, 8, 8))
+	snap := mustSnapshot(t, world)
+
+	world.AddComponent(kept, components.CHealth, components.Health{CurrentHP: 2, MaxHP: 10})
+	world.RemoveEntity(doomed)
+	later := world.AddEntity()
+	world.AddComponents(later, gruid.Point{X: 3})
+
+	removed = nil
+	if err := world.Restore(snap); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := Diff(snap, mustSnapshot(t, world)); len(diff) != 0 {
+		t.Errorf("Expected the world to match the snapshot, got %v", changeStrings(diff))
+	}
+	if !slices.Equal(removed, []EntityID{later}) {
+		t.Errorf("Expected hooks to see only the later entity's position removed, got %v", removed)
+	}
+	if fov := world.GetFOVSafe(doomed); fov == nil || fov.GetFOVCalculator() == nil {
+		t.Error("Expected the removed entity back with a working FOV")
+	}
+	if world.EntityExists(later) {
+		t.Error("Expected the entity created after the snapshot gone")
+	}
+	if reused := world.AddEntity(); reused == later {
+		t.Errorf("Expected a new entity to get a fresh ID, got the stale %d", reused)
+	}
+}
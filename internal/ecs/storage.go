@@ -0,0 +1,169 @@
+package ecs
+
+import (
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// componentStore holds every component of one type. Stores are not safe for
+// concurrent use; the ECS lock guards them.
+type componentStore interface {
+	get(id EntityID) (any, bool)
+	set(id EntityID, comp any) bool // False if comp has the wrong type
+	remove(id EntityID)
+	has(id EntityID) bool
+	len() int
+	entities() []EntityID // Dense entity IDs, owned by the store
+}
+
+// sparseSet stores components of type T contiguously. A sparse array maps
+// entity indices to indices in the dense arrays, so lookups are two slice
+// reads and iteration walks packed memory without boxing.
+type sparseSet[T any] struct {
+	sparse []int32    // Entity index -> dense index + 1, 0 when absent
+	ids    []EntityID // Entity owning each element of data, with its generation
+	data   []T
+}
+
+// newSparseSet creates an empty store for components of type T
+func newSparseSet[T any]() componentStore {
+	return &sparseSet[T]{}
+}
+
+// storeFactories creates the typed store for each known component type.
+// Types missing here fall back to a store of boxed values.
+var storeFactories = map[components.ComponentType]func() componentStore{
+	components.CAIComponent:          newSparseSet[components.AIComponent],
+	components.CAITag:                newSparseSet[components.AITag],
+	components.CBlocksMovement:       newSparseSet[components.BlocksMovement],
+	components.CCorpseTag:            newSparseSet[components.CorpseTag],
+	components.CEquipment:            newSparseSet[components.Equipment],
+	components.CFOV:                  newSparseSet[*components.FOV],
+	components.CHealth:               newSparseSet[components.Health],
+	components.CInventory:            newSparseSet[components.Inventory],
+	components.CItemPickup:           newSparseSet[components.ItemPickup],
+	components.CName:                 newSparseSet[components.Name],
+	components.CPlayerTag:            newSparseSet[components.PlayerTag],
+	components.CPosition:             newSparseSet[gruid.Point],
+	components.CRenderable:           newSparseSet[components.Renderable],
+	components.CStats:                newSparseSet[components.Stats],
+	components.CExperience:           newSparseSet[components.Experience],
+	components.CSkills:               newSparseSet[components.Skills],
+	components.CCombat:               newSparseSet[components.Combat],
+	components.CMana:                 newSparseSet[components.Mana],
+	components.CStamina:              newSparseSet[components.Stamina],
+	components.CStatusEffects:        newSparseSet[components.StatusEffects],
+	components.CTurnActor:            newSparseSet[components.TurnActor],
+	components.CPathfindingComponent: newSparseSet[components.PathfindingComponent],
+	components.CSpellbook:            newSparseSet[components.Spellbook],
+	components.CSummoned:             newSparseSet[components.Summoned],
+	components.CReward:               newSparseSet[components.Reward],
+	components.CParent:               newSparseSet[components.Parent],
+	components.CEquipped:             newSparseSet[components.Equipped],
+}
+
+// newStore creates the store for a component type
+func newStore(compType components.ComponentType) componentStore {
+	if factory, ok := storeFactories[compType]; ok {
+		return factory()
+	}
+	return newSparseSet[any]()
+}
+
+// index returns the dense index of the entity's component. Stale handles,
+// whose generation differs from the stored entity's, find nothing.
+func (s *sparseSet[T]) index(id EntityID) (int, bool) {
+	i, ok := s.slotIndex(id)
+	if !ok || s.ids[i] != id {
+		return 0, false
+	}
+	return i, true
+}
+
+// slotIndex returns the dense index used by the entity's index, whatever
+// the generation of the entity holding it
+func (s *sparseSet[T]) slotIndex(id EntityID) (int, bool) {
+	if id < 0 || int(id.Index()) >= len(s.sparse) {
+		return 0, false
+	}
+	i := s.sparse[id.Index()]
+	return int(i) - 1, i != 0
+}
+
+// ptr returns a pointer to the entity's component, valid until the store
+// is next modified
+func (s *sparseSet[T]) ptr(id EntityID) *T {
+	if i, ok := s.index(id); ok {
+		return &s.data[i]
+	}
+	return nil
+}
+
+// getTyped returns the entity's component without boxing it
+func (s *sparseSet[T]) getTyped(id EntityID) (T, bool) {
+	if i, ok := s.index(id); ok {
+		return s.data[i], true
+	}
+	var zero T
+	return zero, false
+}
+
+// put adds or replaces the entity's component
+func (s *sparseSet[T]) put(id EntityID, comp T) {
+	if i, ok := s.slotIndex(id); ok {
+		s.ids[i] = id
+		s.data[i] = comp
+		return
+	}
+	if n := int(id.Index()) + 1; n > len(s.sparse) {
+		s.sparse = append(s.sparse, make([]int32, n-len(s.sparse))...)
+	}
+	s.ids = append(s.ids, id)
+	s.data = append(s.data, comp)
+	s.sparse[id.Index()] = int32(len(s.data))
+}
+
+func (s *sparseSet[T]) get(id EntityID) (any, bool) {
+	return s.getTyped(id)
+}
+
+func (s *sparseSet[T]) set(id EntityID, comp any) bool {
+	typed, ok := comp.(T)
+	if !ok || id < 0 {
+		return false
+	}
+	s.put(id, typed)
+	return true
+}
+
+// remove swaps the last element into the removed slot to keep data packed
+func (s *sparseSet[T]) remove(id EntityID) {
+	i, ok := s.index(id)
+	if !ok {
+		return
+	}
+	last := len(s.data) - 1
+	if i != last {
+		s.data[i] = s.data[last]
+		s.ids[i] = s.ids[last]
+		s.sparse[s.ids[i].Index()] = int32(i + 1)
+	}
+	var zero T
+	s.data[last] = zero // Drop references held by the removed component
+	s.data = s.data[:last]
+	s.ids = s.ids[:last]
+	s.sparse[id.Index()] = 0
+}
+
+func (s *sparseSet[T]) has(id EntityID) bool {
+	_, ok := s.index(id)
+	return ok
+}
+
+func (s *sparseSet[T]) len() int {
+	return len(s.data)
+}
+
+func (s *sparseSet[T]) entities() []EntityID {
+	return s.ids
+}
@@ -0,0 +1,187 @@
+package ecs
+
+import (
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func TestStoreFactories_CoverEveryComponentType(t *testing.T) {
+	for compType := range components.TypeToComponent {
+		if _, ok := storeFactories[compType]; !ok {
+			t.Errorf("No typed store for component type %s", compType)
+		}
+	}
+}
+
+func TestSparseSet_AddRemove(t *testing.T) {
+	s := &sparseSet[int]{}
+	for id := EntityID(1); id <= 5; id++ {
+		s.put(id, int(id)*10)
+	}
+
+	s.remove(2) // Swaps entity 5 into the freed slot
+	s.remove(9) // Absent, no-op
+
+	if s.has(2) || s.len() != 4 {
+		t.Fatalf("Expected entity 2 removed and 4 left, got len %d", s.len())
+	}
+	for _, id := range []EntityID{1, 3, 4, 5} {
+		if v, ok := s.getTyped(id); !ok || v != int(id)*10 {
+			t.Errorf("Entity %d: got %d, %v", id, v, ok)
+		}
+	}
+
+	s.put(5, 99)
+	if v, _ := s.getTyped(5); v != 99 || s.len() != 4 {
+		t.Errorf("Replacing a component should not grow the store, got %d (len %d)", v, s.len())
+	}
+	if s.set(6, "not an int") {
+		t.Error("Expected a value of the wrong type to be rejected")
+	}
+}
+
+func TestECS_QueriesAfterRemoval(t *testing.T) {
+	world := NewECS()
+	var ids []EntityID
+	for i := range 4 {
+		id := world.AddEntity()
+		world.AddComponents(id, gruid.Point{X: i, Y: 0}, components.Renderable{Glyph: 'x'})
+		ids = append(ids, id)
+	}
+	world.AddComponent(ids[3], components.CAITag, components.AITag{})
+	world.RemoveEntity(ids[0])
+	world.RemoveComponent(ids[1], components.CRenderable)
+
+	if got := len(world.GetEntitiesWithPositionAndRenderable()); got != 2 {
+		t.Errorf("Expected 2 positioned renderables, got %d", got)
+	}
+	if got := world.GetEntitiesWithComponents(components.CPosition, components.CAITag); len(got) != 1 || got[0] != ids[3] {
+		t.Errorf("Expected only entity %d, got %v", ids[3], got)
+	}
+	if got := world.EntitiesAt(gruid.Point{X: 2, Y: 0}); len(got) != 1 || got[0] != ids[2] {
+		t.Errorf("Expected entity %d at (2,0), got %v", ids[2], got)
+	}
+	if pos, ok := world.GetPosition(ids[3]); !ok || pos.X != 3 {
+		t.Errorf("Expected entity %d at x=3, got %v, %v", ids[3], pos, ok)
+	}
+}
+
+// --- Benchmarks ---
+
+// benchEntities is the world size used by the benchmarks
+const benchEntities = 4000
+
+// mapStore reproduces the previous storage layout, one map of boxed values
+// per component type, so the benchmarks have a baseline.
+type mapStore map[components.ComponentType]map[EntityID]any
+
+func (m mapStore) add(id EntityID, compType components.ComponentType, comp any) {
+	if m[compType] == nil {
+		m[compType] = make(map[EntityID]any)
+	}
+	m[compType][id] = comp
+}
+
+// newBenchWorld fills both storages with the same entities: all positioned,
+// half renderable and one in ten with a FOV
+func newBenchWorld() (*ECS, mapStore) {
+	world := NewECS()
+	legacy := mapStore{}
+	for i := range benchEntities {
+		id := world.AddEntity()
+		pos := gruid.Point{X: i % 80, Y: i / 80}
+		world.AddComponent(id, components.CPosition, pos)
+		legacy.add(id, components.CPosition, pos)
+		if i%2 == 0 {
+			r := components.Renderable{Glyph: 'o'}
+			world.AddComponent(id, components.CRenderable, r)
+			legacy.add(id, components.CRenderable, r)
+		}
+		if i%10 == 0 {
+			fov := &components.FOV{}
+			world.AddComponent(id, components.CFOV, fov)
+			legacy.add(id, components.CFOV, fov)
+		}
+	}
+	return world, legacy
+}
+
+func BenchmarkIterPositionRenderable(b *testing.B) {
+	world, legacy := newBenchWorld()
+
+	b.Run("sparse", func(b *testing.B) {
+		for range b.N {
+			sum := 0
+			for _, e := range world.GetEntitiesWithPositionAndRenderable() {
+				sum += e.Position.X + int(e.Renderable.Glyph)
+			}
+			sinkInt = sum
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		for range b.N {
+			sum := 0
+			for id := range legacy[components.CPosition] {
+				comp, ok := legacy[components.CRenderable][id]
+				if !ok {
+					continue
+				}
+				sum += legacy[components.CPosition][id].(gruid.Point).X + int(comp.(components.Renderable).Glyph)
+			}
+			sinkInt = sum
+		}
+	})
+}
+
+func BenchmarkIterPositionFOV(b *testing.B) {
+	world, legacy := newBenchWorld()
+
+	b.Run("sparse", func(b *testing.B) {
+		for range b.N {
+			sum := 0
+			for _, e := range world.GetEntitiesWithPositionAndFOV() {
+				if e.FOV != nil {
+					sum += e.Position.Y
+				}
+			}
+			sinkInt = sum
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		for range b.N {
+			sum := 0
+			for id := range legacy[components.CPosition] {
+				comp, ok := legacy[components.CFOV][id]
+				if !ok {
+					continue
+				}
+				if comp.(*components.FOV) != nil {
+					sum += legacy[components.CPosition][id].(gruid.Point).Y
+				}
+			}
+			sinkInt = sum
+		}
+	})
+}
+
+func BenchmarkGetComponent(b *testing.B) {
+	world, legacy := newBenchWorld()
+
+	b.Run("sparse", func(b *testing.B) {
+		for i := range b.N {
+			pos, _ := world.GetPosition(EntityID(i%benchEntities + 1))
+			sinkInt = pos.X
+		}
+	})
+	b.Run("map", func(b *testing.B) {
+		for i := range b.N {
+			pos, _ := legacy[components.CPosition][EntityID(i%benchEntities+1)].(gruid.Point)
+			sinkInt = pos.X
+		}
+	})
+}
+
+// sinkInt keeps benchmark results alive
+var sinkInt int
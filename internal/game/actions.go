@@ -1,6 +1,7 @@
 package game
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -30,7 +31,24 @@ type MoveAction struct {
 
 // Execute performs the move action, returning the time cost and any error.
 func (a MoveAction) Execute(g *Game) (cost uint, err error) {
+	a.Direction = g.confusedDirection(a.EntityID, a.Direction)
+
+	// Walking into a closed door or a container opens it, taking the
+	// move's turn. The rest of a queued path goes through the open door.
+	target := g.ecs.GetPositionSafe(a.EntityID).Add(a.Direction)
+	if g.dungeon.InBounds(target) {
+		if cell := g.dungeon.Grid.At(target); cell == DoorClosedCell || cell == DoorLockedCell {
+			return OpenDoorAction{EntityID: a.EntityID, Pos: target}.Execute(g)
+		}
+	}
+	if container, ok := g.containerAt(target); ok && g.ecs.HasInventorySafe(a.EntityID) {
+		return LootAction{EntityID: a.EntityID, ContainerID: container}.Execute(g)
+	}
+
 	again, err := g.EntityBump(a.EntityID, a.Direction)
+	if errors.Is(err, errBlocked) && a.EntityID != g.PlayerID {
+		return 100, nil // Waits for the way to clear
+	}
 	if err != nil {
 		return 0, err // No cost if error occurred
 	}
@@ -51,54 +69,16 @@ type AttackAction struct {
 
 // Execute performs the attack action.
 func (a AttackAction) Execute(g *Game) (cost uint, err error) {
-	// Use safe accessors - no error handling needed!
-	attackerName := g.ecs.GetNameSafe(a.AttackerID)
-	targetName := g.ecs.GetNameSafe(a.TargetID)
-
-	// Use optional pattern for explicit null handling of health
-	targetHealthOpt := g.ecs.GetHealthOpt(a.TargetID)
-	if targetHealthOpt.IsNone() {
+	if !g.ecs.HasHealthSafe(a.TargetID) {
 		// Target might have died between action queuing and execution
-		slog.Debug("Attacker tries to attack target, but target has no health component", "attacker", attackerName, "attackerId", a.AttackerID, "target", targetName, "targetId", a.TargetID)
+		slog.Debug("Attacker tries to attack target, but target has no health component", "attacker", g.ecs.GetNameSafe(a.AttackerID), "attackerId", a.AttackerID, "target", g.ecs.GetNameSafe(a.TargetID), "targetId", a.TargetID)
 		return 0, fmt.Errorf("target %d has no health", a.TargetID)
 	}
 
-	targetHealth := targetHealthOpt.Unwrap()
-
-	// --- Basic Damage Calculation ---
-	damage := 1 // Simple fixed damage for now
-	targetHealth.CurrentHP -= damage
-
-	// Track damage statistics
-	if a.AttackerID == g.PlayerID {
-		g.AddDamageDealt(damage)
-	} else if a.TargetID == g.PlayerID {
-		g.AddDamageTaken(damage)
-	}
-
-	// Determine message color based on who is attacking
-	var msgColor gruid.Color
-	if a.AttackerID == g.PlayerID {
-		msgColor = ui.ColorPlayerAttack // Define in ui/color.go
-	} else if a.TargetID == g.PlayerID {
-		msgColor = ui.ColorEnemyAttack // Define in ui/color.go
-	} else {
-		msgColor = ui.ColorNeutralAttack // Define in ui/color.go
-	}
-	g.log.AddMessagef(msgColor, "%s attacks %s for %d damage.", attackerName, targetName, damage)
-
-	slog.Info("Combat action", "attacker", attackerName, "attackerId", a.AttackerID, "target", targetName, "targetId", a.TargetID, "damage", damage, "targetHP", targetHealth.CurrentHP, "targetMaxHP", targetHealth.MaxHP)
-	g.ecs.AddComponent(a.TargetID, components.CHealth, targetHealth)
-
-	// Trigger combat event
-	g.TriggerCombatEvent(a.AttackerID, a.TargetID, damage, false)
+	result := g.resolveMeleeAttack(a.AttackerID, a.TargetID)
+	g.applyAttack(a.AttackerID, a.TargetID, result, "attacks")
 
-	// Check for death (CurrentHP <= 0) and handle it
-	if targetHealth.IsDead() {
-		g.handleEntityDeath(a.TargetID, targetName, a.AttackerID)
-	}
-
-	return 100, nil // Standard attack cost
+	return 100, nil // Standard attack cost, whether or not it hits
 }
 
 // handleEntityDeath handles an entity's death, either removing it completely
@@ -129,25 +109,68 @@ func (g *Game) handleEntityDeath(entityID ecs.EntityID, entityName string, kille
 		expSystem.AwardExperience(killerID, xpReward)
 	}
 
-	// Turn entity into a corpse
-	g.ecs.RemoveComponents(entityID,
+	// Roll its loot before it becomes a corpse
+	pos := g.ecs.GetPositionSafe(entityID)
+	g.dropLoot(entityID, pos)
+
+	// Turn entity into a corpse at the next sync point, as the caller may be
+	// iterating over entities. Losing its TurnActor takes it out of the turn
+	// queue, and the corpse tag out of the spatial grid.
+	g.commands.RemoveComponents(entityID,
 		components.CTurnActor,
 		components.CAITag,
 		components.CBlocksMovement,
 		components.CHealth,
+		components.CSummoned,
+		components.CReward,
 	)
-
-	g.ecs.AddComponents(entityID,
-		components.Renderable{Glyph: '%', Color: ui.ColorCorpse},
-		components.CorpseTag{},
-	)
-
-	// Remove from turn queue
-	g.turnQueue.Remove(entityID)
-	// Remove from spatial grid using safe accessor
-	pos := g.ecs.GetPositionSafe(entityID)
-	g.spatialGrid.Remove(entityID, pos)
+	g.commands.AddComponent(entityID, components.CRenderable, components.Renderable{Glyph: '%', Color: ui.ColorCorpse})
+	g.commands.AddComponent(entityID, components.CCorpseTag, components.CorpseTag{})
 }
 
 // Additional Action Types
 // Note: Inventory actions (Pickup, Drop, UseItem, Equip) are defined in inventory_actions.go
+
+// DescendStairsAction takes the player down the stairs they are standing on.
+type DescendStairsAction struct {
+	EntityID ecs.EntityID
+}
+
+// Execute moves the player one level deeper.
+func (a DescendStairsAction) Execute(g *Game) (cost uint, err error) {
+	if a.EntityID != g.PlayerID {
+		return 0, fmt.Errorf("entity %d cannot use stairs", a.EntityID)
+	}
+
+	if g.dungeon.Grid.At(g.GetPlayerPosition()) != StairsDownCell {
+		g.log.AddMessagef(ui.ColorStatusBad, "There are no stairs down here.")
+		return 0, fmt.Errorf("no stairs down at player position")
+	}
+
+	g.changeLevel(g.Depth + 1)
+	g.log.AddMessagef(ui.ColorStatusNeutral, "You descend to depth %d.", g.Depth)
+
+	return 100, nil
+}
+
+// AscendStairsAction takes the player up the stairs they are standing on.
+type AscendStairsAction struct {
+	EntityID ecs.EntityID
+}
+
+// Execute moves the player one level up.
+func (a AscendStairsAction) Execute(g *Game) (cost uint, err error) {
+	if a.EntityID != g.PlayerID {
+		return 0, fmt.Errorf("entity %d cannot use stairs", a.EntityID)
+	}
+
+	if g.dungeon.Grid.At(g.GetPlayerPosition()) != StairsUpCell {
+		g.log.AddMessagef(ui.ColorStatusBad, "There are no stairs up here.")
+		return 0, fmt.Errorf("no stairs up at player position")
+	}
+
+	g.changeLevel(g.Depth - 1)
+	g.log.AddMessagef(ui.ColorStatusNeutral, "You climb up to depth %d.", g.Depth)
+
+	return 100, nil
+}
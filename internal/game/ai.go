@@ -3,7 +3,6 @@ package game
 import (
 	"log/slog"
 	"math"
-	"math/rand"
 
 	"codeberg.org/anaseto/gruid"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
@@ -173,7 +172,7 @@ func (g *Game) searchForPlayer(entityID ecs.EntityID, aiComp *components.AICompo
 		directions := []gruid.Point{
 			{X: -1, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: 0, Y: 1},
 		}
-		direction := directions[rand.Intn(len(directions))]
+		direction := directions[g.rand.Intn(len(directions))]
 		return MoveAction{Direction: direction, EntityID: entityID}
 	}
 
@@ -218,7 +217,7 @@ func (g *Game) patrolArea(entityID ecs.EntityID, aiComp *components.AIComponent,
 	directions := []gruid.Point{
 		{X: -1, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: 0, Y: 1},
 	}
-	direction := directions[rand.Intn(len(directions))]
+	direction := directions[g.rand.Intn(len(directions))]
 	return MoveAction{Direction: direction, EntityID: entityID}
 }
 
@@ -248,11 +247,11 @@ func (g *Game) attackNearbyTarget(entityID ecs.EntityID, pos gruid.Point) GameAc
 func (g *Game) idleBehavior(entityID ecs.EntityID, aiComp *components.AIComponent, pos gruid.Point) GameAction {
 	switch aiComp.Behavior {
 	case components.AIBehaviorWander:
-		if rand.Intn(3) == 0 { // 33% chance to move
+		if g.rand.Intn(3) == 0 { // 33% chance to move
 			directions := []gruid.Point{
 				{X: -1, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: 0, Y: 1},
 			}
-			direction := directions[rand.Intn(len(directions))]
+			direction := directions[g.rand.Intn(len(directions))]
 			return MoveAction{Direction: direction, EntityID: entityID}
 		}
 	}
@@ -0,0 +1,402 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"math/rand"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui"
+)
+
+// BlueprintsFile is the data file, under assets/data, defining the entity
+// blueprints spawned entities are built from
+const BlueprintsFile = "blueprints.json"
+
+// Blueprint is the data-file form of an entity archetype. Components maps
+// component type names to the component's fields, which are decoded over
+// the component's defaults. A blueprint extending another starts from the
+// parent's components: the fields it lists replace the parent's, and a null
+// component drops the inherited one.
+type Blueprint struct {
+	ID         string                                       `json:"id"`
+	Extends    string                                       `json:"extends,omitempty"`
+	Components map[components.ComponentType]json.RawMessage `json:"components"`
+}
+
+// blueprintColors are the color names accepted besides "#RRGGBB"
+var blueprintColors = map[string]gruid.Color{
+	"player":  ui.ColorPlayer,
+	"monster": ui.ColorMonster,
+	"item":    ui.ColorItem,
+	"ally":    ui.ColorStatusGood,
+}
+
+// spawnContext is what blueprint components may depend on besides their
+// fields
+type spawnContext struct {
+	pos           gruid.Point
+	width, height int // Dungeon size, for fields of view
+	rand          *rand.Rand
+}
+
+// componentBuilder builds one component type from blueprint fields
+type componentBuilder struct {
+	typ   reflect.Type // Type of the built component, matched against overrides
+	build func(fields json.RawMessage, ctx spawnContext) (any, error)
+}
+
+// fieldsOver builds a component by decoding the fields over base()
+func fieldsOver[T any](base func() T) componentBuilder {
+	return componentBuilder{
+		typ: reflect.TypeFor[T](),
+		build: func(fields json.RawMessage, _ spawnContext) (any, error) {
+			comp := base()
+			if err := decodeFields(fields, &comp); err != nil {
+				return nil, err
+			}
+			return comp, nil
+		},
+	}
+}
+
+// fromSpec builds a component by decoding the fields into a spec, then
+// converting it with finish
+func fromSpec[S, T any](finish func(spec S, ctx spawnContext) (T, error)) componentBuilder {
+	return componentBuilder{
+		typ: reflect.TypeFor[T](),
+		build: func(fields json.RawMessage, ctx spawnContext) (any, error) {
+			var spec S
+			if err := decodeFields(fields, &spec); err != nil {
+				return nil, err
+			}
+			return finish(spec, ctx)
+		},
+	}
+}
+
+// zeroOf returns the zero value of a component without fields
+func zeroOf[T any]() T {
+	var zero T
+	return zero
+}
+
+// blueprintBuilders lists the components a blueprint may hold. Components
+// that only make sense per entity, like positions and item pickups, are
+// left to the spawn overrides.
+var blueprintBuilders = map[components.ComponentType]componentBuilder{
+	components.CAITag:          fieldsOver(zeroOf[components.AITag]),
+	components.CBlocksMovement: fieldsOver(zeroOf[components.BlocksMovement]),
+	components.CCorpseTag:      fieldsOver(zeroOf[components.CorpseTag]),
+	components.CPlayerTag:      fieldsOver(zeroOf[components.PlayerTag]),
+	components.CName:           fieldsOver(zeroOf[components.Name]),
+	components.CEquipment:      fieldsOver(components.NewEquipment),
+	components.CStats:          fieldsOver(components.NewStats),
+	components.CExperience:     fieldsOver(components.NewExperience),
+	components.CSkills:         fieldsOver(components.NewSkills),
+	components.CCombat:         fieldsOver(components.NewCombat),
+	components.CStatusEffects:  fieldsOver(components.NewStatusEffects),
+	components.CReward:         fieldsOver(zeroOf[components.Reward]),
+	components.CSummoned:       fieldsOver(zeroOf[components.Summoned]),
+
+	components.CPathfindingComponent: fieldsOver(components.NewPathfindingComponent),
+
+	components.CRenderable: fromSpec(func(spec struct{ Glyph, Color, TileName string }, _ spawnContext) (components.Renderable, error) {
+		if utf8.RuneCountInString(spec.Glyph) != 1 {
+			return components.Renderable{}, fmt.Errorf("needs a single-character glyph")
+		}
+		color, ok := blueprintColors[spec.Color]
+		if !ok {
+			var err error
+			if color, err = parseHexColor(spec.Color); err != nil {
+				return components.Renderable{}, fmt.Errorf("invalid color %q", spec.Color)
+			}
+		}
+		glyph, _ := utf8.DecodeRuneInString(spec.Glyph)
+		return components.Renderable{Glyph: glyph, Color: color, TileName: spec.TileName}, nil
+	}),
+	components.CHealth: fromSpec(func(spec struct{ MaxHP int }, _ spawnContext) (components.Health, error) {
+		if spec.MaxHP <= 0 {
+			return components.Health{}, fmt.Errorf("needs a positive MaxHP")
+		}
+		return components.NewHealth(spec.MaxHP), nil
+	}),
+	components.CMana: fromSpec(func(spec struct{ MaxMP int }, _ spawnContext) (components.Mana, error) {
+		return components.NewMana(spec.MaxMP), nil
+	}),
+	components.CStamina: fromSpec(func(spec struct{ MaxSP int }, _ spawnContext) (components.Stamina, error) {
+		return components.NewStamina(spec.MaxSP), nil
+	}),
+	components.CInventory: fromSpec(func(spec struct{ Capacity int }, _ spawnContext) (components.Inventory, error) {
+		if spec.Capacity <= 0 {
+			return components.Inventory{}, fmt.Errorf("needs a positive Capacity")
+		}
+		return components.NewInventory(spec.Capacity), nil
+	}),
+	components.CSpellbook: fromSpec(func(spec struct{ Spells []string }, _ spawnContext) (components.Spellbook, error) {
+		return components.NewSpellbook(spec.Spells...), nil
+	}),
+	components.CTurnActor: fromSpec(func(spec struct{ Speed uint64 }, _ spawnContext) (components.TurnActor, error) {
+		if spec.Speed == 0 {
+			return components.TurnActor{}, fmt.Errorf("needs a positive Speed")
+		}
+		return components.NewTurnActor(spec.Speed), nil
+	}),
+	components.CFOV: fromSpec(func(spec struct{ Range int }, ctx spawnContext) (*components.FOV, error) {
+		if spec.Range <= 0 {
+			return nil, fmt.Errorf("needs a positive Range")
+		}
+		return components.NewFOVComponent(spec.Range, ctx.width, ctx.height), nil
+	}),
+	components.CAIComponent: fromSpec(func(spec struct{ Behavior string }, ctx spawnContext) (components.AIComponent, error) {
+		behavior, ok := aiBehaviors[spec.Behavior]
+		if !ok {
+			if spec.Behavior != behaviorRandom {
+				return components.AIComponent{}, fmt.Errorf("unknown behavior %q", spec.Behavior)
+			}
+			behavior = randomBehaviors[ctx.rand.Intn(len(randomBehaviors))]
+		}
+		return components.NewAIComponent(behavior, ctx.pos), nil
+	}),
+}
+
+// decodeFields decodes blueprint fields, rejecting fields the target lacks
+func decodeFields(fields json.RawMessage, v any) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(fields))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// mergeFields lays the child's component fields over the parent's
+func mergeFields(parent, child json.RawMessage) (json.RawMessage, error) {
+	if parent == nil {
+		return child, nil
+	}
+	var merged, overlay map[string]json.RawMessage
+	if err := json.Unmarshal(parent, &merged); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(child, &overlay); err != nil {
+		return nil, err
+	}
+	if merged == nil {
+		merged = overlay
+	} else {
+		maps.Copy(merged, overlay)
+	}
+	return json.Marshal(merged)
+}
+
+// BlueprintCatalog holds the blueprints loaded from data, with inheritance
+// resolved
+type BlueprintCatalog struct {
+	blueprints map[string]map[components.ComponentType]json.RawMessage // Component fields by blueprint ID
+}
+
+// NewBlueprintCatalog builds a catalog, resolving inheritance and rejecting
+// blueprints with unknown parents, inheritance cycles, unknown components
+// or fields the components can't take
+func NewBlueprintCatalog(defs []Blueprint) (*BlueprintCatalog, error) {
+	byID := make(map[string]Blueprint, len(defs))
+	var errs []error
+	for _, def := range defs {
+		if def.ID == "" {
+			errs = append(errs, fmt.Errorf("blueprint must have an id"))
+			continue
+		}
+		if _, exists := byID[def.ID]; exists {
+			errs = append(errs, fmt.Errorf("duplicate blueprint %q", def.ID))
+			continue
+		}
+		byID[def.ID] = def
+	}
+
+	catalog := &BlueprintCatalog{blueprints: make(map[string]map[components.ComponentType]json.RawMessage, len(byID))}
+	resolved := make(map[string]map[components.ComponentType]json.RawMessage, len(byID))
+	validation := spawnContext{width: 1, height: 1, rand: rand.New(rand.NewSource(0))}
+	for _, id := range slices.Sorted(maps.Keys(byID)) {
+		fields, err := resolveBlueprint(id, byID, resolved, nil)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := validateBlueprint(fields, validation); err != nil {
+			errs = append(errs, fmt.Errorf("blueprint %q: %w", id, err))
+			continue
+		}
+		catalog.blueprints[id] = fields
+	}
+	return catalog, errors.Join(errs...)
+}
+
+// resolveBlueprint merges a blueprint's components with its ancestors'.
+// chain lists the blueprints being resolved, to catch inheritance cycles.
+func resolveBlueprint(id string, defs map[string]Blueprint, resolved map[string]map[components.ComponentType]json.RawMessage, chain []string) (map[components.ComponentType]json.RawMessage, error) {
+	if fields, ok := resolved[id]; ok {
+		return fields, nil
+	}
+	if slices.Contains(chain, id) {
+		return nil, fmt.Errorf("blueprint inheritance cycle %s", strings.Join(append(chain, id), " -> "))
+	}
+	def, ok := defs[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown blueprint %q", id)
+	}
+
+	fields := make(map[components.ComponentType]json.RawMessage, len(def.Components))
+	if def.Extends != "" {
+		parent, err := resolveBlueprint(def.Extends, defs, resolved, append(chain, id))
+		if err != nil {
+			return nil, fmt.Errorf("blueprint %q: %w", id, err)
+		}
+		maps.Copy(fields, parent)
+	}
+	for compType, compFields := range def.Components {
+		if string(compFields) == "null" {
+			delete(fields, compType)
+			continue
+		}
+		merged, err := mergeFields(fields[compType], compFields)
+		if err != nil {
+			return nil, fmt.Errorf("blueprint %q: %s fields must be an object: %w", id, compType, err)
+		}
+		fields[compType] = merged
+	}
+	resolved[id] = fields
+	return fields, nil
+}
+
+// validateBlueprint checks the components against components.TypeToComponent
+// and builds each of them once, so bad fields show up at load time
+func validateBlueprint(fields map[components.ComponentType]json.RawMessage, ctx spawnContext) error {
+	var errs []error
+	for _, compType := range slices.Sorted(maps.Keys(fields)) {
+		if _, ok := components.TypeToComponent[compType]; !ok {
+			errs = append(errs, fmt.Errorf("unknown component %q", compType))
+			continue
+		}
+		builder, ok := blueprintBuilders[compType]
+		if !ok {
+			errs = append(errs, fmt.Errorf("component %s can't be set in a blueprint", compType))
+			continue
+		}
+		if _, err := builder.build(fields[compType], ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", compType, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// requiredBlueprints are spawned by the game itself, which can't run
+// without them
+var requiredBlueprints = []string{"player", "monster", "summon", "item", "chest"}
+
+// LoadBlueprints loads the blueprint catalog from the data embedded from
+// assets/data. Invalid blueprints are logged and skipped so one bad entry
+// doesn't remove all the others, but missing required blueprints leave the
+// game unplayable and panic.
+func LoadBlueprints() *BlueprintCatalog {
+	defs, err := loadData[[]Blueprint](BlueprintsFile)
+	if err != nil {
+		panic(fmt.Sprintf("failed to load blueprints: %v", err))
+	}
+
+	catalog, err := NewBlueprintCatalog(defs)
+	if err != nil {
+		slog.Warn("Skipped invalid blueprints", "error", err)
+	}
+	if err := catalog.checkRequired(); err != nil {
+		panic(err.Error())
+	}
+	return catalog
+}
+
+// checkRequired reports the required blueprints missing from the catalog
+func (c *BlueprintCatalog) checkRequired() error {
+	var errs []error
+	for _, id := range requiredBlueprints {
+		if !c.Has(id) {
+			errs = append(errs, fmt.Errorf("missing required blueprint %q", id))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Has reports whether the catalog holds a blueprint
+func (c *BlueprintCatalog) Has(id string) bool {
+	_, ok := c.blueprints[id]
+	return ok
+}
+
+// Build returns the components of an entity spawned from a blueprint at
+// ctx.pos, position included. Overrides replace the blueprint's components
+// of the same type and add to the others. For an unknown blueprint the
+// position and overrides are returned along with an error.
+func (c *BlueprintCatalog) Build(id string, ctx spawnContext, overrides ...any) ([]any, error) {
+	comps := []any{ctx.pos}
+	fields, ok := c.blueprints[id]
+	if !ok {
+		return append(comps, overrides...), fmt.Errorf("unknown blueprint %q", id)
+	}
+
+	replaced := make(map[reflect.Type]bool, len(overrides))
+	for _, override := range overrides {
+		replaced[reflect.TypeOf(override)] = true
+	}
+
+	// Sorted, so components drawing random numbers do it in a fixed order
+	var errs []error
+	for _, compType := range slices.Sorted(maps.Keys(fields)) {
+		builder := blueprintBuilders[compType]
+		if replaced[builder.typ] {
+			continue
+		}
+		comp, err := builder.build(fields[compType], ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("blueprint %q: %s: %w", id, compType, err))
+			continue
+		}
+		comps = append(comps, comp)
+	}
+	return append(comps, overrides...), errors.Join(errs...)
+}
+
+// spawnContext returns the context of an entity spawning at pos
+func (g *Game) spawnContext(pos gruid.Point) spawnContext {
+	ctx := spawnContext{pos: pos, rand: g.rand}
+	if g.dungeon != nil {
+		ctx.width, ctx.height = g.dungeon.Width, g.dungeon.Height
+	}
+	return ctx
+}
+
+// blueprintComponents builds the components of an entity spawning at pos,
+// logging blueprint errors
+func (g *Game) blueprintComponents(blueprint string, pos gruid.Point, overrides ...any) []any {
+	comps, err := g.blueprints.Build(blueprint, g.spawnContext(pos), overrides...)
+	if err != nil {
+		slog.Error("Failed to build entity from blueprint", "blueprint", blueprint, "error", err)
+	}
+	return comps
+}
+
+// SpawnBlueprint creates an entity at pos from a blueprint. Overrides
+// replace the blueprint's components of the same type.
+func (g *Game) SpawnBlueprint(blueprint string, pos gruid.Point, overrides ...any) ecs.EntityID {
+	id := g.ecs.AddEntity()
+	g.ecs.AddComponents(id, g.blueprintComponents(blueprint, pos, overrides...)...)
+	return id
+}
@@ -0,0 +1,161 @@
+package game
+
+import (
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func TestBlueprintsFile_IsValid(t *testing.T) {
+	defs, err := loadData[[]Blueprint](BlueprintsFile)
+	if err != nil {
+		t.Fatalf("Failed to load %s: %v", BlueprintsFile, err)
+	}
+	catalog, err := NewBlueprintCatalog(defs)
+	if err != nil {
+		t.Errorf("Invalid blueprints: %v", err)
+	}
+
+	// The spawners build from these
+	if err := catalog.checkRequired(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBlueprintCatalog_CheckRequired(t *testing.T) {
+	catalog, err := NewBlueprintCatalog(parseBlueprints(t, `[{"id": "player"}, {"id": "item"}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = catalog.checkRequired()
+	if err == nil {
+		t.Fatal("Expected missing blueprints reported")
+	}
+	for _, id := range []string{"monster", "summon"} {
+		if !strings.Contains(err.Error(), id) {
+			t.Errorf("Expected %q reported missing, got %v", id, err)
+		}
+	}
+}
+
+func TestBlueprintBuilders_MatchComponentTypes(t *testing.T) {
+	for compType := range blueprintBuilders {
+		if _, ok := components.TypeToComponent[compType]; !ok {
+			t.Errorf("Builder for unknown component %q", compType)
+		}
+	}
+}
+
+// parseBlueprints decodes blueprints written as in the data file
+func parseBlueprints(t *testing.T, data string) []Blueprint {
+	t.Helper()
+	var defs []Blueprint
+	if err := json.Unmarshal([]byte(data), &defs); err != nil {
+		t.Fatal(err)
+	}
+	return defs
+}
+
+func TestBlueprintCatalog_InheritanceAndOverrides(t *testing.T) {
+	catalog, err := NewBlueprintCatalog(parseBlueprints(t, `[
+		{"id": "goblin_archer", "extends": "goblin", "components": {
+			"Combat": {"Accuracy": 90},
+			"BlocksMovement": null
+		}},
+		{"id": "goblin", "components": {
+			"BlocksMovement": {},
+			"Name": {"Name": "Goblin"},
+			"Health": {"MaxHP": 7},
+			"Combat": {"AttackPower": 3}
+		}}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := spawnContext{pos: gruid.Point{X: 3, Y: 4}, rand: rand.New(rand.NewSource(1))}
+	comps, err := catalog.Build("goblin_archer", ctx, components.Name{Name: "Grik"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	var combat components.Combat
+	for _, comp := range comps {
+		switch c := comp.(type) {
+		case gruid.Point:
+			if c != ctx.pos {
+				t.Errorf("Expected position %v, got %v", ctx.pos, c)
+			}
+		case components.Name:
+			names = append(names, c.Name)
+		case components.Combat:
+			combat = c
+		case components.Health:
+			if c.CurrentHP != 7 || c.MaxHP != 7 {
+				t.Errorf("Expected inherited 7/7 HP, got %d/%d", c.CurrentHP, c.MaxHP)
+			}
+		case components.BlocksMovement:
+			t.Error("Expected the null component to drop the inherited one")
+		}
+	}
+
+	if len(names) != 1 || names[0] != "Grik" {
+		t.Errorf("Expected the override to replace the blueprint's name, got %v", names)
+	}
+	if combat.AttackPower != 3 || combat.Accuracy != 90 {
+		t.Errorf("Expected parent attack 3 and own accuracy 90, got %d and %d", combat.AttackPower, combat.Accuracy)
+	}
+	if combat.CriticalDamage != components.NewCombat().CriticalDamage {
+		t.Errorf("Expected unlisted fields to keep their defaults, got %d", combat.CriticalDamage)
+	}
+}
+
+func TestBlueprintCatalog_RejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"unknown parent", `[{"id": "a", "extends": "b", "components": {}}]`},
+		{"inheritance cycle", `[{"id": "a", "extends": "b"}, {"id": "b", "extends": "a"}]`},
+		{"unknown component", `[{"id": "a", "components": {"Wings": {}}}]`},
+		{"per-entity component", `[{"id": "a", "components": {"Position": {}}}]`},
+		{"unknown field", `[{"id": "a", "components": {"Health": {"MaxHP": 5, "Armor": 2}}}]`},
+		{"invalid value", `[{"id": "a", "components": {"TurnActor": {"Speed": 0}}}]`},
+		{"unknown behavior", `[{"id": "a", "components": {"AIComponent": {"Behavior": "dance"}}}]`},
+		{"duplicate id", `[{"id": "a"}, {"id": "a"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			catalog, err := NewBlueprintCatalog(parseBlueprints(t, tt.data))
+			if err == nil {
+				t.Error("Expected an error")
+			}
+			if tt.name != "duplicate id" && catalog.Has("a") {
+				t.Error("Expected the invalid blueprint skipped")
+			}
+		})
+	}
+}
+
+func TestSpawnPlayer_FromBlueprint(t *testing.T) {
+	g := createTestGame()
+	g.SpawnPlayer(gruid.Point{X: 2, Y: 2}, g.items)
+
+	for _, compType := range []components.ComponentType{
+		components.CPosition, components.CPlayerTag, components.CHealth, components.CTurnActor,
+		components.CFOV, components.CInventory, components.CSpellbook, components.CRenderable,
+	} {
+		if !g.ecs.HasComponent(g.PlayerID, compType) {
+			t.Errorf("Expected the player to have %s", compType)
+		}
+	}
+	if fov := g.ecs.GetFOVSafe(g.PlayerID); fov.Range != 10 {
+		t.Errorf("Expected the player's own FOV range over the creature default, got %d", fov.Range)
+	}
+}
@@ -0,0 +1,77 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui"
+)
+
+// handleCastAction lists the player's spells and waits for a choice
+func (md *Model) handleCastAction() (again bool, eff gruid.Effect, err error) {
+	g := md.game
+
+	spells := g.knownSpells(g.PlayerID)
+	if len(spells) == 0 {
+		g.log.AddMessagef(ui.ColorStatusBad, "You don't know any spells.")
+		return true, eff, nil // Don't consume turn
+	}
+
+	mana := g.ecs.GetManaSafe(g.PlayerID)
+	g.log.AddMessagef(ui.ColorStatusNeutral, "Cast which spell? (%d/%d MP, Esc cancels)", mana.CurrentMP, mana.MaxMP)
+	for i, spell := range spells {
+		g.log.AddMessagef(ui.ColorStatusNeutral, "%c) %s (%d MP) - %s", 'a'+i, spell.Name, spell.ManaCost, spell.Description)
+	}
+
+	md.spellChoices = spells
+	md.mode = modeSpellSelect
+	return true, eff, nil // Don't consume turn until casting
+}
+
+// processSpellSelectInput handles input while choosing a spell to cast
+func (md *Model) processSpellSelectInput(msg gruid.Msg) gruid.Effect {
+	keyMsg, ok := msg.(gruid.MsgKeyDown)
+	if !ok {
+		slog.Debug("Unhandled message type in spell selection", "type", fmt.Sprintf("%T", msg))
+		return nil
+	}
+
+	if keyMsg.Key == gruid.KeyEscape {
+		md.mode = modeNormal
+		return nil
+	}
+
+	key := []rune(keyMsg.Key)
+	if len(key) != 1 || key[0] < 'a' || int(key[0]-'a') >= len(md.spellChoices) {
+		slog.Debug("Key does not select a spell", "key", keyMsg.Key)
+		return nil
+	}
+
+	again, effect := md.chooseSpell(md.spellChoices[key[0]-'a'])
+	if again {
+		return effect
+	}
+	return md.EndTurn()
+}
+
+// chooseSpell casts the chosen spell, entering targeting mode first for
+// targeted spells.
+func (md *Model) chooseSpell(spell Spell) (again bool, eff gruid.Effect) {
+	g := md.game
+	md.mode = modeNormal
+
+	if g.ecs.GetManaSafe(g.PlayerID).CurrentMP < spell.ManaCost {
+		g.log.AddMessagef(ui.ColorStatusBad, "You don't have enough mana to cast %s.", spell.Name)
+		return true, eff
+	}
+
+	if spell.IsTargeted() {
+		md.startTargeting(spell.Range, spell.ID)
+		g.log.AddMessagef(ui.ColorStatusNeutral, "Aim %s: Tab cycles targets, Enter casts, Esc cancels.", spell.Name)
+		return true, eff
+	}
+
+	md.queuePlayerAction(CastSpellAction{CasterID: g.PlayerID, SpellID: spell.ID})
+	return false, eff
+}
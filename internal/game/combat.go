@@ -0,0 +1,194 @@
+package game
+
+import (
+	"log/slog"
+	"math"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui"
+)
+
+const (
+	minHitChance = 5  // Attacks always have some chance to land
+	maxHitChance = 95 // and some chance to miss
+)
+
+// AttackResult is the outcome of a single resolved attack.
+type AttackResult struct {
+	Hit      bool
+	Critical bool
+	Damage   int
+}
+
+// attributeModifier converts an attribute score into a bonus, with 10 as
+// the neutral value (8-9 gives -1, 12-13 gives +1 and so on).
+func attributeModifier(score int) int {
+	return int(math.Floor(float64(score-10) / 2))
+}
+
+// effectiveStats returns an entity's attributes with equipment bonuses and
+// active status effects applied. Entities without a Stats component use the
+// defaults.
+func (g *Game) effectiveStats(id ecs.EntityID) components.Stats {
+	stats, ok := g.ecs.GetStats(id)
+	if !ok {
+		stats = components.NewStats()
+	}
+
+	if equipment, ok := g.ecs.GetEquipment(id); ok {
+		for _, item := range equipment.Items() {
+			stats.Strength += item.StatBonus.Strength
+			stats.Dexterity += item.StatBonus.Dexterity
+			stats.Constitution += item.StatBonus.Constitution
+			stats.Intelligence += item.StatBonus.Intelligence
+			stats.Wisdom += item.StatBonus.Wisdom
+			stats.Charisma += item.StatBonus.Charisma
+		}
+	}
+
+	if effects, ok := g.ecs.GetStatusEffects(id); ok {
+		statMods, _ := effects.GetTotalModifiers()
+		stats.Strength += statMods.Strength
+		stats.Dexterity += statMods.Dexterity
+		stats.Constitution += statMods.Constitution
+		stats.Intelligence += statMods.Intelligence
+		stats.Wisdom += statMods.Wisdom
+		stats.Charisma += statMods.Charisma
+	}
+
+	return stats
+}
+
+// effectiveCombat returns an entity's combat values with its attributes,
+// equipped items and active status effects folded in. Entities without a
+// Combat component fall back to the defaults.
+func (g *Game) effectiveCombat(id ecs.EntityID) components.Combat {
+	combat, ok := g.ecs.GetCombat(id)
+	if !ok {
+		combat = components.NewCombat()
+	}
+	stats := g.effectiveStats(id)
+
+	if effects, ok := g.ecs.GetStatusEffects(id); ok {
+		_, combatMods := effects.GetTotalModifiers()
+		combat.AttackPower += combatMods.AttackPower
+		combat.Defense += combatMods.Defense
+		combat.Accuracy += combatMods.Accuracy
+		combat.DodgeChance += combatMods.DodgeChance
+	}
+
+	if equipment, ok := g.ecs.GetEquipment(id); ok {
+		for _, item := range equipment.Items() {
+			combat.AttackPower += item.AttackBonus
+			combat.Defense += item.DefenseBonus
+			combat.Accuracy += item.AccuracyBonus
+			combat.DodgeChance += item.DodgeBonus
+		}
+	}
+
+	combat.AttackPower += attributeModifier(stats.Strength)
+	combat.Accuracy += 2 * attributeModifier(stats.Dexterity)
+	combat.DodgeChance += attributeModifier(stats.Dexterity)
+
+	return combat
+}
+
+// EffectiveStats returns the player's attributes with equipment and
+// status effects applied, for UI access
+func (g *Game) EffectiveStats() components.Stats {
+	return g.effectiveStats(g.PlayerID)
+}
+
+// EffectiveCombat returns the player's combat values with attributes,
+// equipment and status effects applied, for UI access
+func (g *Game) EffectiveCombat() components.Combat {
+	return g.effectiveCombat(g.PlayerID)
+}
+
+// resolveMeleeAttack rolls a melee attack from attacker against target.
+func (g *Game) resolveMeleeAttack(attackerID, targetID ecs.EntityID) AttackResult {
+	return g.rollAttack(attackerID, g.effectiveCombat(attackerID), g.effectiveCombat(targetID))
+}
+
+// rollAttack resolves an attack between two sets of combat values. The hit
+// chance is the attacker's accuracy minus the defender's dodge chance, crits
+// multiply damage by CriticalDamage percent, and the defender's defense is
+// subtracted afterwards. A hit always deals at least 1 damage.
+func (g *Game) rollAttack(attackerID ecs.EntityID, attacker, defender components.Combat) AttackResult {
+	hitChance := min(max(attacker.Accuracy-defender.DodgeChance, minHitChance), maxHitChance)
+	if g.rand.Intn(100) >= hitChance {
+		return AttackResult{}
+	}
+
+	result := AttackResult{Hit: true}
+	damage := float64(attacker.AttackPower)
+	if g.rand.Intn(100) < attacker.CriticalChance {
+		result.Critical = true
+		damage = damage * float64(attacker.CriticalDamage) / 100
+	}
+
+	damage -= float64(defender.Defense)
+	if attackerID != g.PlayerID {
+		damage *= gameplayConfig().MonsterDamageMultiplier
+	}
+
+	result.Damage = max(int(math.Round(damage)), 1)
+	return result
+}
+
+// applyAttack applies a resolved attack to its target: it logs the outcome
+// using verb ("attacks", "shoots", ...), updates health and statistics,
+// triggers the combat event and handles the target's death.
+func (g *Game) applyAttack(attackerID, targetID ecs.EntityID, result AttackResult, verb string) {
+	attackerName := g.ecs.GetNameSafe(attackerID)
+	targetName := g.ecs.GetNameSafe(targetID)
+
+	// Determine message color based on who is attacking
+	var msgColor gruid.Color
+	if attackerID == g.PlayerID {
+		msgColor = ui.ColorPlayerAttack
+	} else if targetID == g.PlayerID {
+		msgColor = ui.ColorEnemyAttack
+	} else {
+		msgColor = ui.ColorNeutralAttack
+	}
+
+	if !result.Hit {
+		g.log.AddMessagef(msgColor, "%s misses %s.", attackerName, targetName)
+		slog.Info("Combat action missed", "attacker", attackerName, "attackerId", attackerID, "target", targetName, "targetId", targetID)
+		g.TriggerCombatEvent(attackerID, targetID, 0, false, true)
+		return
+	}
+
+	// The dead keep their health until their corpse is made at the next
+	// sync point; they cannot die twice
+	targetHealth, ok := g.ecs.GetHealth(targetID)
+	if !ok || targetHealth.IsDead() {
+		return
+	}
+
+	damage := result.Damage
+	targetHealth.CurrentHP -= damage
+
+	// Track damage statistics
+	if attackerID == g.PlayerID {
+		g.AddDamageDealt(damage)
+	} else if targetID == g.PlayerID {
+		g.AddDamageTaken(damage)
+	}
+
+	g.log.AddMessagef(msgColor, "%s %s %s for %d damage.", attackerName, verb, targetName, damage)
+
+	slog.Info("Combat action", "attacker", attackerName, "attackerId", attackerID, "target", targetName, "targetId", targetID, "damage", damage, "critical", result.Critical, "targetHP", targetHealth.CurrentHP, "targetMaxHP", targetHealth.MaxHP)
+	g.ecs.AddComponent(targetID, components.CHealth, targetHealth)
+
+	// Trigger combat event
+	g.TriggerCombatEvent(attackerID, targetID, damage, result.Critical, false)
+
+	// Check for death (CurrentHP <= 0) and handle it
+	if targetHealth.IsDead() {
+		g.handleEntityDeath(targetID, targetName, attackerID)
+	}
+}
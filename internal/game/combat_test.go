@@ -0,0 +1,143 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func TestAttributeModifier(t *testing.T) {
+	tests := []struct {
+		score int
+		want  int
+	}{
+		{10, 0},
+		{11, 0},
+		{12, 1},
+		{16, 3},
+		{9, -1},
+		{8, -1},
+		{7, -2},
+	}
+
+	for _, tt := range tests {
+		if got := attributeModifier(tt.score); got != tt.want {
+			t.Errorf("attributeModifier(%d) = %d, want %d", tt.score, got, tt.want)
+		}
+	}
+}
+
+func newCombatant(g *Game, combat components.Combat) ecs.EntityID {
+	id := g.ecs.AddEntity()
+	g.ecs.AddComponents(id, combat, components.NewStats(), components.NewHealth(100))
+	return id
+}
+
+func TestEffectiveCombat_FoldsEquipmentAndEffects(t *testing.T) {
+	g := NewGame()
+	id := newCombatant(g, components.NewCombat())
+
+	equipment := components.NewEquipment()
+	equipment.EquipItem(components.Item{Name: "Sword", Type: components.ItemTypeWeapon, AttackBonus: 3})
+	equipment.EquipItem(components.Item{Name: "Mail", Type: components.ItemTypeArmor, DefenseBonus: 2})
+	effects := components.NewStatusEffects()
+	effects.AddEffect(components.StatusEffect{Name: "Might", Duration: 5, StrengthMod: 4, DefenseMod: 1})
+	g.ecs.AddComponents(id, equipment, effects)
+
+	got := g.effectiveCombat(id)
+	if want := 1 + 3 + attributeModifier(14); got.AttackPower != want {
+		t.Errorf("AttackPower = %d, want %d", got.AttackPower, want)
+	}
+	if got.Defense != 3 {
+		t.Errorf("Defense = %d, want 3", got.Defense)
+	}
+}
+
+func TestEffectiveCombat_AccessoriesAndStatBonuses(t *testing.T) {
+	g := NewGame()
+	id := newCombatant(g, components.NewCombat())
+
+	equipment := components.NewEquipment()
+	equipment.Equip(components.Item{Name: "Gloves", Type: components.ItemTypeArmor, Slot: components.SlotHands, AccuracyBonus: 5})
+	equipment.Equip(components.Item{Name: "Boots", Type: components.ItemTypeArmor, Slot: components.SlotFeet, DodgeBonus: 3})
+	equipment.Equip(components.Item{Name: "Ring", Type: components.ItemTypeAccessory, Slot: components.SlotRing,
+		StatBonus: components.Stats{Strength: 4}})
+	g.ecs.AddComponents(id, equipment)
+
+	if got := g.effectiveStats(id).Strength; got != 14 {
+		t.Errorf("Strength = %d, want 14", got)
+	}
+	got := g.effectiveCombat(id)
+	if want := 1 + attributeModifier(14); got.AttackPower != want {
+		t.Errorf("AttackPower = %d, want %d", got.AttackPower, want)
+	}
+	if got.Accuracy != 80 || got.DodgeChance != 8 {
+		t.Errorf("Accuracy/Dodge = %d/%d, want 80/8", got.Accuracy, got.DodgeChance)
+	}
+}
+
+func TestResolveMeleeAttack(t *testing.T) {
+	g := NewGame()
+	g.SetSeed(1)
+
+	attacker := newCombatant(g, components.Combat{AttackPower: 10, Accuracy: 100, CriticalChance: 0, CriticalDamage: 200})
+	critter := newCombatant(g, components.Combat{AttackPower: 10, Accuracy: 100, CriticalChance: 100, CriticalDamage: 200})
+	target := newCombatant(g, components.Combat{Defense: 4})
+	armored := newCombatant(g, components.Combat{Defense: 50})
+	g.PlayerID = attacker
+
+	hits := 0
+	for range 1000 {
+		result := g.resolveMeleeAttack(attacker, target)
+		if !result.Hit {
+			if result.Damage != 0 {
+				t.Fatalf("Miss dealt %d damage", result.Damage)
+			}
+			continue
+		}
+		hits++
+		if result.Critical || result.Damage != 6 {
+			t.Fatalf("Expected a plain 6 damage hit, got %+v", result)
+		}
+	}
+	if hits < 900 || hits == 1000 {
+		t.Errorf("Expected hit chance capped at %d%%, got %d/1000 hits", maxHitChance, hits)
+	}
+
+	for range 50 {
+		if result := g.resolveMeleeAttack(critter, target); result.Hit && (!result.Critical || result.Damage != 16) {
+			t.Fatalf("Expected a 16 damage crit, got %+v", result)
+		}
+		if result := g.resolveMeleeAttack(attacker, armored); result.Hit && result.Damage != 1 {
+			t.Fatalf("Expected minimum damage of 1 against heavy armor, got %+v", result)
+		}
+	}
+}
+
+func TestApplyAttack_OnlyChangesTargetHealth(t *testing.T) {
+	g := NewGame()
+	attacker := newCombatant(g, components.NewCombat())
+	target := newCombatant(g, components.NewCombat())
+	g.PlayerID = attacker
+
+	before, err := g.ecs.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.applyAttack(attacker, target, AttackResult{Hit: true, Damage: 7}, "hits")
+	after, err := g.ecs.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, change := range ecs.Diff(before, after) {
+		got = append(got, change.String())
+	}
+	if want := []string{fmt.Sprintf("entity %d: Health modified", target)}; !slices.Equal(got, want) {
+		t.Errorf("Expected only %v, got %v", want, got)
+	}
+}
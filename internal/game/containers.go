@@ -0,0 +1,235 @@
+package game
+
+import (
+	"cmp"
+	"log/slog"
+	"slices"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// Items are entities wherever they are: on the floor they have a position,
+// while carried they have their holder as parent instead, and an Equipped
+// component when worn. A holder's Inventory and Equipment components list
+// these child entities and are rebuilt from them by syncContainer.
+
+// syncContainer rebuilds the inventory and equipment of an entity from the
+// item entities it holds. Stacks keep their order, new ones go last.
+func (g *Game) syncContainer(holder ecs.EntityID) {
+	hasInventory, hasEquipment := g.ecs.HasInventorySafe(holder), g.ecs.HasEquipmentSafe(holder)
+	if !hasInventory && !hasEquipment {
+		return
+	}
+
+	inventory := g.ecs.GetInventorySafe(holder)
+	order := make(map[int]int, len(inventory.Items))
+	for i, stack := range inventory.Items {
+		order[stack.Entity] = i
+	}
+
+	stacks := make([]components.ItemStack, 0, len(inventory.Items))
+	equipment := components.NewEquipment()
+	for _, child := range g.ecs.Children(holder) {
+		pickup, ok := g.ecs.GetItemPickup(child)
+		if !ok {
+			continue
+		}
+		if equipped, ok := g.ecs.GetEquipped(child); ok {
+			equipment.SetSlot(equipped.Slot, &pickup.Item)
+			continue
+		}
+		stacks = append(stacks, components.ItemStack{Item: pickup.Item, Quantity: pickup.Quantity, Entity: int(child)})
+	}
+	slices.SortStableFunc(stacks, func(a, b components.ItemStack) int {
+		i, aListed := order[a.Entity]
+		j, bListed := order[b.Entity]
+		switch {
+		case aListed && bListed:
+			return cmp.Compare(i, j)
+		case aListed:
+			return -1
+		case bListed:
+			return 1
+		}
+		return 0
+	})
+
+	if hasInventory {
+		inventory.Items = stacks
+		g.ecs.AddComponent(holder, components.CInventory, inventory)
+	}
+	if hasEquipment {
+		g.ecs.AddComponent(holder, components.CEquipment, equipment)
+	}
+}
+
+// giveItem creates an item entity carried by holder, as a stack of its own
+func (g *Game) giveItem(holder ecs.EntityID, item components.Item, quantity int) ecs.EntityID {
+	itemID := g.ecs.AddEntity()
+	// Carried items have no position
+	comps := slices.DeleteFunc(g.itemComponents(item, quantity, gruid.Point{}), func(comp any) bool {
+		_, isPos := comp.(gruid.Point)
+		return isPos
+	})
+	g.ecs.AddComponents(itemID, comps...)
+	if err := g.ecs.SetParent(itemID, holder); err != nil {
+		slog.Error("Failed to give item", "item", item.Name, "holder", holder, "error", err)
+	}
+	return itemID
+}
+
+// storeItem moves an item entity into holder's inventory, topping up stacks
+// of the same stackable item first. An item merged entirely into other
+// stacks is despawned at the next sync point. It returns false, changing
+// nothing, if the inventory has no room for what is left.
+func (g *Game) storeItem(holder, itemID ecs.EntityID) bool {
+	inventory := g.ecs.GetInventorySafe(holder)
+	pickup := g.ecs.GetItemPickupSafe(itemID)
+
+	left := pickup.Quantity
+	var topUps []components.ItemStack // Stacks with their new quantities
+	if pickup.Item.Stackable {
+		for _, stack := range inventory.Items {
+			if stack.Item.Name != pickup.Item.Name || stack.Entity == 0 || left == 0 {
+				continue
+			}
+			if amount := min(left, pickup.Item.MaxStack-stack.Quantity); amount > 0 {
+				stack.Quantity += amount
+				topUps = append(topUps, stack)
+				left -= amount
+			}
+		}
+	}
+	if left > 0 && inventory.IsFull() {
+		return false
+	}
+
+	for _, stack := range topUps {
+		g.setItemQuantity(ecs.EntityID(stack.Entity), stack.Quantity)
+	}
+	if left == 0 {
+		g.commands.Despawn(itemID)
+		return true
+	}
+
+	g.setItemQuantity(itemID, left)
+	g.ecs.RemoveComponent(itemID, components.CPosition)
+	if err := g.ecs.SetParent(itemID, holder); err != nil {
+		slog.Error("Failed to store item", "item", itemID, "holder", holder, "error", err)
+		return false
+	}
+	return true
+}
+
+// setItemQuantity changes the size of an item entity's stack
+func (g *Game) setItemQuantity(itemID ecs.EntityID, quantity int) {
+	pickup := g.ecs.GetItemPickupSafe(itemID)
+	pickup.Quantity = quantity
+	g.ecs.AddComponent(itemID, components.CItemPickup, pickup)
+}
+
+// splitItem takes quantity items off a stack into a new entity with the
+// same components and parent, so per-item state is kept. Taking the whole
+// stack returns the stack itself.
+func (g *Game) splitItem(itemID ecs.EntityID, quantity int) ecs.EntityID {
+	pickup := g.ecs.GetItemPickupSafe(itemID)
+	if quantity >= pickup.Quantity {
+		return itemID
+	}
+	g.setItemQuantity(itemID, pickup.Quantity-quantity)
+
+	splitID := g.ecs.AddEntity()
+	for compType, comp := range g.ecs.ComponentsOf(itemID) {
+		if compType != components.CEquipped {
+			g.ecs.AddComponent(splitID, compType, comp)
+		}
+	}
+	g.setItemQuantity(splitID, quantity)
+	return splitID
+}
+
+// consumeItem uses up quantity items of a stack, removing its entity once
+// empty
+func (g *Game) consumeItem(itemID ecs.EntityID, quantity int) {
+	pickup := g.ecs.GetItemPickupSafe(itemID)
+	if pickup.Quantity <= quantity {
+		g.ecs.RemoveEntity(itemID)
+		return
+	}
+	g.setItemQuantity(itemID, pickup.Quantity-quantity)
+}
+
+// dropItem puts a carried item entity on the floor at pos
+func (g *Game) dropItem(itemID ecs.EntityID, pos gruid.Point) {
+	g.ecs.RemoveComponent(itemID, components.CEquipped)
+	g.ecs.ClearParent(itemID)
+	g.ecs.AddComponent(itemID, components.CPosition, pos)
+}
+
+// containerAt returns the container, such as a chest, standing at pos.
+// Containers hold items in an inventory but are not creatures.
+func (g *Game) containerAt(pos gruid.Point) (ecs.EntityID, bool) {
+	for _, id := range g.ecs.EntitiesAt(pos) {
+		if g.ecs.HasInventorySafe(id) && !g.ecs.HasHealthSafe(id) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// carriedItem returns the first unequipped stack of the named item holding
+// at least quantity items
+func (g *Game) carriedItem(holder ecs.EntityID, name string, quantity int) (ecs.EntityID, bool) {
+	for _, stack := range g.ecs.GetInventorySafe(holder).Items {
+		if stack.Item.Name == name && stack.Quantity >= quantity && stack.Entity != 0 {
+			return ecs.EntityID(stack.Entity), true
+		}
+	}
+	return 0, false
+}
+
+// equipItem wears one item of a carried stack and returns the item entities
+// it displaced. These go back to the inventory, or on the floor when there
+// is no room for them.
+func (g *Game) equipItem(holder, itemID ecs.EntityID) (displaced []ecs.EntityID, err error) {
+	equipment := g.ecs.GetEquipmentSafe(holder)
+	before := equipment.Slots()
+	if _, err := equipment.Equip(g.ecs.GetItemPickupSafe(itemID).Item); err != nil {
+		return nil, err
+	}
+
+	worn := make(map[string]ecs.EntityID)
+	for _, child := range g.ecs.Children(holder) {
+		if equipped, ok := g.ecs.GetEquipped(child); ok {
+			worn[equipped.Slot] = child
+		}
+	}
+
+	// Equip replaces the item of every slot it touches
+	var slot string
+	for i, after := range equipment.Slots() {
+		if after.Item == before[i].Item {
+			continue
+		}
+		if id, ok := worn[after.Slot]; ok {
+			displaced = append(displaced, id)
+			g.ecs.RemoveComponent(id, components.CEquipped)
+		}
+		if after.Item != nil {
+			slot = after.Slot
+		}
+	}
+
+	g.ecs.AddComponent(g.splitItem(itemID, 1), components.CEquipped, components.Equipped{Slot: slot})
+
+	pos := g.ecs.GetPositionSafe(holder)
+	for _, id := range displaced {
+		inventory, ok := g.ecs.GetInventory(holder)
+		if !ok || len(inventory.Items) > inventory.Capacity {
+			g.dropItem(id, pos)
+		}
+	}
+	return displaced, nil
+}
@@ -0,0 +1,176 @@
+package game
+
+import (
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// createHolderTestGame sets up a player at (5,5) with an empty inventory
+// and equipment
+func createHolderTestGame(capacity int) *Game {
+	g := createTestGame()
+	g.PlayerID = g.ecs.AddEntity()
+	g.ecs.AddComponents(g.PlayerID,
+		gruid.Point{X: 5, Y: 5},
+		components.Name{Name: "Player"},
+		components.NewInventory(capacity),
+		components.NewEquipment(),
+	)
+	return g
+}
+
+func TestPickupAndDrop_KeepItemEntity(t *testing.T) {
+	g := createHolderTestGame(10)
+	sword := components.Item{Name: "Sword", Type: components.ItemTypeWeapon, AttackBonus: 2}
+	swordID := g.SpawnItem(sword, 1, gruid.Point{X: 5, Y: 5})
+
+	if _, err := (PickupAction{EntityID: g.PlayerID, ItemID: swordID}).Execute(g); err != nil {
+		t.Fatal(err)
+	}
+	g.commands.Apply()
+
+	if !g.ecs.EntityExists(swordID) {
+		t.Fatal("Expected the picked up item to stay an entity")
+	}
+	if parent, ok := g.ecs.Parent(swordID); !ok || parent != g.PlayerID {
+		t.Errorf("Expected the player to hold the sword, got parent %d", parent)
+	}
+	if g.ecs.HasComponent(swordID, components.CPosition) || len(g.spatialGrid.GetEntitiesAt(gruid.Point{X: 5, Y: 5})) != 1 {
+		t.Error("Expected the carried sword off the floor")
+	}
+	inventory := g.ecs.GetInventorySafe(g.PlayerID)
+	if len(inventory.Items) != 1 || inventory.Items[0].Entity != int(swordID) {
+		t.Fatalf("Expected the inventory to list the sword's entity, got %+v", inventory.Items)
+	}
+
+	if _, err := (DropAction{EntityID: g.PlayerID, ItemName: "Sword", Quantity: 1}).Execute(g); err != nil {
+		t.Fatal(err)
+	}
+	if pos, ok := g.ecs.GetPosition(swordID); !ok || pos != (gruid.Point{X: 5, Y: 5}) {
+		t.Errorf("Expected the same sword entity back on the floor, got %v", pos)
+	}
+	if _, ok := g.ecs.Parent(swordID); ok {
+		t.Error("Expected the dropped sword to have no parent")
+	}
+	if items := g.ecs.GetInventorySafe(g.PlayerID).Items; len(items) != 0 {
+		t.Errorf("Expected an empty inventory, got %+v", items)
+	}
+}
+
+func TestPickup_StacksAndSplits(t *testing.T) {
+	g := createHolderTestGame(1)
+	arrow := components.Item{Name: "Arrow", Type: components.ItemTypeAmmo, Stackable: true, MaxStack: 10}
+	carried := g.giveItem(g.PlayerID, arrow, 4)
+
+	pickedID := g.SpawnItem(arrow, 3, gruid.Point{X: 5, Y: 5})
+	if _, err := (PickupAction{EntityID: g.PlayerID, ItemID: pickedID}).Execute(g); err != nil {
+		t.Fatalf("Expected the arrows to fit the existing stack: %v", err)
+	}
+	g.commands.Apply()
+	if g.ecs.EntityExists(pickedID) || g.ecs.GetItemPickupSafe(carried).Quantity != 7 {
+		t.Errorf("Expected the arrows merged into the carried stack of 7, got %d", g.ecs.GetItemPickupSafe(carried).Quantity)
+	}
+
+	if _, err := (DropAction{EntityID: g.PlayerID, ItemName: "Arrow", Quantity: 2}).Execute(g); err != nil {
+		t.Fatal(err)
+	}
+	if g.ecs.GetItemPickupSafe(carried).Quantity != 5 {
+		t.Errorf("Expected 5 arrows left in the carried stack, got %d", g.ecs.GetItemPickupSafe(carried).Quantity)
+	}
+	var dropped []ecs.EntityID
+	for _, id := range g.spatialGrid.GetEntitiesAt(gruid.Point{X: 5, Y: 5}) {
+		if g.ecs.HasItemPickupSafe(id) {
+			dropped = append(dropped, id)
+		}
+	}
+	if len(dropped) != 1 || dropped[0] == carried || g.ecs.GetItemPickupSafe(dropped[0]).Quantity != 2 {
+		t.Errorf("Expected a new entity of 2 arrows on the floor, got %v", dropped)
+	}
+
+	full := g.SpawnItem(components.Item{Name: "Gem", Type: components.ItemTypeMisc}, 1, gruid.Point{X: 5, Y: 5})
+	if _, err := (PickupAction{EntityID: g.PlayerID, ItemID: full}).Execute(g); err == nil {
+		t.Error("Expected no room for a new stack")
+	}
+}
+
+func TestEquip_MovesEntitiesBetweenSlots(t *testing.T) {
+	g := createHolderTestGame(10)
+	dagger := g.giveItem(g.PlayerID, components.Item{Name: "Dagger", Type: components.ItemTypeWeapon, AttackBonus: 1}, 1)
+	axe := g.giveItem(g.PlayerID, components.Item{Name: "Axe", Type: components.ItemTypeWeapon, AttackBonus: 4}, 1)
+
+	if _, err := (EquipAction{EntityID: g.PlayerID, ItemName: "Dagger"}).Execute(g); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (EquipAction{EntityID: g.PlayerID, ItemName: "Axe"}).Execute(g); err != nil {
+		t.Fatal(err)
+	}
+
+	if equipped, ok := g.ecs.GetEquipped(axe); !ok || equipped.Slot != "Weapon" {
+		t.Errorf("Expected the axe in the weapon slot, got %+v", equipped)
+	}
+	if g.ecs.HasComponent(dagger, components.CEquipped) {
+		t.Error("Expected the dagger unequipped")
+	}
+	if weapon := g.ecs.GetEquipmentSafe(g.PlayerID).Weapon; weapon == nil || weapon.Name != "Axe" {
+		t.Errorf("Expected the equipment to show the axe, got %v", weapon)
+	}
+	inventory := g.ecs.GetInventorySafe(g.PlayerID)
+	if len(inventory.Items) != 1 || inventory.Items[0].Entity != int(dagger) {
+		t.Errorf("Expected only the dagger's entity in the inventory, got %+v", inventory.Items)
+	}
+}
+
+func TestGiveItem_HasNoPosition(t *testing.T) {
+	g := createHolderTestGame(10)
+	gem := g.giveItem(g.PlayerID, components.Item{Name: "Gem", Type: components.ItemTypeMisc}, 1)
+
+	if g.ecs.HasComponent(gem, components.CPosition) {
+		t.Error("Expected the carried gem to have no position")
+	}
+	for _, comp := range []components.ComponentType{components.CItemPickup, components.CName, components.CRenderable} {
+		if !g.ecs.HasComponent(gem, comp) {
+			t.Errorf("Expected the carried gem to keep its %s", comp)
+		}
+	}
+}
+
+func TestMoveIntoChest_LootsItemEntities(t *testing.T) {
+	g := createHolderTestGame(10)
+	g.ecs.AddComponents(g.PlayerID, components.NewTurnActor(100))
+	sword := components.Item{Name: "Sword", Type: components.ItemTypeWeapon}
+	arrow := components.Item{Name: "Arrow", Type: components.ItemTypeAmmo, Stackable: true, MaxStack: 10}
+	chest := g.SpawnChest(gruid.Point{X: 6, Y: 5}, []components.ItemStack{{Item: sword, Quantity: 1}, {Item: arrow, Quantity: 4}})
+	swordID := g.ecs.Children(chest)[0]
+
+	if items := g.ecs.GetInventorySafe(chest).Items; len(items) != 2 {
+		t.Fatalf("Expected the chest to list its 2 stacks, got %+v", items)
+	}
+
+	cost, err := (MoveAction{EntityID: g.PlayerID, Direction: gruid.Point{X: 1}}).Execute(g)
+	if err != nil || cost != 100 {
+		t.Fatalf("Expected looting to cost 100, got %d, %v", cost, err)
+	}
+	g.commands.Apply()
+
+	if pos := g.ecs.GetPositionSafe(g.PlayerID); pos != (gruid.Point{X: 5, Y: 5}) {
+		t.Errorf("Expected the player to stay put, got %v", pos)
+	}
+	if parent, ok := g.ecs.Parent(swordID); !ok || parent != g.PlayerID {
+		t.Errorf("Expected the same sword entity moved to the player, got parent %d", parent)
+	}
+	if items := g.ecs.GetInventorySafe(chest).Items; len(items) != 0 {
+		t.Errorf("Expected an empty chest, got %+v", items)
+	}
+	inventory := g.ecs.GetInventorySafe(g.PlayerID)
+	if got := inventory.GetItemCount("Arrow"); got != 4 {
+		t.Errorf("Expected 4 arrows carried, got %d", got)
+	}
+
+	// An empty chest takes no time
+	if cost, err := (LootAction{EntityID: g.PlayerID, ContainerID: chest}).Execute(g); err != nil || cost != 0 {
+		t.Errorf("Expected a free look into the empty chest, got %d, %v", cost, err)
+	}
+}
@@ -0,0 +1,13 @@
+package game
+
+import (
+	"path"
+
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/assets"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/io"
+)
+
+// loadData parses a data file embedded from assets/data
+func loadData[T any](file string) (T, error) {
+	return io.LoadDataFS[T](assets.Data, path.Join("data", file))
+}
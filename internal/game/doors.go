@@ -0,0 +1,187 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+
+	"codeberg.org/anaseto/gruid"
+	"codeberg.org/anaseto/gruid/rl"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui"
+)
+
+// Door placement odds, in percent
+const (
+	doorChance       = 60 // A doorway gets a door
+	openDoorChance   = 25 // A door starts open
+	lockedDoorChance = 15 // A door starts locked
+)
+
+// Lockpicking odds, in percent
+const (
+	lockpickBaseChance = 20
+	lockpickPerSkill   = 10 // Per point of Skills.Lockpicking
+	lockpickPerDex     = 5  // Per point of dexterity modifier
+	lockpickMinChance  = 5
+	lockpickMaxChance  = 95
+)
+
+// placeDoors puts doors in the doorways of rooms: cells of a room's wall
+// ring that a tunnel went through, with wall on both sides.
+func placeDoors(m *Map, rooms []Rect, rng *rand.Rand) {
+	for _, room := range rooms {
+		for _, p := range room.wallRing() {
+			if !m.isDoorway(p) || rng.Intn(100) >= doorChance {
+				continue
+			}
+			cell := DoorClosedCell
+			switch roll := rng.Intn(100); {
+			case roll < lockedDoorChance:
+				cell = DoorLockedCell
+			case roll < lockedDoorChance+openDoorChance:
+				cell = DoorOpenCell
+			}
+			m.Grid.Set(p, cell)
+		}
+	}
+}
+
+// wallRing returns the walls around the floor carved by createRoom for this
+// rectangle, corners excluded.
+func (r Rect) wallRing() []gruid.Point {
+	var pts []gruid.Point
+	for x := r.X1 + 1; x < r.X2; x++ {
+		pts = append(pts, gruid.Point{X: x, Y: r.Y1}, gruid.Point{X: x, Y: r.Y2})
+	}
+	for y := r.Y1 + 1; y < r.Y2; y++ {
+		pts = append(pts, gruid.Point{X: r.X1, Y: y}, gruid.Point{X: r.X2, Y: y})
+	}
+	return pts
+}
+
+// isDoorway checks if a floor cell is a one-cell passage between walls
+func (m *Map) isDoorway(p gruid.Point) bool {
+	if !m.InBounds(p) || m.Grid.At(p) != FloorCell {
+		return false
+	}
+	horizontal := gruid.Point{X: 1}
+	vertical := gruid.Point{Y: 1}
+	between := func(walls, open gruid.Point) bool {
+		return m.IsWall(p.Add(walls)) && m.IsWall(p.Sub(walls)) &&
+			m.isWalkable(p.Add(open)) && m.isWalkable(p.Sub(open))
+	}
+	return between(horizontal, vertical) || between(vertical, horizontal)
+}
+
+// setCell changes a map tile. Any field of view may include the tile, so
+// all of them are recomputed at the next FOV update.
+func (g *Game) setCell(p gruid.Point, c rl.Cell) {
+	g.dungeon.Grid.Set(p, c)
+	for _, id := range g.ecs.GetEntitiesWithComponent(components.CFOV) {
+		g.staleFOV[id] = true
+	}
+}
+
+// OpenDoorAction opens the door at Pos, unlocking it first if it is locked.
+type OpenDoorAction struct {
+	EntityID ecs.EntityID
+	Pos      gruid.Point
+}
+
+// Execute opens the door. A failed attempt at picking the lock still takes
+// time.
+func (a OpenDoorAction) Execute(g *Game) (cost uint, err error) {
+	if !g.dungeon.InBounds(a.Pos) {
+		return 0, fmt.Errorf("no door at %v", a.Pos)
+	}
+
+	switch g.dungeon.Grid.At(a.Pos) {
+	case DoorClosedCell:
+	case DoorLockedCell:
+		unlocked, err := g.unlockDoor(a.EntityID)
+		if err != nil {
+			return 0, err
+		}
+		if !unlocked {
+			return 100, nil
+		}
+	default:
+		return 0, fmt.Errorf("no closed door at %v", a.Pos)
+	}
+
+	g.setCell(a.Pos, DoorOpenCell)
+	g.doorMessage(a.EntityID, ui.ColorStatusNeutral, "open the door", "opens the door")
+	return 100, nil
+}
+
+// CloseDoorAction closes the open door at Pos.
+type CloseDoorAction struct {
+	EntityID ecs.EntityID
+	Pos      gruid.Point
+}
+
+// Execute closes the door, unless something stands in the doorway.
+func (a CloseDoorAction) Execute(g *Game) (cost uint, err error) {
+	if !g.dungeon.InBounds(a.Pos) || g.dungeon.Grid.At(a.Pos) != DoorOpenCell {
+		return 0, fmt.Errorf("no open door at %v", a.Pos)
+	}
+	if len(g.ecs.EntitiesAt(a.Pos)) > 0 {
+		if a.EntityID == g.PlayerID {
+			g.log.AddMessagef(ui.ColorStatusBad, "Something is in the way.")
+		}
+		return 0, fmt.Errorf("door at %v is blocked", a.Pos)
+	}
+
+	g.setCell(a.Pos, DoorClosedCell)
+	g.doorMessage(a.EntityID, ui.ColorStatusNeutral, "close the door", "closes the door")
+	return 100, nil
+}
+
+// unlockDoor unlocks a door with a carried key, which is used up, or else
+// tries to pick the lock. Entities with neither keys nor skills can't.
+func (g *Game) unlockDoor(id ecs.EntityID) (unlocked bool, err error) {
+	if keyID, ok := g.carriedKey(id); ok {
+		g.consumeItem(keyID, 1)
+		g.doorMessage(id, ui.ColorStatusGood, "unlock the door with a key", "unlocks the door with a key")
+		return true, nil
+	}
+
+	if !g.ecs.HasSkillsSafe(id) {
+		return false, fmt.Errorf("entity %d cannot open locked doors", id)
+	}
+	if g.rand.Intn(100) < g.lockpickChance(id) {
+		g.doorMessage(id, ui.ColorStatusGood, "pick the lock", "picks the lock")
+		return true, nil
+	}
+	g.doorMessage(id, ui.ColorStatusBad, "fail to pick the lock", "fails to pick the lock")
+	return false, nil
+}
+
+// doorMessage logs what an entity does to a door, in the second person for
+// the player and only if seen for others
+func (g *Game) doorMessage(id ecs.EntityID, color gruid.Color, you, others string) {
+	if id == g.PlayerID {
+		g.log.AddMessagef(color, "You %s.", you)
+	} else if g.playerCanSee(id) {
+		g.log.AddMessagef(color, "%s %s.", g.ecs.GetNameSafe(id), others)
+	}
+}
+
+// lockpickChance returns the percent chance the entity picks a lock
+func (g *Game) lockpickChance(id ecs.EntityID) int {
+	chance := lockpickBaseChance +
+		g.ecs.GetSkillsSafe(id).Lockpicking*lockpickPerSkill +
+		attributeModifier(g.effectiveStats(id).Dexterity)*lockpickPerDex
+	return min(max(chance, lockpickMinChance), lockpickMaxChance)
+}
+
+// carriedKey returns a stack of keys the holder carries
+func (g *Game) carriedKey(holder ecs.EntityID) (ecs.EntityID, bool) {
+	for _, stack := range g.ecs.GetInventorySafe(holder).Items {
+		if stack.Item.Type == components.ItemTypeKey && stack.Entity != 0 {
+			return ecs.EntityID(stack.Entity), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,164 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"codeberg.org/anaseto/gruid/rl"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+var eastDoor = gruid.Point{X: 6, Y: 5}
+
+func TestMoveAction_OpensClosedDoor(t *testing.T) {
+	g := createAfflictedTestGame(10)
+	g.ecs.AddComponents(g.PlayerID, components.NewFOVComponent(8, g.dungeon.Width, g.dungeon.Height))
+	g.dungeon.Grid.Set(eastDoor, DoorClosedCell)
+	if !g.dungeon.IsOpaque(eastDoor) {
+		t.Fatal("Expected a closed door to block sight")
+	}
+
+	cost, err := (MoveAction{EntityID: g.PlayerID, Direction: gruid.Point{X: 1}}).Execute(g)
+	if err != nil || cost != 100 {
+		t.Fatalf("Expected the door to open for 100, got %d, %v", cost, err)
+	}
+
+	if pos := g.ecs.GetPositionSafe(g.PlayerID); pos != (gruid.Point{X: 5, Y: 5}) {
+		t.Errorf("Expected the player to stay put while opening, got %v", pos)
+	}
+	if g.dungeon.Grid.At(eastDoor) != DoorOpenCell || g.dungeon.IsOpaque(eastDoor) || !g.dungeon.isWalkable(eastDoor) {
+		t.Error("Expected an open, see-through and walkable door")
+	}
+	if !g.staleFOV[g.PlayerID] {
+		t.Error("Expected opening the door to mark the player's FOV stale")
+	}
+	if msgs := g.log.Messages; len(msgs) == 0 || msgs[len(msgs)-1].Text != "You open the door." {
+		t.Errorf("Expected the player told they opened the door, got %v", msgs)
+	}
+}
+
+func TestMoveAction_QueuedMovesGoThroughDoor(t *testing.T) {
+	g := createAfflictedTestGame(10)
+	md := &Model{game: g}
+	g.dungeon.Grid.Set(eastDoor, DoorClosedCell)
+
+	actor := g.ecs.GetTurnActorSafe(g.PlayerID)
+	for range 3 {
+		actor.AddAction(MoveAction{EntityID: g.PlayerID, Direction: gruid.Point{X: 1}})
+	}
+	md.processTurnQueue()
+
+	// One turn opening the door, two walking through it
+	if pos := g.ecs.GetPositionSafe(g.PlayerID); pos != (gruid.Point{X: 7, Y: 5}) {
+		t.Errorf("Expected the player past the door, got %v", pos)
+	}
+	if !g.waitingForInput || g.turnQueue.CurrentTime != 300 {
+		t.Errorf("Expected the player's turn at time 300, got %d", g.turnQueue.CurrentTime)
+	}
+}
+
+func TestOpenDoor_KeyUnlocksAndIsUsedUp(t *testing.T) {
+	g := createHolderTestGame(10)
+	g.dungeon.Grid.Set(eastDoor, DoorLockedCell)
+	key := components.Item{Name: "Key", Type: components.ItemTypeKey, Stackable: true, MaxStack: 10}
+	keyID := g.giveItem(g.PlayerID, key, 2)
+
+	if _, err := (OpenDoorAction{EntityID: g.PlayerID, Pos: eastDoor}).Execute(g); err != nil {
+		t.Fatal(err)
+	}
+	if g.dungeon.Grid.At(eastDoor) != DoorOpenCell {
+		t.Error("Expected the key to open the locked door")
+	}
+	if got := g.ecs.GetItemPickupSafe(keyID).Quantity; got != 1 {
+		t.Errorf("Expected one key left, got %d", got)
+	}
+}
+
+func TestOpenDoor_Lockpicking(t *testing.T) {
+	g := createHolderTestGame(10)
+	g.dungeon.Grid.Set(eastDoor, DoorLockedCell)
+
+	if _, err := (OpenDoorAction{EntityID: g.PlayerID, Pos: eastDoor}).Execute(g); err == nil {
+		t.Error("Expected no way through without keys or skills")
+	}
+
+	skills := components.NewSkills()
+	g.ecs.AddComponents(g.PlayerID, skills)
+	novice := g.lockpickChance(g.PlayerID)
+	skills.Lockpicking = 10
+	g.ecs.AddComponent(g.PlayerID, components.CSkills, skills)
+	if expert := g.lockpickChance(g.PlayerID); expert != lockpickMaxChance || novice >= expert {
+		t.Errorf("Expected skill to raise the chance from %d to the cap, got %d", novice, expert)
+	}
+
+	g.rand = rand.New(rand.NewSource(1))
+	for range 20 {
+		cost, err := (OpenDoorAction{EntityID: g.PlayerID, Pos: eastDoor}).Execute(g)
+		if err != nil || cost != 100 {
+			t.Fatalf("Expected every attempt to cost 100, got %d, %v", cost, err)
+		}
+		if g.dungeon.Grid.At(eastDoor) == DoorOpenCell {
+			return
+		}
+	}
+	t.Error("Expected an expert to pick the lock")
+}
+
+func TestCloseDoor_BlockedByEntity(t *testing.T) {
+	g := createHolderTestGame(10)
+	g.dungeon.Grid.Set(eastDoor, DoorOpenCell)
+	orc := addTestMonster(g, eastDoor)
+
+	if _, err := (CloseDoorAction{EntityID: g.PlayerID, Pos: eastDoor}).Execute(g); err == nil {
+		t.Error("Expected the orc to keep the door open")
+	}
+
+	g.ecs.RemoveEntity(orc)
+	if _, err := (CloseDoorAction{EntityID: g.PlayerID, Pos: eastDoor}).Execute(g); err != nil {
+		t.Fatal(err)
+	}
+	if g.dungeon.Grid.At(eastDoor) != DoorClosedCell {
+		t.Error("Expected the door closed")
+	}
+}
+
+func TestPlaceDoors_AtCorridorJunctions(t *testing.T) {
+	room := NewRect(1, 1, 5, 4)
+	doorway := gruid.Point{X: room.X2, Y: 3}
+
+	for seed := range int64(20) {
+		m := NewMap(12, 7)
+		m.Grid.Fill(WallCell)
+		createRoom(m.Grid, room)
+		createHTunnel(m.Grid, room.X2, 10, doorway.Y) // A corridor leaving the east wall
+		placeDoors(m, []Rect{room}, rand.New(rand.NewSource(seed)))
+
+		it := m.Grid.Iterator()
+		for it.Next() {
+			if m.isDoor(it.P()) && it.P() != doorway {
+				t.Fatalf("Expected doors only in the doorway, got one at %v", it.P())
+			}
+		}
+		if m.isDoor(doorway) {
+			return
+		}
+	}
+	t.Error("Expected a door in the doorway")
+}
+
+func TestSaveMap_KeepsDoors(t *testing.T) {
+	m := NewMap(5, 5)
+	m.Grid.Fill(WallCell)
+	doors := map[gruid.Point]rl.Cell{{X: 1, Y: 1}: DoorClosedCell, {X: 2, Y: 1}: DoorOpenCell, {X: 3, Y: 1}: DoorLockedCell}
+	for p, c := range doors {
+		m.Grid.Set(p, c)
+	}
+
+	loaded := loadMap(saveMap(m))
+	for p, c := range doors {
+		if got := loaded.Grid.At(p); got != c {
+			t.Errorf("Expected cell %d at %v, got %d", c, p, got)
+		}
+	}
+}
@@ -98,13 +98,18 @@ type CombatEvent struct {
 	TargetName   string
 	Damage       int
 	Critical     bool
+	Missed       bool
 	Timestamp    time.Time
 }
 
 func (e CombatEvent) EventType() string { return "combat" }
 
 func (e CombatEvent) Execute(g *Game) error {
-	slog.Debug("Processing combat event", "attacker", e.AttackerName, "target", e.TargetName, "damage", e.Damage, "critical", e.Critical)
+	slog.Debug("Processing combat event", "attacker", e.AttackerName, "target", e.TargetName, "damage", e.Damage, "critical", e.Critical, "missed", e.Missed)
+
+	if e.Missed {
+		return nil
+	}
 
 	// Apply combat consequences
 	if e.Critical {
@@ -232,23 +237,12 @@ func (g *Game) tryAutoEquip(itemName string) error {
 	}
 
 	// Only auto-equip if no equipment in that slot
-	switch foundItem.Type {
-	case components.ItemTypeWeapon:
-		equipment := g.ecs.GetEquipmentSafe(g.PlayerID)
-		if equipment.Weapon == nil {
-			g.log.AddMessagef(ui.ColorStatusGood, "Auto-equipping %s.", itemName)
-			// Queue equip action
-			actor, _ := g.ecs.GetTurnActor(g.PlayerID)
-			actor.AddAction(EquipAction{EntityID: g.PlayerID, ItemName: itemName})
-		}
-	case components.ItemTypeArmor:
-		equipment := g.ecs.GetEquipmentSafe(g.PlayerID)
-		if equipment.Armor == nil {
-			g.log.AddMessagef(ui.ColorStatusGood, "Auto-equipping %s.", itemName)
-			// Queue equip action
-			actor, _ := g.ecs.GetTurnActor(g.PlayerID)
-			actor.AddAction(EquipAction{EntityID: g.PlayerID, ItemName: itemName})
-		}
+	equipment := g.ecs.GetEquipmentSafe(g.PlayerID)
+	if slot := foundItem.EquipSlot(); slot != "" && equipment.IsFree(slot) {
+		g.log.AddMessagef(ui.ColorStatusGood, "Auto-equipping %s.", itemName)
+		// Queue equip action
+		actor, _ := g.ecs.GetTurnActor(g.PlayerID)
+		actor.AddAction(EquipAction{EntityID: g.PlayerID, ItemName: itemName})
 	}
 
 	return nil
@@ -291,7 +285,7 @@ func (g *Game) TriggerPickupEvent(entityID, itemID ecs.EntityID, quantity int) {
 }
 
 // TriggerCombatEvent creates and queues a combat event
-func (g *Game) TriggerCombatEvent(attackerID, targetID ecs.EntityID, damage int, critical bool) {
+func (g *Game) TriggerCombatEvent(attackerID, targetID ecs.EntityID, damage int, critical, missed bool) {
 	attackerName := g.ecs.GetNameSafe(attackerID)
 	targetName := g.ecs.GetNameSafe(targetID)
 
@@ -302,6 +296,7 @@ func (g *Game) TriggerCombatEvent(attackerID, targetID ecs.EntityID, damage int,
 		TargetName:   targetName,
 		Damage:       damage,
 		Critical:     critical,
+		Missed:       missed,
 		Timestamp:    time.Now(),
 	}
 
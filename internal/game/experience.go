@@ -122,16 +122,21 @@ func (es *ExperienceSystem) handleLevelUp(entityID ecs.EntityID) {
 func (es *ExperienceSystem) GetExperienceForKill(killerID, victimID ecs.EntityID) int {
 	baseXP := 10
 
-	// Bonus XP based on victim's level if they have experience
-	if es.game.ecs.HasExperienceSafe(victimID) {
-		victimExp := es.game.ecs.GetExperienceSafe(victimID)
-		baseXP += victimExp.Level * 5
-	}
+	if reward, ok := es.game.ecs.GetReward(victimID); ok {
+		// Monsters built from templates carry their XP value
+		baseXP = reward.XP
+	} else {
+		// Bonus XP based on victim's level if they have experience
+		if es.game.ecs.HasExperienceSafe(victimID) {
+			victimExp := es.game.ecs.GetExperienceSafe(victimID)
+			baseXP += victimExp.Level * 5
+		}
 
-	// Bonus XP based on victim's max health
-	if es.game.ecs.HasHealthSafe(victimID) {
-		victimHealth := es.game.ecs.GetHealthSafe(victimID)
-		baseXP += victimHealth.MaxHP
+		// Bonus XP based on victim's max health
+		if es.game.ecs.HasHealthSafe(victimID) {
+			victimHealth := es.game.ecs.GetHealthSafe(victimID)
+			baseXP += victimHealth.MaxHP
+		}
 	}
 
 	// Level difference modifier
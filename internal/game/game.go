@@ -1,6 +1,7 @@
 package game
 
 import (
+	"log/slog"
 	"math/rand"
 	"time"
 
@@ -37,41 +38,63 @@ type Game struct {
 	Depth           int
 	State           GameState
 	waitingForInput bool
+	turnsPending    bool          // The action phase yielded before the player's turn
+	turnBudget      time.Duration // Longest the action phase runs before yielding
+	stalledTurns    int           // Turns skipped because the turn loop made no progress
 
 	dungeon        *Map
+	levels         map[int]*Level // Visited floors other than the current one
 	ecs            *ecs.ECS
 	spatialGrid    *SpatialGrid
+	commands       *ecs.CommandBuffer    // Structural changes deferred to the next sync point
+	staleFOV       map[ecs.EntityID]bool // Entities whose FOV must be recomputed
 	pathfindingMgr *PathfindingManager
 	model          *Model
 
-	PlayerID  ecs.EntityID
-	turnQueue *turn.TurnQueue
-	log       *log.MessageLog
-	stats     *GameStats
+	PlayerID   ecs.EntityID
+	turnQueue  *turn.TurnQueue
+	log        *log.MessageLog
+	stats      *GameStats
+	spells     map[string]Spell  // Spell definitions by ID
+	items      *ItemCatalog      // Item definitions loaded from data
+	monsters   *MonsterCatalog   // Monster templates and spawn tables loaded from data
+	blueprints *BlueprintCatalog // Entity archetypes spawned entities are built from
+	systems    *Scheduler        // Game logic, run phase by phase each round
 
-	rand *rand.Rand
+	Seed       int64           // Seed of the run's random source
+	rand       *rand.Rand      // All game randomness goes through this
+	randSource *countingSource // Backing source of rand, tracks draws for saving
 }
 
 func NewGame() *Game {
-	return &Game{
+	g := &Game{
 		State:       GameStateRunning,
-		ecs:         ecs.NewECS(),
+		levels:      make(map[int]*Level),
 		turnQueue:   turn.NewTurnQueue(),
 		log:         log.NewMessageLog(),
 		spatialGrid: NewSpatialGrid(config.DungeonWidth, config.DungeonHeight),
+		spells:      LoadSpells(),
+		items:       LoadItems(),
+		monsters:    LoadMonsters(),
+		blueprints:  LoadBlueprints(),
+		systems:     newGameScheduler(),
+		turnBudget:  defaultTurnBudget,
 		stats: &GameStats{
 			StartTime: time.Now(),
 		},
 	}
+	g.setWorld(ecs.NewECS())
+	g.SetSeed(time.Now().UnixNano())
+
+	return g
 }
 
 // InitLevel initializes a new game level
 func (g *Game) InitLevel() {
-	if g.rand == nil {
-		g.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
-	}
+	slog.Info("Initializing level", "seed", g.Seed)
 
 	g.Depth = 1
+	g.levels = make(map[int]*Level)
 
 	// Clear the spatial grid for the new level
 	g.spatialGrid.Clear()
@@ -81,9 +104,8 @@ func (g *Game) InitLevel() {
 	// Initialize pathfinding manager after map is created
 	g.pathfindingMgr = NewPathfindingManager(g)
 
-	items := CreateBasicItems()
-	playerStart := g.dungeon.generateMap(g, config.DungeonWidth, config.DungeonHeight, items)
-	g.SpawnPlayer(playerStart, items)
+	playerStart := g.dungeon.generateMap(g, g.monsters, g.items)
+	g.SpawnPlayer(playerStart, g.items)
 }
 
 func (g *Game) GetPlayerPosition() gruid.Point {
@@ -169,6 +191,11 @@ func (g *Game) GetPlayerID() ecs.EntityID {
 	return g.PlayerID
 }
 
+// GetSeed returns the run's random seed for UI access
+func (g *Game) GetSeed() int64 {
+	return g.Seed
+}
+
 // GetDepth returns the current dungeon depth for UI access
 func (g *Game) GetDepth() int {
 	return g.Depth
@@ -0,0 +1,79 @@
+package game
+
+import (
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// setWorld makes world the active ECS and registers the hooks that keep the
+// spatial grid, turn queue, FOV and containers in sync with its components.
+func (g *Game) setWorld(world *ecs.ECS) {
+	g.ecs = world
+	g.commands = ecs.NewCommandBuffer(world)
+	g.spatialGrid.Clear()
+	g.staleFOV = make(map[ecs.EntityID]bool)
+
+	// Positioned entities live in the spatial grid, except corpses, which
+	// nothing collides with or targets
+	world.OnAdd(components.CPosition, func(id ecs.EntityID, comp any) {
+		if !world.HasComponent(id, components.CCorpseTag) {
+			g.spatialGrid.Add(id, comp.(gruid.Point))
+		}
+		g.staleFOV[id] = true
+	})
+	world.OnChange(components.CPosition, func(id ecs.EntityID, old, new any) {
+		if !world.HasComponent(id, components.CCorpseTag) {
+			g.spatialGrid.Move(id, old.(gruid.Point), new.(gruid.Point))
+		}
+		g.staleFOV[id] = true
+	})
+	world.OnRemove(components.CPosition, func(id ecs.EntityID, comp any) {
+		g.spatialGrid.Remove(id, comp.(gruid.Point))
+		delete(g.staleFOV, id)
+	})
+	world.OnAdd(components.CCorpseTag, func(id ecs.EntityID, _ any) {
+		if pos, ok := world.GetPosition(id); ok {
+			g.spatialGrid.Remove(id, pos)
+		}
+	})
+
+	// Entities that can no longer act leave the turn queue. Scheduling stays
+	// explicit, since when a new actor first moves depends on how it arrived.
+	world.OnRemove(components.CTurnActor, func(id ecs.EntityID, _ any) {
+		g.turnQueue.Remove(id)
+	})
+
+	// Fields of view are recomputed only after their owner moves
+	world.OnAdd(components.CFOV, func(id ecs.EntityID, _ any) {
+		g.staleFOV[id] = true
+	})
+	world.OnRemove(components.CFOV, func(id ecs.EntityID, _ any) {
+		delete(g.staleFOV, id)
+	})
+
+	// Inventories and equipment follow the item entities their owner holds
+	holderOf := func(comp any) ecs.EntityID {
+		return ecs.EntityID(comp.(components.Parent).ID)
+	}
+	world.OnAdd(components.CParent, func(_ ecs.EntityID, comp any) {
+		g.syncContainer(holderOf(comp))
+	})
+	world.OnChange(components.CParent, func(_ ecs.EntityID, old, new any) {
+		g.syncContainer(holderOf(old))
+		g.syncContainer(holderOf(new))
+	})
+	world.OnRemove(components.CParent, func(_ ecs.EntityID, comp any) {
+		g.syncContainer(holderOf(comp))
+	})
+	for _, compType := range []components.ComponentType{components.CItemPickup, components.CEquipped} {
+		syncHolder := func(id ecs.EntityID) {
+			if holder, ok := world.Parent(id); ok {
+				g.syncContainer(holder)
+			}
+		}
+		world.OnAdd(compType, func(id ecs.EntityID, _ any) { syncHolder(id) })
+		world.OnChange(compType, func(id ecs.EntityID, _, _ any) { syncHolder(id) })
+		world.OnRemove(compType, func(id ecs.EntityID, _ any) { syncHolder(id) })
+	}
+}
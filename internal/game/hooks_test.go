@@ -0,0 +1,76 @@
+package game
+
+import (
+	"slices"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func queuedEntities(g *Game) []ecs.EntityID {
+	var ids []ecs.EntityID
+	for _, entry := range g.turnQueue.Snapshot() {
+		ids = append(ids, entry.EntityID)
+	}
+	return ids
+}
+
+func TestHooks_SpatialGridAndTurnQueueFollowECS(t *testing.T) {
+	g := createTestGame()
+	tmpl := MonsterTemplate{ID: "rat", Name: "Rat", Glyph: "r", Speed: 100, HP: 3, FOVRange: 4}
+	start, next := gruid.Point{X: 4, Y: 4}, gruid.Point{X: 5, Y: 4}
+
+	id := g.SpawnMonster(tmpl, start)
+	if got := g.spatialGrid.GetEntitiesAt(start); !slices.Equal(got, []ecs.EntityID{id}) {
+		t.Fatalf("Expected the spawned monster in the grid, got %v", got)
+	}
+
+	if err := g.ecs.MoveEntity(id, next); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.spatialGrid.GetEntitiesAt(start); len(got) != 0 {
+		t.Errorf("Expected the old cell empty, got %v", got)
+	}
+	if got := g.spatialGrid.GetEntitiesAt(next); !slices.Equal(got, []ecs.EntityID{id}) {
+		t.Errorf("Expected the monster in its new cell, got %v", got)
+	}
+
+	// Dying takes it out of both the grid and the turn queue
+	g.handleEntityDeath(id, "Rat", 0)
+	g.commands.Apply()
+	if got := g.spatialGrid.GetEntitiesAt(next); len(got) != 0 {
+		t.Errorf("Expected the corpse left out of the grid, got %v", got)
+	}
+	if slices.Contains(queuedEntities(g), id) {
+		t.Error("Expected the corpse out of the turn queue")
+	}
+}
+
+func TestHooks_FOVRecomputedOnlyWhenStale(t *testing.T) {
+	g := createTestGame()
+	g.PlayerID = g.ecs.AddEntity()
+	fov := components.NewFOVComponent(4, g.dungeon.Width, g.dungeon.Height)
+	g.ecs.AddComponents(g.PlayerID, gruid.Point{X: 2, Y: 2}, fov)
+
+	g.FOVSystem()
+	if !fov.IsVisible(gruid.Point{X: 2, Y: 2}, g.dungeon.Width) {
+		t.Fatal("Expected the player to see their own cell")
+	}
+
+	// Unmoved, the player's FOV is left alone
+	fov.ClearVisible()
+	g.FOVSystem()
+	if fov.IsVisible(gruid.Point{X: 2, Y: 2}, g.dungeon.Width) {
+		t.Error("Expected no recomputation without movement")
+	}
+
+	if err := g.ecs.MoveEntity(g.PlayerID, gruid.Point{X: 7, Y: 7}); err != nil {
+		t.Fatal(err)
+	}
+	g.FOVSystem()
+	if !fov.IsVisible(gruid.Point{X: 7, Y: 7}, g.dungeon.Width) {
+		t.Error("Expected the FOV recomputed after moving")
+	}
+}
@@ -25,6 +25,12 @@ var KEYS_NORMAL = map[gruid.Key]playerAction{
 	"i":                 ActionInventory,
 	"u":                 ActionUseItem,
 	"e":                 ActionEquip,
+	"f":                 ActionFire,
+	"z":                 ActionCast,
+	"o":                 ActionOpenDoor,
+	"c":                 ActionCloseDoor,
+	">":                 ActionDescend,
+	"<":                 ActionAscend,
 	".":                 ActionWait,
 	gruid.KeySpace:      ActionWait,
 	"S":                 ActionSave,
@@ -38,6 +44,29 @@ var KEYS_NORMAL = map[gruid.Key]playerAction{
 	"T":                 ActionToggleTiles,
 }
 
+// KEYS_TARGETING defines key bindings while aiming a ranged attack
+var KEYS_TARGETING = map[gruid.Key]playerAction{
+	gruid.KeyArrowLeft:  ActionW,
+	gruid.KeyArrowDown:  ActionS,
+	gruid.KeyArrowUp:    ActionN,
+	gruid.KeyArrowRight: ActionE,
+	"h":                 ActionW,
+	"j":                 ActionS,
+	"k":                 ActionN,
+	"l":                 ActionE,
+	"4":                 ActionW,
+	"2":                 ActionS,
+	"8":                 ActionN,
+	"6":                 ActionE,
+	gruid.KeyTab:        ActionTargetNext,
+	"n":                 ActionTargetNext,
+	"p":                 ActionTargetPrev,
+	gruid.KeyEnter:      ActionTargetConfirm,
+	"f":                 ActionTargetConfirm,
+	".":                 ActionTargetConfirm,
+	gruid.KeyEscape:     ActionCloseScreen,
+}
+
 // KEYS_INVENTORY_SCREEN defines key bindings for inventory screen
 var KEYS_INVENTORY_SCREEN = map[gruid.Key]playerAction{
 	gruid.KeyEscape:    ActionCloseScreen,
@@ -275,7 +275,7 @@ func TestScreenModeActionProcessing(t *testing.T) {
 
 	t.Run("screen_mode_calls_end_turn_when_switching_to_normal", func(t *testing.T) {
 		grid := gruid.NewGrid(80, 24)
-		model := NewModel(grid)
+		model := NewModel(grid, 1)
 
 		// Set up initial state
 		initialMode := modeInventory
@@ -302,7 +302,7 @@ func TestScreenModeActionProcessing(t *testing.T) {
 
 	t.Run("screen_mode_stays_in_screen_when_again_true", func(t *testing.T) {
 		grid := gruid.NewGrid(80, 24)
-		model := NewModel(grid)
+		model := NewModel(grid, 1)
 
 		// Set up initial state
 		model.mode = modeInventory
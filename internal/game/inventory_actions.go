@@ -1,8 +1,10 @@
 package game
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
@@ -21,28 +23,18 @@ func (a PickupAction) Execute(g *Game) (cost uint, err error) {
 		return 0, fmt.Errorf("entity %d has no inventory", a.EntityID)
 	}
 
-	// Check if item exists and has ItemPickup component
-	if !g.ecs.EntityExists(a.ItemID) || !g.ecs.HasItemPickupSafe(a.ItemID) {
+	// Check if item exists, has ItemPickup component and lies on the floor
+	if !g.ecs.EntityExists(a.ItemID) || !g.ecs.HasItemPickupSafe(a.ItemID) || g.ecs.HasComponent(a.ItemID, components.CParent) {
 		return 0, fmt.Errorf("item %d does not exist or is not pickupable", a.ItemID)
 	}
 
 	// Get components
-	inventory := g.ecs.GetInventorySafe(a.EntityID)
 	pickup := g.ecs.GetItemPickupSafe(a.ItemID)
 	entityName := g.ecs.GetNameSafe(a.EntityID)
 
-	// Try to add item to inventory
-	if inventory.AddItem(pickup.Item, pickup.Quantity) {
-		// Update inventory component
-		g.ecs.AddComponent(a.EntityID, components.CInventory, inventory)
-
-		// Grab position before we delete the entity
-		itemPos := g.ecs.GetPositionSafe(a.ItemID)
-
-		// Remove from world & grid
-		g.ecs.RemoveEntity(a.ItemID)
-		g.spatialGrid.Remove(a.ItemID, itemPos)
-
+	// Try to move the item into the inventory, which also takes it out of
+	// the grid
+	if g.storeItem(a.EntityID, a.ItemID) {
 		// Track item collection statistics
 		if a.EntityID == g.PlayerID {
 			g.IncrementItemsCollected()
@@ -67,6 +59,64 @@ func (a PickupAction) Execute(g *Game) (cost uint, err error) {
 	return 0, fmt.Errorf("inventory full") // No cost if inventory is full and pickup fails
 }
 
+// LootAction represents taking everything out of a container, such as a
+// chest. The item entities move into the looter's inventory as they are.
+type LootAction struct {
+	EntityID    ecs.EntityID
+	ContainerID ecs.EntityID
+}
+
+func (a LootAction) Execute(g *Game) (cost uint, err error) {
+	if !g.ecs.HasInventorySafe(a.EntityID) {
+		return 0, fmt.Errorf("entity %d has no inventory", a.EntityID)
+	}
+	if !g.ecs.HasInventorySafe(a.ContainerID) {
+		return 0, fmt.Errorf("entity %d is not a container", a.ContainerID)
+	}
+	isPlayer := a.EntityID == g.PlayerID
+	containerName := strings.ToLower(g.ecs.GetNameSafe(a.ContainerID))
+
+	var taken []string
+	left := false
+	for _, itemID := range g.ecs.Children(a.ContainerID) {
+		pickup, ok := g.ecs.GetItemPickup(itemID)
+		if !ok {
+			continue
+		}
+		if !g.storeItem(a.EntityID, itemID) {
+			left = true
+			continue
+		}
+		if isPlayer {
+			g.IncrementItemsCollected()
+		}
+		g.TriggerPickupEvent(a.EntityID, itemID, pickup.Quantity)
+		taken = append(taken, pickup.Item.Name)
+	}
+
+	switch {
+	case len(taken) > 0:
+		if isPlayer {
+			g.log.AddMessagef(ui.ColorStatusGood, "You take %s from the %s.", strings.Join(taken, ", "), containerName)
+			if left {
+				g.log.AddMessagef(ui.ColorStatusBad, "Your inventory is full!")
+			}
+		}
+		slog.Debug("Looted container", "entity", g.ecs.GetNameSafe(a.EntityID), "container", a.ContainerID, "items", taken)
+		return 100, nil
+	case left:
+		if isPlayer {
+			g.log.AddMessagef(ui.ColorStatusBad, "Your inventory is full!")
+		}
+		return 0, fmt.Errorf("inventory full")
+	}
+
+	if isPlayer {
+		g.log.AddMessagef(ui.ColorStatusNeutral, "The %s is empty.", containerName)
+	}
+	return 0, nil // Looking into an empty container is free
+}
+
 // DropAction represents an action to drop an item
 type DropAction struct {
 	EntityID ecs.EntityID
@@ -81,62 +131,28 @@ func (a DropAction) Execute(g *Game) (cost uint, err error) {
 	}
 
 	// Get components
-	inventory := g.ecs.GetInventorySafe(a.EntityID)
 	entityPos := g.ecs.GetPositionSafe(a.EntityID)
 	entityName := g.ecs.GetNameSafe(a.EntityID)
 
 	// Check if entity has the item
-	if !inventory.HasItem(a.ItemName, a.Quantity) {
+	itemID, ok := g.carriedItem(a.EntityID, a.ItemName, a.Quantity)
+	if !ok {
 		if a.EntityID == g.PlayerID {
 			g.log.AddMessagef(ui.ColorStatusBad, "You don't have enough %s to drop.", a.ItemName)
 		}
 		return 0, fmt.Errorf("not enough items to drop")
 	}
 
-	// Find the item in inventory to get its details
-	var itemToDrop components.Item
-	found := false
-	for _, stack := range inventory.Items {
-		if stack.Item.Name == a.ItemName {
-			itemToDrop = stack.Item
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		return 0, fmt.Errorf("item %s not found in inventory", a.ItemName)
-	}
-
-	// Remove item from inventory
-	if inventory.RemoveItem(a.ItemName, a.Quantity) {
-		// Update inventory component
-		g.ecs.AddComponent(a.EntityID, components.CInventory, inventory)
-
-		// Create item pickup entity
-		itemEntity := g.ecs.AddEntity()
-		pickup := components.NewItemPickup(itemToDrop, a.Quantity)
-
-		g.ecs.AddComponents(itemEntity,
-			entityPos,
-			pickup,
-			components.Renderable{Glyph: itemToDrop.Glyph, Color: itemToDrop.Color},
-			components.Name{Name: itemToDrop.Name},
-		)
+	// Put the dropped part of the stack on the floor, keeping its entity
+	g.dropItem(g.splitItem(itemID, a.Quantity), entityPos)
 
-		// Add to spatial grid
-		g.spatialGrid.Add(itemEntity, entityPos)
-
-		// Log message
-		if a.EntityID == g.PlayerID {
-			g.log.AddMessagef(ui.ColorStatusGood, "You drop %s.", a.ItemName)
-		}
-
-		slog.Debug("Dropped item", "entity", entityName, "item", a.ItemName, "quantity", a.Quantity)
-		return 100, nil
+	// Log message
+	if a.EntityID == g.PlayerID {
+		g.log.AddMessagef(ui.ColorStatusGood, "You drop %s.", a.ItemName)
 	}
 
-	return 0, fmt.Errorf("failed to remove item from inventory")
+	slog.Debug("Dropped item", "entity", entityName, "item", a.ItemName, "quantity", a.Quantity)
+	return 100, nil
 }
 
 // UseItemAction represents an action to use a consumable item
@@ -151,32 +167,17 @@ func (a UseItemAction) Execute(g *Game) (cost uint, err error) {
 		return 0, fmt.Errorf("entity %d has no inventory", a.EntityID)
 	}
 
-	// Get components
-	inventory := g.ecs.GetInventorySafe(a.EntityID)
 	entityName := g.ecs.GetNameSafe(a.EntityID)
 
 	// Check if entity has the item
-	if !inventory.HasItem(a.ItemName, 1) {
+	itemID, ok := g.carriedItem(a.EntityID, a.ItemName, 1)
+	if !ok {
 		if a.EntityID == g.PlayerID {
 			g.log.AddMessagef(ui.ColorStatusBad, "You don't have %s.", a.ItemName)
 		}
 		return 0, fmt.Errorf("item not found in inventory")
 	}
-
-	// Find the item to check if it's consumable
-	var itemToUse components.Item
-	found := false
-	for _, stack := range inventory.Items {
-		if stack.Item.Name == a.ItemName {
-			itemToUse = stack.Item
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		return 0, fmt.Errorf("item %s not found in inventory", a.ItemName)
-	}
+	itemToUse := g.ecs.GetItemPickupSafe(itemID).Item
 
 	// Check if item is consumable
 	if itemToUse.Type != components.ItemTypeConsumable {
@@ -186,36 +187,16 @@ func (a UseItemAction) Execute(g *Game) (cost uint, err error) {
 		return 0, fmt.Errorf("item is not consumable")
 	}
 
-	// Apply item effects (simplified - just health potion for now)
-	if itemToUse.Name == "Health Potion" {
-		if g.ecs.HasHealthSafe(a.EntityID) {
-			health := g.ecs.GetHealthSafe(a.EntityID)
-			healAmount := 10 // Fixed heal amount for now
-			health.CurrentHP += healAmount
-			if health.CurrentHP > health.MaxHP {
-				health.CurrentHP = health.MaxHP
-			}
-			g.ecs.AddComponent(a.EntityID, components.CHealth, health)
-
-			if a.EntityID == g.PlayerID {
-				g.log.AddMessagef(ui.ColorStatusGood, "You feel better! (+%d HP)", healAmount)
-			}
-		}
-	}
-
-	// Remove item from inventory
-	if inventory.RemoveItem(a.ItemName, 1) {
-		g.ecs.AddComponent(a.EntityID, components.CInventory, inventory)
+	// Apply the item's effects, then use it up
+	g.applyItemEffects(a.EntityID, itemToUse)
+	g.consumeItem(itemID, 1)
 
-		if a.EntityID == g.PlayerID {
-			g.log.AddMessagef(ui.ColorStatusGood, "You use %s.", a.ItemName)
-		}
-
-		slog.Debug("Used item", "entity", entityName, "item", a.ItemName)
-		return 100, nil
+	if a.EntityID == g.PlayerID {
+		g.log.AddMessagef(ui.ColorStatusGood, "You use %s.", a.ItemName)
 	}
 
-	return 0, fmt.Errorf("failed to remove item from inventory")
+	slog.Debug("Used item", "entity", entityName, "item", a.ItemName)
+	return 100, nil
 }
 
 // EquipAction represents an action to equip an item
@@ -230,22 +211,10 @@ func (a EquipAction) Execute(g *Game) (cost uint, err error) {
 		return 0, fmt.Errorf("entity %d missing inventory or equipment", a.EntityID)
 	}
 
-	// Get components
-	inventory := g.ecs.GetInventorySafe(a.EntityID)
-	equipment := g.ecs.GetEquipmentSafe(a.EntityID)
 	entityName := g.ecs.GetNameSafe(a.EntityID)
 
 	// Find item in inventory
-	var itemToEquip components.Item
-	found := false
-	for _, stack := range inventory.Items {
-		if stack.Item.Name == a.ItemName {
-			itemToEquip = stack.Item
-			found = true
-			break
-		}
-	}
-
+	itemID, found := g.carriedItem(a.EntityID, a.ItemName, 1)
 	if !found {
 		if a.EntityID == g.PlayerID {
 			g.log.AddMessagef(ui.ColorStatusBad, "You don't have %s.", a.ItemName)
@@ -254,46 +223,32 @@ func (a EquipAction) Execute(g *Game) (cost uint, err error) {
 	}
 
 	// Check if item is equippable
-	if itemToEquip.Type != components.ItemTypeWeapon && itemToEquip.Type != components.ItemTypeArmor {
+	if g.ecs.GetItemPickupSafe(itemID).Item.EquipSlot() == "" {
 		if a.EntityID == g.PlayerID {
 			g.log.AddMessagef(ui.ColorStatusBad, "You can't equip %s.", a.ItemName)
 		}
 		return 0, fmt.Errorf("item is not equippable")
 	}
 
-	// Try to equip item
-	oldItem := equipment.EquipItem(itemToEquip)
-
-	// Remove item from inventory
-	if !inventory.RemoveItem(a.ItemName, 1) {
-		return 0, fmt.Errorf("failed to remove item from inventory")
-	}
-
-	// If there was an old item, add it back to inventory
-	if oldItem != nil {
-		if !inventory.AddItem(*oldItem, 1) {
-			// Inventory full, drop the old item
-			dropAction := DropAction{
-				EntityID: a.EntityID,
-				ItemName: oldItem.Name,
-				Quantity: 1,
-			}
-
-			// Inventory is full – just drop the old item directly
-			if _, err := dropAction.Execute(g); err != nil {
-				return 0, fmt.Errorf("failed to drop old item: %w", err)
-			}
+	// Try to equip item. Displaced items go back to the inventory, or on
+	// the floor if it is full.
+	removed, err := g.equipItem(a.EntityID, itemID)
+	if err != nil {
+		if a.EntityID == g.PlayerID && errors.Is(err, components.ErrOffhandBlocked) {
+			g.log.AddMessagef(ui.ColorStatusBad, "You need a free hand to equip %s.", a.ItemName)
 		}
+		return 0, err
 	}
 
-	// Update components
-	g.ecs.AddComponent(a.EntityID, components.CInventory, inventory)
-	g.ecs.AddComponent(a.EntityID, components.CEquipment, equipment)
+	var removedNames []string
+	for _, oldID := range removed {
+		removedNames = append(removedNames, g.ecs.GetItemPickupSafe(oldID).Item.Name)
+	}
 
 	// Log message
 	if a.EntityID == g.PlayerID {
-		if oldItem != nil {
-			g.log.AddMessagef(ui.ColorStatusGood, "You equip %s (unequipped %s).", a.ItemName, oldItem.Name)
+		if len(removedNames) > 0 {
+			g.log.AddMessagef(ui.ColorStatusGood, "You equip %s (unequipped %s).", a.ItemName, strings.Join(removedNames, ", "))
 		} else {
 			g.log.AddMessagef(ui.ColorStatusGood, "You equip %s.", a.ItemName)
 		}
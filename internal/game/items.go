@@ -0,0 +1,334 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"codeberg.org/anaseto/gruid"
+	"codeberg.org/anaseto/gruid/paths"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui"
+)
+
+// ItemsFile is the data file, under assets/data, defining every item
+const ItemsFile = "items.json"
+
+// teleportAttempts bounds the random search for a free teleport destination
+const teleportAttempts = 200
+
+// itemTypes maps the type names used in data files to item types
+var itemTypes = map[string]components.ItemType{
+	"weapon":     components.ItemTypeWeapon,
+	"armor":      components.ItemTypeArmor,
+	"consumable": components.ItemTypeConsumable,
+	"misc":       components.ItemTypeMisc,
+	"ammo":       components.ItemTypeAmmo,
+	"accessory":  components.ItemTypeAccessory,
+	"key":        components.ItemTypeKey,
+}
+
+// equipSlots lists the slot names accepted in data files
+var equipSlots = []components.EquipSlot{
+	components.SlotWeapon, components.SlotOffhand, components.SlotHead, components.SlotBody,
+	components.SlotHands, components.SlotFeet, components.SlotRing, components.SlotAmulet,
+}
+
+// ItemDefinition is the data-file form of an item, along with where it
+// appears in the game.
+type ItemDefinition struct {
+	Name         string                  `json:"name"`
+	Description  string                  `json:"description"`
+	Type         string                  `json:"type"`
+	Glyph        string                  `json:"glyph"`
+	Color        string                  `json:"color"` // "#RRGGBB"
+	Value        int                     `json:"value"`
+	Stackable    bool                    `json:"stackable,omitempty"`
+	MaxStack     int                     `json:"max_stack,omitempty"`
+	Weight       int                     `json:"weight,omitempty"`
+	AttackBonus  int                     `json:"attack_bonus,omitempty"`
+	DefenseBonus int                     `json:"defense_bonus,omitempty"`
+	Range        int                     `json:"range,omitempty"`
+	AmmoType     string                  `json:"ammo_type,omitempty"`
+	Effects      []components.ItemEffect `json:"effects,omitempty"`
+
+	Slot          string           `json:"slot,omitempty"` // Defaults to weapon for weapons and body for armor
+	TwoHanded     bool             `json:"two_handed,omitempty"`
+	AccuracyBonus int              `json:"accuracy_bonus,omitempty"`
+	DodgeBonus    int              `json:"dodge_bonus,omitempty"`
+	StatBonus     components.Stats `json:"stat_bonus"` // e.g. {"strength": 1}
+
+	SpawnWeight   int  `json:"spawn_weight,omitempty"`   // Relative odds of lying on a dungeon floor, 0 for never
+	StartQuantity int  `json:"start_quantity,omitempty"` // How many the player starts with
+	StartEquipped bool `json:"start_equipped,omitempty"` // Whether the player starts with it equipped
+}
+
+// toItem converts the definition into an item, validating its fields
+func (d ItemDefinition) toItem() (components.Item, error) {
+	if d.Name == "" {
+		return components.Item{}, fmt.Errorf("item must have a name")
+	}
+
+	itemType, ok := itemTypes[d.Type]
+	if !ok {
+		return components.Item{}, fmt.Errorf("item %q has unknown type %q", d.Name, d.Type)
+	}
+	if utf8.RuneCountInString(d.Glyph) != 1 {
+		return components.Item{}, fmt.Errorf("item %q needs a single-character glyph", d.Name)
+	}
+	color, err := parseHexColor(d.Color)
+	if err != nil {
+		return components.Item{}, fmt.Errorf("item %q has invalid color %q", d.Name, d.Color)
+	}
+	if d.Weight < 0 {
+		return components.Item{}, fmt.Errorf("item %q has negative weight", d.Name)
+	}
+	if d.Stackable && d.MaxStack <= 0 {
+		return components.Item{}, fmt.Errorf("stackable item %q needs a positive max_stack", d.Name)
+	}
+	slot := components.EquipSlot(d.Slot)
+	if d.Slot != "" && !slices.Contains(equipSlots, slot) {
+		return components.Item{}, fmt.Errorf("item %q has unknown slot %q", d.Name, d.Slot)
+	}
+	if d.TwoHanded && d.Type != "weapon" {
+		return components.Item{}, fmt.Errorf("only weapons can be two-handed, not %q", d.Name)
+	}
+	for _, effect := range d.Effects {
+		if err := validateItemEffect(effect); err != nil {
+			return components.Item{}, fmt.Errorf("item %q: %w", d.Name, err)
+		}
+	}
+
+	glyph, _ := utf8.DecodeRuneInString(d.Glyph)
+	item := components.Item{
+		Name:         d.Name,
+		Description:  d.Description,
+		Type:         itemType,
+		Glyph:        glyph,
+		Color:        color,
+		Value:        d.Value,
+		Stackable:    d.Stackable,
+		MaxStack:     d.MaxStack,
+		Weight:       d.Weight,
+		AttackBonus:  d.AttackBonus,
+		DefenseBonus: d.DefenseBonus,
+		Range:        d.Range,
+		AmmoType:     d.AmmoType,
+		Effects:      d.Effects,
+
+		Slot:          slot,
+		TwoHanded:     d.TwoHanded,
+		AccuracyBonus: d.AccuracyBonus,
+		DodgeBonus:    d.DodgeBonus,
+		StatBonus:     d.StatBonus,
+	}
+	if d.Type == "accessory" && item.EquipSlot() == "" {
+		return components.Item{}, fmt.Errorf("accessory %q needs a slot", d.Name)
+	}
+	return item, nil
+}
+
+// parseHexColor parses a "#RRGGBB" data-file color
+func parseHexColor(s string) (gruid.Color, error) {
+	color, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 24)
+	return gruid.Color(color), err
+}
+
+// validateItemEffect checks that an effect has the fields its kind needs
+func validateItemEffect(effect components.ItemEffect) error {
+	switch effect.Kind {
+	case components.EffectHeal, components.EffectRestoreMana:
+		if effect.Amount <= 0 {
+			return fmt.Errorf("%s effect needs a positive amount", effect.Kind)
+		}
+	case components.EffectDamageArea:
+		if effect.Amount <= 0 || effect.Radius <= 0 {
+			return fmt.Errorf("%s effect needs a positive amount and radius", effect.Kind)
+		}
+	case components.EffectStatus:
+		if effect.Status == nil || effect.Status.Name == "" || effect.Status.Duration <= 0 {
+			return fmt.Errorf("%s effect needs a named status with a positive duration", effect.Kind)
+		}
+	case components.EffectTeleport, components.EffectRevealMap:
+	default:
+		return fmt.Errorf("unknown effect kind %q", effect.Kind)
+	}
+	return nil
+}
+
+// ItemCatalog holds every item definition loaded from data
+type ItemCatalog struct {
+	items map[string]components.Item // By name
+	defs  []ItemDefinition           // In file order
+}
+
+// NewItemCatalog builds a catalog, rejecting invalid or duplicate items
+func NewItemCatalog(defs []ItemDefinition) (*ItemCatalog, error) {
+	catalog := &ItemCatalog{items: make(map[string]components.Item, len(defs))}
+	var errs []error
+	for _, def := range defs {
+		item, err := def.toItem()
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, exists := catalog.items[def.Name]; exists {
+			errs = append(errs, fmt.Errorf("duplicate item %q", def.Name))
+			continue
+		}
+		catalog.items[def.Name] = item
+		catalog.defs = append(catalog.defs, def)
+	}
+	return catalog, errors.Join(errs...)
+}
+
+// LoadItems loads the item catalog embedded from assets/data. Invalid
+// entries are logged and skipped so one bad item doesn't remove all the
+// others.
+func LoadItems() *ItemCatalog {
+	defs, err := loadData[[]ItemDefinition](ItemsFile)
+	if err != nil {
+		slog.Error("Failed to load items, no items available", "error", err)
+		return &ItemCatalog{items: map[string]components.Item{}}
+	}
+
+	catalog, err := NewItemCatalog(defs)
+	if err != nil {
+		slog.Warn("Skipped invalid item definitions", "error", err)
+	}
+	return catalog
+}
+
+// Item returns the item with the given name
+func (c *ItemCatalog) Item(name string) (components.Item, bool) {
+	item, ok := c.items[name]
+	return item, ok
+}
+
+// RandomSpawn picks an item to place on a dungeon floor, weighted by
+// SpawnWeight. It returns false if no item can spawn.
+func (c *ItemCatalog) RandomSpawn(g *Game) (components.Item, bool) {
+	total := 0
+	for _, def := range c.defs {
+		total += def.SpawnWeight
+	}
+	if total <= 0 {
+		return components.Item{}, false
+	}
+
+	roll := g.rand.Intn(total)
+	for _, def := range c.defs {
+		if roll < def.SpawnWeight {
+			return c.items[def.Name], true
+		}
+		roll -= def.SpawnWeight
+	}
+	return components.Item{}, false
+}
+
+// StartingItems returns the player's starting kit in file order
+func (c *ItemCatalog) StartingItems() []ItemDefinition {
+	return slices.DeleteFunc(slices.Clone(c.defs), func(def ItemDefinition) bool {
+		return def.StartQuantity <= 0
+	})
+}
+
+// applyItemEffects runs an item's effects for the entity using it
+func (g *Game) applyItemEffects(userID ecs.EntityID, item components.Item) {
+	for _, effect := range item.Effects {
+		g.applyItemEffect(userID, item, effect)
+	}
+}
+
+// applyItemEffect runs a single item effect for the entity using the item
+func (g *Game) applyItemEffect(userID ecs.EntityID, item components.Item, effect components.ItemEffect) {
+	isPlayer := userID == g.PlayerID
+
+	switch effect.Kind {
+	case components.EffectHeal:
+		health, ok := g.ecs.GetHealth(userID)
+		if !ok {
+			return
+		}
+		healed := min(effect.Amount, health.MaxHP-health.CurrentHP)
+		health.CurrentHP += healed
+		g.ecs.AddComponent(userID, components.CHealth, health)
+		if isPlayer {
+			g.log.AddMessagef(ui.ColorStatusGood, "You feel better! (+%d HP)", healed)
+		}
+
+	case components.EffectRestoreMana:
+		mana, ok := g.ecs.GetMana(userID)
+		if !ok {
+			return
+		}
+		restored := min(effect.Amount, mana.MaxMP-mana.CurrentMP)
+		mana.CurrentMP += restored
+		g.ecs.AddComponent(userID, components.CMana, mana)
+		if isPlayer {
+			g.log.AddMessagef(ui.ColorStatusGood, "Your mind clears. (+%d MP)", restored)
+		}
+
+	case components.EffectStatus:
+		g.addStatusEffect(userID, *effect.Status)
+		if isPlayer {
+			color := ui.ColorStatusGood
+			if effect.Status.Type == "debuff" {
+				color = ui.ColorStatusBad
+			}
+			g.log.AddMessagef(color, "You are affected by %s.", effect.Status.Name)
+		}
+
+	case components.EffectTeleport:
+		to, ok := g.randomFreeCell()
+		if !ok {
+			if isPlayer {
+				g.log.AddMessagef(ui.ColorStatusNeutral, "You feel a brief tug, but nothing happens.")
+			}
+			return
+		}
+		g.ecs.AddComponent(userID, components.CPosition, to)
+		if isPlayer {
+			g.log.AddMessagef(ui.ColorStatusNeutral, "You are yanked across the level!")
+		}
+
+	case components.EffectRevealMap:
+		for y := range g.dungeon.Height {
+			for x := range g.dungeon.Width {
+				g.dungeon.SetExplored(gruid.Point{X: x, Y: y})
+			}
+		}
+		if isPlayer {
+			g.log.AddMessagef(ui.ColorStatusGood, "The layout of the level becomes clear to you.")
+		}
+
+	case components.EffectDamageArea:
+		center := g.ecs.GetPositionSafe(userID)
+		g.log.AddMessagef(ui.ColorStatusNeutral, "%s unleashes %s!", g.ecs.GetNameSafe(userID), item.Name)
+		for _, id := range g.ecs.GetEntitiesWithComponents(components.CHealth, components.CPosition) {
+			if id == userID || g.isAllied(userID, id) {
+				continue
+			}
+			if paths.DistanceChebyshev(center, g.ecs.GetPositionSafe(id)) > effect.Radius {
+				continue
+			}
+			g.applyAttack(userID, id, AttackResult{Hit: true, Damage: effect.Amount}, "blasts")
+		}
+	}
+}
+
+// randomFreeCell returns a random walkable cell with no blocking entity
+func (g *Game) randomFreeCell() (gruid.Point, bool) {
+	for range teleportAttempts {
+		p := gruid.Point{X: g.rand.Intn(g.dungeon.Width), Y: g.rand.Intn(g.dungeon.Height)}
+		if g.dungeon.isWalkable(p) && len(g.ecs.GetEntitiesAtWithComponents(p, components.CBlocksMovement)) == 0 {
+			return p, true
+		}
+	}
+	return gruid.Point{}, false
+}
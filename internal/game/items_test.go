@@ -0,0 +1,190 @@
+package game
+
+import (
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func TestItemsFile_IsValid(t *testing.T) {
+	defs, err := loadData[[]ItemDefinition](ItemsFile)
+	if err != nil {
+		t.Fatalf("Failed to load %s: %v", ItemsFile, err)
+	}
+	if _, err := NewItemCatalog(defs); err != nil {
+		t.Errorf("Invalid item definitions: %v", err)
+	}
+}
+
+func TestLoadItems_OutsideRepo(t *testing.T) {
+	t.Chdir(t.TempDir()) // Installed games don't run from a checkout
+
+	if _, ok := LoadItems().Item("Health Potion"); !ok {
+		t.Error("Expected the embedded items loaded")
+	}
+}
+
+func TestItemDefinition_ToItem(t *testing.T) {
+	valid := ItemDefinition{Name: "Potion", Type: "consumable", Glyph: "!", Color: "#FF0000"}
+
+	tests := []struct {
+		name    string
+		modify  func(d *ItemDefinition)
+		wantErr bool
+	}{
+		{"valid", func(d *ItemDefinition) {}, false},
+		{"missing name", func(d *ItemDefinition) { d.Name = "" }, true},
+		{"unknown type", func(d *ItemDefinition) { d.Type = "food" }, true},
+		{"long glyph", func(d *ItemDefinition) { d.Glyph = "!!" }, true},
+		{"bad color", func(d *ItemDefinition) { d.Color = "red" }, true},
+		{"stack without size", func(d *ItemDefinition) { d.Stackable = true }, true},
+		{"unknown effect", func(d *ItemDefinition) { d.Effects = []components.ItemEffect{{Kind: "polymorph"}} }, true},
+		{"heal without amount", func(d *ItemDefinition) { d.Effects = []components.ItemEffect{{Kind: components.EffectHeal}} }, true},
+		{"status without status", func(d *ItemDefinition) { d.Effects = []components.ItemEffect{{Kind: components.EffectStatus}} }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := valid
+			tt.modify(&def)
+			if _, err := def.toItem(); (err != nil) != tt.wantErr {
+				t.Errorf("toItem() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// createItemUserTestGame sets up a wounded player at (5,5) carrying item
+func createItemUserTestGame(item components.Item) *Game {
+	g := createTestGame()
+	g.PlayerID = g.ecs.AddEntity()
+
+	health := components.NewHealth(20)
+	health.CurrentHP = 5
+	g.ecs.AddComponents(g.PlayerID,
+		gruid.Point{X: 5, Y: 5},
+		components.Name{Name: "Player"},
+		components.BlocksMovement{},
+		health,
+		components.NewMana(10),
+		components.NewStatusEffects(),
+		components.NewInventory(10),
+	)
+	g.giveItem(g.PlayerID, item, 2)
+	return g
+}
+
+func useTestItem(t *testing.T, g *Game, item components.Item) {
+	t.Helper()
+	action := UseItemAction{EntityID: g.PlayerID, ItemName: item.Name}
+	if _, err := action.Execute(g); err != nil {
+		t.Fatalf("Unexpected error using %s: %v", item.Name, err)
+	}
+	inventory := g.ecs.GetInventorySafe(g.PlayerID)
+	if got := inventory.GetItemCount(item.Name); got != 1 {
+		t.Errorf("Expected 1 %s left, got %d", item.Name, got)
+	}
+}
+
+func consumable(effects ...components.ItemEffect) components.Item {
+	return components.Item{Name: "Test Potion", Type: components.ItemTypeConsumable, Stackable: true, MaxStack: 10, Effects: effects}
+}
+
+func TestUseItem_HealAndManaAreCapped(t *testing.T) {
+	item := consumable(
+		components.ItemEffect{Kind: components.EffectHeal, Amount: 100},
+		components.ItemEffect{Kind: components.EffectRestoreMana, Amount: 3},
+	)
+	g := createItemUserTestGame(item)
+	mana := g.ecs.GetManaSafe(g.PlayerID)
+	mana.CurrentMP = 2
+	g.ecs.AddComponent(g.PlayerID, components.CMana, mana)
+
+	useTestItem(t, g, item)
+
+	if hp := g.ecs.GetHealthSafe(g.PlayerID); hp.CurrentHP != hp.MaxHP {
+		t.Errorf("Expected full health, got %d/%d", hp.CurrentHP, hp.MaxHP)
+	}
+	if mp := g.ecs.GetManaSafe(g.PlayerID).CurrentMP; mp != 5 {
+		t.Errorf("Expected 5 MP, got %d", mp)
+	}
+}
+
+func TestUseItem_AppliesStatus(t *testing.T) {
+	poison := components.NewPoisonEffect(1, 5)
+	item := consumable(components.ItemEffect{Kind: components.EffectStatus, Status: &poison})
+	g := createItemUserTestGame(item)
+
+	useTestItem(t, g, item)
+
+	if effects := g.ecs.GetStatusEffectsSafe(g.PlayerID); !effects.HasEffect("Poison") {
+		t.Error("Expected the player to be poisoned")
+	}
+}
+
+func TestUseItem_DamageArea(t *testing.T) {
+	item := consumable(components.ItemEffect{Kind: components.EffectDamageArea, Amount: 7, Radius: 2})
+	g := createItemUserTestGame(item)
+	near := addTestMonster(g, gruid.Point{X: 7, Y: 6})
+	far := addTestMonster(g, gruid.Point{X: 8, Y: 8})
+
+	useTestItem(t, g, item)
+
+	if hp := g.ecs.GetHealthSafe(near).CurrentHP; hp != 93 {
+		t.Errorf("Expected the nearby monster at 93 HP, got %d", hp)
+	}
+	if hp := g.ecs.GetHealthSafe(far).CurrentHP; hp != 100 {
+		t.Errorf("Expected the distant monster untouched, got %d", hp)
+	}
+	if hp := g.ecs.GetHealthSafe(g.PlayerID).CurrentHP; hp != 5 {
+		t.Errorf("Expected the user untouched, got %d", hp)
+	}
+}
+
+func TestUseItem_TeleportAndRevealMap(t *testing.T) {
+	item := consumable(
+		components.ItemEffect{Kind: components.EffectTeleport},
+		components.ItemEffect{Kind: components.EffectRevealMap},
+	)
+	g := createItemUserTestGame(item)
+
+	useTestItem(t, g, item)
+
+	pos := g.GetPlayerPosition()
+	if !g.dungeon.isWalkable(pos) {
+		t.Errorf("Teleported into a wall at %v", pos)
+	}
+	if ids := g.spatialGrid.GetEntitiesAt(pos); len(ids) != 1 || ids[0] != g.PlayerID {
+		t.Errorf("Expected the spatial grid to track the player at %v, got %v", pos, ids)
+	}
+	if !g.dungeon.IsExplored(gruid.Point{X: 9, Y: 9}) {
+		t.Error("Expected the whole map to be explored")
+	}
+}
+
+func TestGiveStartingItems(t *testing.T) {
+	g := createTestGame()
+	g.PlayerID = g.ecs.AddEntity()
+	g.ecs.AddComponents(g.PlayerID, components.NewInventory(20), components.NewEquipment())
+
+	catalog, err := NewItemCatalog([]ItemDefinition{
+		{Name: "Sword", Type: "weapon", Glyph: "/", Color: "#FFFFFF", StartQuantity: 1, StartEquipped: true},
+		{Name: "Potion", Type: "consumable", Glyph: "!", Color: "#FF0000", Stackable: true, MaxStack: 10, StartQuantity: 3},
+		{Name: "Gem", Type: "misc", Glyph: "*", Color: "#00FF00", SpawnWeight: 1},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected catalog error: %v", err)
+	}
+
+	g.giveStartingItems(g.PlayerID, catalog)
+
+	equipment := g.ecs.GetEquipmentSafe(g.PlayerID)
+	if equipment.Weapon == nil || equipment.Weapon.Name != "Sword" {
+		t.Errorf("Expected the sword to be equipped, got %v", equipment.Weapon)
+	}
+	inventory := g.ecs.GetInventorySafe(g.PlayerID)
+	if inventory.GetItemCount("Sword") != 0 || inventory.GetItemCount("Potion") != 3 || inventory.GetItemCount("Gem") != 0 {
+		t.Errorf("Unexpected starting inventory: %v", inventory.Items)
+	}
+}
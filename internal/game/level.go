@@ -0,0 +1,120 @@
+package game
+
+import (
+	"log/slog"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/config"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+	turn "github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/turn_queue"
+)
+
+// Level holds the persistent state of a dungeon floor the player is not
+// currently on. Entities keep their IDs while stored here so they can be
+// moved back into the active ECS unchanged.
+type Level struct {
+	Depth  int
+	Map    *Map
+	World  *ecs.ECS         // Entities left behind on this floor
+	Turns  []turn.TurnEntry // Pending turns of those entities and the floor's timers
+	LeftAt uint64           // Turn queue time when the player left
+}
+
+// changeLevel moves the player to the given depth. A floor is generated on
+// the first visit and restored from its stored state afterwards. The player
+// arrives on the stairs leading back to the floor they came from.
+func (g *Game) changeLevel(depth int) {
+	descending := depth > g.Depth
+
+	g.stashCurrentLevel()
+	g.Depth = depth
+
+	var arrival gruid.Point
+	if lvl, ok := g.levels[depth]; ok {
+		g.restoreLevel(lvl)
+
+		stairs := StairsDownCell
+		if descending {
+			stairs = StairsUpCell
+		}
+		if p, found := g.dungeon.FindCell(stairs); found {
+			arrival = p
+		} else {
+			arrival = g.GetPlayerPosition()
+		}
+	} else {
+		g.dungeon = NewMap(config.DungeonWidth, config.DungeonHeight)
+		arrival = g.dungeon.generateMap(g, g.monsters, g.items)
+	}
+
+	g.pathfindingMgr = NewPathfindingManager(g)
+	g.ecs.AddComponent(g.PlayerID, components.CPosition, arrival)
+
+	if fov := g.ecs.GetFOVSafe(g.PlayerID); fov != nil {
+		fov.ClearVisible()
+	}
+	g.FOVSystem()
+
+	slog.Info("Changed level", "depth", depth, "arrival", arrival)
+}
+
+// stashCurrentLevel moves every entity except the player and what it
+// carries out of the active ECS and turn queue into a Level stored under
+// the current depth. Pending world timers stay with the floor too.
+func (g *Game) stashCurrentLevel() {
+	lvl := &Level{
+		Depth:  g.Depth,
+		Map:    g.dungeon,
+		World:  ecs.NewECS(),
+		LeftAt: g.turnQueue.CurrentTime,
+	}
+
+	// Split the turns first, as transferring actors out of the ECS also
+	// takes them out of the turn queue
+	var remaining []turn.TurnEntry
+	for _, entry := range g.turnQueue.Snapshot() {
+		if entry.EntityID == g.PlayerID {
+			remaining = append(remaining, entry)
+		} else {
+			lvl.Turns = append(lvl.Turns, entry)
+		}
+	}
+	g.turnQueue.RestoreFromSnapshot(remaining)
+
+	// Children, like carried items, go along with their parent
+	for _, id := range g.ecs.GetAllEntities() {
+		if id == g.PlayerID || g.ecs.HasComponent(id, components.CParent) {
+			continue
+		}
+		if err := g.ecs.TransferEntity(id, lvl.World); err != nil {
+			slog.Error("Failed to stash entity", "entityId", id, "error", err)
+		}
+	}
+
+	g.levels[g.Depth] = lvl
+}
+
+// restoreLevel moves a stored level back into the active ECS and turn queue.
+// Pending turns are shifted by the time spent away so the floor resumes
+// exactly where it was left.
+func (g *Game) restoreLevel(lvl *Level) {
+	g.dungeon = lvl.Map
+
+	for _, id := range lvl.World.GetAllEntities() {
+		if lvl.World.HasComponent(id, components.CParent) {
+			continue
+		}
+		if err := lvl.World.TransferEntity(id, g.ecs); err != nil {
+			slog.Error("Failed to restore entity", "entityId", id, "error", err)
+		}
+	}
+
+	for _, entry := range lvl.Turns {
+		offset := max(entry.Time, lvl.LeftAt) - lvl.LeftAt
+		entry.Time = g.turnQueue.CurrentTime + offset
+		g.turnQueue.Push(entry)
+	}
+
+	delete(g.levels, lvl.Depth)
+}
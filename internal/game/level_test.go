@@ -0,0 +1,56 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestChangeLevel_PersistsFloors(t *testing.T) {
+	g := NewGame()
+	g.InitLevel()
+
+	firstMap := g.dungeon
+	firstEntities := len(g.ecs.GetAllEntities())
+	staying := 1 + len(g.ecs.Children(g.PlayerID)) // The player and what it carries
+
+	if _, ok := g.dungeon.FindCell(StairsUpCell); ok {
+		t.Error("Depth 1 should not have stairs up")
+	}
+
+	g.changeLevel(2)
+
+	if g.Depth != 2 {
+		t.Fatalf("Expected depth 2, got %d", g.Depth)
+	}
+	if g.dungeon == firstMap {
+		t.Fatal("Descending should generate a new map")
+	}
+	if g.dungeon.Grid.At(g.GetPlayerPosition()) != StairsUpCell {
+		t.Error("Player should arrive on stairs up after descending")
+	}
+	if _, ok := g.levels[1]; !ok {
+		t.Fatal("Depth 1 should be stored after leaving it")
+	}
+	if got := len(g.levels[1].World.GetAllEntities()); got != firstEntities-staying {
+		t.Errorf("Expected %d stored entities on depth 1, got %d", firstEntities-staying, got)
+	}
+	for _, entry := range g.turnQueue.Snapshot() {
+		if !g.ecs.EntityExists(entry.EntityID) {
+			t.Errorf("Turn queue holds entity %d from another floor", entry.EntityID)
+		}
+	}
+
+	g.changeLevel(1)
+
+	if g.dungeon != firstMap {
+		t.Error("Ascending should restore the original map")
+	}
+	if got := len(g.ecs.GetAllEntities()); got != firstEntities {
+		t.Errorf("Expected %d entities after returning, got %d", firstEntities, got)
+	}
+	if _, ok := g.levels[2]; !ok {
+		t.Error("Depth 2 should be stored after leaving it")
+	}
+	if _, ok := g.levels[1]; ok {
+		t.Error("The active floor should not also be stored")
+	}
+}
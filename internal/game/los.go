@@ -3,6 +3,8 @@ package game
 import (
 	"codeberg.org/anaseto/gruid"
 	"codeberg.org/anaseto/gruid/paths"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
 )
 
 // Define the passable function once (reusing Map's IsOpaque)
@@ -10,17 +12,22 @@ func (g *Game) passable(p gruid.Point) bool {
 	return !g.dungeon.IsOpaque(p)
 }
 
-// FOVSystem updates the visibility for all entities with an FOV component.
+// FOVSystem updates the visibility of entities whose FOV is stale, i.e. who
+// moved or gained a FOV since it last ran.
 func (g *Game) FOVSystem() {
-	entities := g.ecs.GetEntitiesWithPositionAndFOV()
+	query := ecs.NewQuery2[gruid.Point, *components.FOV](g.ecs, components.CPosition, components.CFOV)
+	query.Each(func(id ecs.EntityID, pos *gruid.Point, fovPtr **components.FOV) {
+		if !g.staleFOV[id] {
+			return
+		}
+		delete(g.staleFOV, id)
 
-	for _, entity := range entities {
-		id, pos, fov := entity.ID, entity.Position, entity.FOV
+		fov := *fovPtr
 		fov.ClearVisible()
 
 		fovCalculator := fov.GetFOVCalculator()
-		for _, p := range fovCalculator.SSCVisionMap(pos, fov.Range, g.passable, false) {
-			if paths.DistanceManhattan(p, pos) > fov.Range {
+		for _, p := range fovCalculator.SSCVisionMap(*pos, fov.Range, g.passable, false) {
+			if paths.DistanceManhattan(p, *pos) > fov.Range {
 				continue
 			}
 
@@ -30,5 +37,5 @@ func (g *Game) FOVSystem() {
 				g.dungeon.SetExplored(p)
 			}
 		}
-	}
+	})
 }
@@ -2,7 +2,6 @@ package game
 
 import (
 	"log/slog"
-	"math/rand"
 	"slices"
 
 	"codeberg.org/anaseto/gruid"
@@ -15,14 +14,22 @@ const (
 	maxRooms           = 10
 	roomMinSize        = 6
 	roomMaxSize        = 10
-	maxMonstersPerRoom = 2 // Max monsters per room (excluding first)
+	maxMonstersPerRoom = 2  // Max monsters per room (excluding first)
+	chestChance        = 10 // Percent chance of a chest in each region
+	maxChestStacks     = 3  // Most item stacks in a chest
 )
 
 // TileType represents the type of a map tile.
 
+// Cell values are saved as is, so new ones go last.
 const (
 	WallCell rl.Cell = iota
 	FloorCell
+	StairsDownCell
+	StairsUpCell
+	DoorClosedCell
+	DoorOpenCell
+	DoorLockedCell
 )
 
 // Map represents the game map's logical state and visibility.
@@ -45,51 +52,32 @@ func NewMap(width, height int) *Map {
 	return m
 }
 
-// generateMap creates a new map layout with rooms and tunnels, and spawns monsters.
-// It now takes the game struct to access ECS and TurnQueue.
-func (m *Map) generateMap(g *Game, width, height int, items map[string]components.Item) gruid.Point {
+// generateMap creates a new map layout with the generator configured for the
+// current depth, then places stairs, monsters and items. It returns the
+// player start position.
+func (m *Map) generateMap(g *Game, monsters *MonsterCatalog, items *ItemCatalog) gruid.Point {
 	m.Grid.Fill(WallCell)
 
-	var rooms []Rect
-	var playerStart gruid.Point = gruid.Point{X: 0, Y: 0}
-
-	for range maxRooms {
-		w := rand.Intn(roomMaxSize-roomMinSize+1) + roomMinSize
-		h := rand.Intn(roomMaxSize-roomMinSize+1) + roomMinSize
-		x := rand.Intn(width - w - 1)  // -1 to ensure room fits
-		y := rand.Intn(height - h - 1) // -1 to ensure room fits
-
-		newRoom := NewRect(x, y, w, h)
-
-		// Check for intersections with existing rooms
-		intersects := slices.ContainsFunc(rooms, newRoom.Intersects)
-
-		if !intersects {
-			createRoom(m.Grid, newRoom)
-			newCenter := newRoom.Center()
-
-			if len(rooms) == 0 {
-				// This is the first room, place player here
-				playerStart = newCenter
-			} else {
-				// Connect to the previous room's center
-				prevCenter := rooms[len(rooms)-1].Center()
-
-				// Randomly decide tunnel order (H then V or V then H)
-				if rand.Intn(2) == 0 {
-					createHTunnel(m.Grid, prevCenter.X, newCenter.X, prevCenter.Y)
-					createVTunnel(m.Grid, prevCenter.Y, newCenter.Y, newCenter.X)
-				} else {
-					createVTunnel(m.Grid, prevCenter.Y, newCenter.Y, prevCenter.X)
-					createHTunnel(m.Grid, prevCenter.X, newCenter.X, newCenter.Y)
-				}
-				// Spawn monsters in this room (if not the first room)
-				m.placeMonsters(g, newRoom)
-				// Spawn items in this room
-				m.placeItems(g, newRoom, items)
-			}
-			rooms = append(rooms, newRoom)
+	layout := mapGeneratorForDepth(g.Depth).Generate(m, g.rand)
+	playerStart := layout.Start
+
+	// Stairs: down as far from the start as possible, up where the player
+	// arrives (below depth 1)
+	if stairs := farthestFloor(m, playerStart); stairs != playerStart {
+		m.Grid.Set(stairs, StairsDownCell)
+	}
+	if g.Depth > 1 {
+		m.Grid.Set(playerStart, StairsUpCell)
+	}
+
+	for _, region := range layout.Regions {
+		// Keep the player's starting region free of monsters and items
+		if slices.Contains(region, playerStart) {
+			continue
 		}
+		m.placeMonsters(g, region, monsters)
+		m.placeItems(g, region, items)
+		m.placeChest(g, region, items)
 	}
 
 	return playerStart
@@ -105,7 +93,29 @@ func (m *Map) isWalkable(p gruid.Point) bool {
 	if !m.InBounds(p) {
 		return false
 	}
-	return m.Grid.At(p) == FloorCell
+	switch m.Grid.At(p) {
+	case FloorCell, StairsDownCell, StairsUpCell, DoorOpenCell:
+		return true
+	}
+	return false
+}
+
+// isPassable checks if actors can get through a tile, opening closed doors
+// on the way. Locked doors are not passable.
+func (m *Map) isPassable(p gruid.Point) bool {
+	return m.isWalkable(p) || (m.InBounds(p) && m.Grid.At(p) == DoorClosedCell)
+}
+
+// isDoor checks if the tile at the given point is a door, in any state.
+func (m *Map) isDoor(p gruid.Point) bool {
+	if !m.InBounds(p) {
+		return false
+	}
+	switch m.Grid.At(p) {
+	case DoorClosedCell, DoorOpenCell, DoorLockedCell:
+		return true
+	}
+	return false
 }
 
 // IsWall checks if the tile at the given point is a wall.
@@ -125,7 +135,11 @@ func (m *Map) IsOpaque(p gruid.Point) bool {
 		return true
 	}
 
-	return m.Grid.At(p) == WallCell
+	switch m.Grid.At(p) {
+	case WallCell, DoorClosedCell, DoorLockedCell:
+		return true
+	}
+	return false
 }
 
 // SetExplored marks a point as explored in the global map bitset.
@@ -171,26 +185,59 @@ func (m *Map) Rune(c rl.Cell) (r rune) {
 		r = '#'
 	case FloorCell:
 		r = '.'
+	case StairsDownCell:
+		r = '>'
+	case StairsUpCell:
+		r = '<'
+	case DoorClosedCell, DoorLockedCell:
+		r = '+'
+	case DoorOpenCell:
+		r = '\''
 	}
 	return r
 }
 
-// placeMonsters spawns monsters in a given room.
-func (m *Map) placeMonsters(g *Game, room Rect) {
-	// Determine number of monsters for this room (e.g., 0 to maxMonstersPerRoom)
-	numMonsters := rand.Intn(maxMonstersPerRoom + 1) // +1 because Intn is exclusive upper bound
-	slog.Debug("Placing monsters in room", "numMonsters", numMonsters, "room", room)
+// FindCell returns the first point holding the given cell type, scanning
+// row by row. Used to locate stairs when arriving on a level.
+func (m *Map) FindCell(c rl.Cell) (gruid.Point, bool) {
+	it := m.Grid.Iterator()
+	for it.Next() {
+		if it.Cell() == c {
+			return it.P(), true
+		}
+	}
+	return gruid.Point{}, false
+}
+
+// placeMonsters spawns monsters from the current depth's spawn table on
+// random points of a spawn region. Deeper tables may allow extra monsters,
+// and the total is scaled by the configured monster spawn rate.
+func (m *Map) placeMonsters(g *Game, region []gruid.Point, monsters *MonsterCatalog) {
+	table, ok := monsters.SpawnTable(g.Depth)
+	if !ok {
+		slog.Debug("No spawn table for depth", "depth", g.Depth)
+		return
+	}
+
+	cfg := gameplayConfig()
+	maxMonsters := cfg.MaxMonstersPerRoom
+	if maxMonsters <= 0 {
+		maxMonsters = maxMonstersPerRoom
+	}
+	maxMonsters += table.ExtraMonsters
+
+	// Determine number of monsters for this region (0 to maxMonsters, scaled)
+	numMonsters := scaleSpawnCount(g.rand.Intn(maxMonsters+1), cfg.MonsterSpawnRate, g.rand) // +1 because Intn is exclusive upper bound
+	slog.Debug("Placing monsters in region", "numMonsters", numMonsters, "regionSize", len(region))
 
 	for i := 0; i < numMonsters; i++ {
-		// Find a random walkable tile within the room bounds
-		// Add +1 to x1, y1 and -1 to x2, y2 to avoid spawning on walls
-		x := rand.Intn(room.X2-room.X1-1) + room.X1 + 1
-		y := rand.Intn(room.Y2-room.Y1-1) + room.Y1 + 1
-		pos := gruid.Point{X: x, Y: y}
+		pos := region[g.rand.Intn(len(region))]
 
 		// Check if the tile is walkable and not already occupied
 		if m.isWalkable(pos) && len(g.ecs.EntitiesAt(pos)) == 0 {
-			g.SpawnMonster(pos)
+			if tmpl, ok := monsters.RandomMonster(table, g.rand); ok {
+				g.SpawnMonster(tmpl, pos)
+			}
 		} else {
 			// If tile is occupied or not walkable, we just skip spawning this monster for simplicity
 			slog.Debug("Failed to spawn monster", "position", pos, "reason", "not walkable or occupied")
@@ -198,28 +245,24 @@ func (m *Map) placeMonsters(g *Game, room Rect) {
 	}
 }
 
-// placeItems spawns items in a given room.
-func (m *Map) placeItems(g *Game, room Rect, items map[string]components.Item) {
-	// 30% chance to spawn an item in each room
-	if rand.Intn(100) < 30 {
-		// Find a random walkable tile within the room bounds
-		x := rand.Intn(room.X2-room.X1-1) + room.X1 + 1
-		y := rand.Intn(room.Y2-room.Y1-1) + room.Y1 + 1
-		pos := gruid.Point{X: x, Y: y}
+// placeItems spawns items on a random point of a spawn region.
+func (m *Map) placeItems(g *Game, region []gruid.Point, items *ItemCatalog) {
+	// 30% chance to spawn an item in each region
+	if g.rand.Intn(100) < 30 {
+		pos := region[g.rand.Intn(len(region))]
 
 		// Check if the tile is walkable and not already occupied
 		if m.isWalkable(pos) && len(g.ecs.EntitiesAt(pos)) == 0 {
-			// Get available items
-
-			// Randomly select an item to spawn
-			itemNames := []string{"Health Potion", "Iron Sword", "Leather Armor", "Gold Coin"}
-			selectedName := itemNames[rand.Intn(len(itemNames))]
-			selectedItem := items[selectedName]
+			// Randomly select an item to spawn, weighted by its spawn odds
+			selectedItem, ok := items.RandomSpawn(g)
+			if !ok {
+				return
+			}
 
 			// Determine quantity
 			quantity := 1
 			if selectedItem.Stackable {
-				quantity = rand.Intn(3) + 1 // 1-3 for stackable items
+				quantity = g.rand.Intn(3) + 1 // 1-3 for stackable items
 			}
 
 			g.SpawnItem(selectedItem, quantity, pos)
@@ -228,4 +271,31 @@ func (m *Map) placeItems(g *Game, room Rect, items map[string]components.Item) {
 	}
 }
 
+// placeChest spawns a chest of random items on a random point of a spawn
+// region.
+func (m *Map) placeChest(g *Game, region []gruid.Point, items *ItemCatalog) {
+	if g.rand.Intn(100) >= chestChance {
+		return
+	}
+	pos := region[g.rand.Intn(len(region))]
+	// Chests block movement, so they stay out of doorways
+	if !m.isWalkable(pos) || m.isDoor(pos) || len(g.ecs.EntitiesAt(pos)) > 0 {
+		return
+	}
+
+	var contents []components.ItemStack
+	for range g.rand.Intn(maxChestStacks) + 1 {
+		item, ok := items.RandomSpawn(g)
+		if !ok {
+			return
+		}
+		quantity := 1
+		if item.Stackable {
+			quantity = g.rand.Intn(3) + 1
+		}
+		contents = append(contents, components.ItemStack{Item: item, Quantity: quantity})
+	}
+	g.SpawnChest(pos, contents)
+}
+
 // Constants like maxRooms, roomMinSize, roomMaxSize, maxMonstersPerRoom should be defined centrally (e.g., in game.go)
@@ -0,0 +1,400 @@
+package game
+
+import (
+	"log/slog"
+	"math/rand"
+	"slices"
+
+	"codeberg.org/anaseto/gruid"
+	"codeberg.org/anaseto/gruid/paths"
+	"codeberg.org/anaseto/gruid/rl"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/config"
+)
+
+// MapGenerator carves a level layout into a wall-filled map.
+type MapGenerator interface {
+	Generate(m *Map, rng *rand.Rand) Layout
+}
+
+// Layout describes a generated level. Regions are groups of floor points
+// (rooms, cave pockets or map sectors) used to place monsters and items, so
+// spawning does not depend on rectangular rooms.
+type Layout struct {
+	Start   gruid.Point
+	Regions [][]gruid.Point
+}
+
+// sectorSize is the size of the map sectors used as spawn regions by
+// generators that do not produce rooms.
+var sectorSize = gruid.Point{X: 10, Y: 8}
+
+// gameplayConfig returns the loaded gameplay settings, or the defaults when
+// the configuration has not been initialized (e.g. in tests).
+func gameplayConfig() config.GameplayConfig {
+	if config.Config != nil {
+		return config.Config.Gameplay
+	}
+	return config.DefaultConfig().Gameplay
+}
+
+// mapGeneratorForDepth returns the generator configured for a depth. Depths
+// without a matching rule fall back to the classic rooms generator.
+func mapGeneratorForDepth(depth int) MapGenerator {
+	cfg := gameplayConfig()
+	for _, rule := range cfg.MapGenerators {
+		if depth < rule.MinDepth || (rule.MaxDepth != 0 && depth > rule.MaxDepth) {
+			continue
+		}
+		if gen := newMapGenerator(rule.Generator, cfg); gen != nil {
+			return gen
+		}
+		slog.Warn("Unknown map generator, using rooms", "generator", rule.Generator, "depth", depth)
+		break
+	}
+	return newMapGenerator("rooms", cfg)
+}
+
+// newMapGenerator creates a generator by name, or returns nil if the name is
+// unknown.
+func newMapGenerator(name string, cfg config.GameplayConfig) MapGenerator {
+	switch name {
+	case "rooms":
+		gen := RoomsGenerator{MaxRooms: cfg.MaxRooms, MinSize: cfg.RoomMinSize, MaxSize: cfg.RoomMaxSize}
+		if gen.MaxRooms <= 0 || gen.MinSize <= 0 || gen.MaxSize < gen.MinSize {
+			gen = RoomsGenerator{MaxRooms: maxRooms, MinSize: roomMinSize, MaxSize: roomMaxSize}
+		}
+		return gen
+	case "bsp":
+		return BSPGenerator{MinLeafSize: 8}
+	case "cave":
+		return CaveGenerator{WallChance: 0.45}
+	case "drunkard":
+		return DrunkardGenerator{FillPercent: 0.4}
+	case "maze":
+		return MazeGenerator{}
+	}
+	return nil
+}
+
+// --- Rooms ---
+
+// RoomsGenerator places random non-overlapping rooms and links each one to
+// the previous with an L-shaped tunnel, with doors where tunnels enter rooms.
+type RoomsGenerator struct {
+	MaxRooms int
+	MinSize  int
+	MaxSize  int
+}
+
+// Generate implements MapGenerator.
+func (gen RoomsGenerator) Generate(m *Map, rng *rand.Rand) Layout {
+	var rooms []Rect
+
+	for range gen.MaxRooms {
+		w := rng.Intn(gen.MaxSize-gen.MinSize+1) + gen.MinSize
+		h := rng.Intn(gen.MaxSize-gen.MinSize+1) + gen.MinSize
+		x := rng.Intn(m.Width - w - 1)  // -1 to ensure room fits
+		y := rng.Intn(m.Height - h - 1) // -1 to ensure room fits
+
+		newRoom := NewRect(x, y, w, h)
+
+		// Check for intersections with existing rooms
+		if slices.ContainsFunc(rooms, newRoom.Intersects) {
+			continue
+		}
+
+		createRoom(m.Grid, newRoom)
+		if len(rooms) > 0 {
+			connectRooms(m.Grid, rooms[len(rooms)-1], newRoom, rng)
+		}
+		rooms = append(rooms, newRoom)
+	}
+
+	placeDoors(m, rooms, rng)
+	return roomsLayout(rooms)
+}
+
+// --- BSP ---
+
+// BSPGenerator recursively splits the map into leaves, carves one room per
+// leaf and connects sibling subtrees with tunnels, with doors where tunnels
+// enter rooms.
+type BSPGenerator struct {
+	MinLeafSize int
+}
+
+// Generate implements MapGenerator.
+func (gen BSPGenerator) Generate(m *Map, rng *rand.Rand) Layout {
+	var rooms []Rect
+	gen.split(m, NewRect(0, 0, m.Width-1, m.Height-1), rng, &rooms)
+	placeDoors(m, rooms, rng)
+	return roomsLayout(rooms)
+}
+
+// split carves rooms into leaf and returns one of them so the caller can
+// connect it to the sibling subtree.
+func (gen BSPGenerator) split(m *Map, leaf Rect, rng *rand.Rand, rooms *[]Rect) Rect {
+	w, h := leaf.X2-leaf.X1, leaf.Y2-leaf.Y1
+	canSplitX := w >= 2*gen.MinLeafSize
+	canSplitY := h >= 2*gen.MinLeafSize
+
+	if !canSplitX && !canSplitY {
+		// Leaf: carve a room of random size inside it
+		rw := rng.Intn(w-gen.MinLeafSize/2+1) + gen.MinLeafSize/2
+		rh := rng.Intn(h-gen.MinLeafSize/2+1) + gen.MinLeafSize/2
+		rx := leaf.X1 + rng.Intn(w-rw+1)
+		ry := leaf.Y1 + rng.Intn(h-rh+1)
+		room := NewRect(rx, ry, rw, rh)
+		createRoom(m.Grid, room)
+		*rooms = append(*rooms, room)
+		return room
+	}
+
+	var a, b Rect
+	if canSplitX && (!canSplitY || w > h) {
+		cut := leaf.X1 + gen.MinLeafSize + rng.Intn(w-2*gen.MinLeafSize+1)
+		a = Rect{X1: leaf.X1, Y1: leaf.Y1, X2: cut, Y2: leaf.Y2}
+		b = Rect{X1: cut, Y1: leaf.Y1, X2: leaf.X2, Y2: leaf.Y2}
+	} else {
+		cut := leaf.Y1 + gen.MinLeafSize + rng.Intn(h-2*gen.MinLeafSize+1)
+		a = Rect{X1: leaf.X1, Y1: leaf.Y1, X2: leaf.X2, Y2: cut}
+		b = Rect{X1: leaf.X1, Y1: cut, X2: leaf.X2, Y2: leaf.Y2}
+	}
+
+	roomA := gen.split(m, a, rng, rooms)
+	roomB := gen.split(m, b, rng, rooms)
+	connectRooms(m.Grid, roomA, roomB, rng)
+
+	return roomA
+}
+
+// --- Cellular automata cave ---
+
+// CaveGenerator builds organic caves with a cellular automata and keeps only
+// the largest connected area.
+type CaveGenerator struct {
+	WallChance float64
+}
+
+// Generate implements MapGenerator.
+func (gen CaveGenerator) Generate(m *Map, rng *rand.Rand) Layout {
+	mg := rl.MapGen{Rand: rng, Grid: interior(m)}
+	rules := []rl.CellularAutomataRule{
+		{WCutoff1: 5, WCutoff2: 2, Reps: 4, WallsOutOfRange: true},
+		{WCutoff1: 5, WCutoff2: 25, Reps: 3, WallsOutOfRange: true},
+	}
+	mg.CellularAutomataCave(WallCell, FloorCell, gen.WallChance, rules)
+
+	return floorLayout(m, rng)
+}
+
+// --- Drunkard's walk ---
+
+// DrunkardGenerator digs tunnels with a random walk until a fraction of the
+// map is open.
+type DrunkardGenerator struct {
+	FillPercent float64
+}
+
+// drunkWalker steps to a random cardinal neighbor.
+type drunkWalker struct {
+	rng *rand.Rand
+}
+
+// Neighbor implements rl.RandomWalker.
+func (w drunkWalker) Neighbor(p gruid.Point) gruid.Point {
+	switch w.rng.Intn(4) {
+	case 0:
+		return p.Shift(1, 0)
+	case 1:
+		return p.Shift(-1, 0)
+	case 2:
+		return p.Shift(0, 1)
+	default:
+		return p.Shift(0, -1)
+	}
+}
+
+// Generate implements MapGenerator.
+func (gen DrunkardGenerator) Generate(m *Map, rng *rand.Rand) Layout {
+	mg := rl.MapGen{Rand: rng, Grid: interior(m)}
+	mg.RandomWalkCave(drunkWalker{rng: rng}, FloorCell, gen.FillPercent, 1)
+
+	return floorLayout(m, rng)
+}
+
+// --- Maze ---
+
+// MazeGenerator carves a perfect maze with a randomized depth-first search.
+type MazeGenerator struct{}
+
+// Generate implements MapGenerator.
+func (gen MazeGenerator) Generate(m *Map, rng *rand.Rand) Layout {
+	// Maze cells sit on odd coordinates; the walls between them are carved
+	// when the search moves from one cell to the next.
+	start := gruid.Point{X: 1, Y: 1}
+	m.Grid.Set(start, FloorCell)
+	stack := []gruid.Point{start}
+	dirs := []gruid.Point{{X: 2, Y: 0}, {X: -2, Y: 0}, {X: 0, Y: 2}, {X: 0, Y: -2}}
+
+	for len(stack) > 0 {
+		cur := stack[len(stack)-1]
+
+		var options []gruid.Point
+		for _, d := range dirs {
+			next := cur.Add(d)
+			if next.X > 0 && next.Y > 0 && next.X < m.Width-1 && next.Y < m.Height-1 && m.Grid.At(next) == WallCell {
+				options = append(options, next)
+			}
+		}
+
+		if len(options) == 0 {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		next := options[rng.Intn(len(options))]
+		m.Grid.Set(gruid.Point{X: (cur.X + next.X) / 2, Y: (cur.Y + next.Y) / 2}, FloorCell)
+		m.Grid.Set(next, FloorCell)
+		stack = append(stack, next)
+	}
+
+	return Layout{Start: start, Regions: sectorRegions(m)}
+}
+
+// --- Helpers ---
+
+// interior returns the grid slice inside the outer wall border.
+func interior(m *Map) rl.Grid {
+	return m.Grid.Slice(gruid.NewRange(1, 1, m.Width-1, m.Height-1))
+}
+
+// connectRooms links two rooms with an L-shaped tunnel, randomly choosing
+// whether to go horizontally or vertically first.
+func connectRooms(grid rl.Grid, a, b Rect, rng *rand.Rand) {
+	prev, next := a.Center(), b.Center()
+	if rng.Intn(2) == 0 {
+		createHTunnel(grid, prev.X, next.X, prev.Y)
+		createVTunnel(grid, prev.Y, next.Y, next.X)
+	} else {
+		createVTunnel(grid, prev.Y, next.Y, prev.X)
+		createHTunnel(grid, prev.X, next.X, next.Y)
+	}
+}
+
+// roomsLayout builds a layout with one spawn region per room. The player
+// starts in the first room.
+func roomsLayout(rooms []Rect) Layout {
+	var layout Layout
+	if len(rooms) > 0 {
+		layout.Start = rooms[0].Center()
+	}
+	for _, room := range rooms {
+		layout.Regions = append(layout.Regions, room.Points())
+	}
+	return layout
+}
+
+// floorLayout keeps the largest connected floor area of an organic map, picks
+// a random start in it and splits it into sector regions.
+func floorLayout(m *Map, rng *rand.Rand) Layout {
+	floor := keepLargestFloorArea(m)
+	if len(floor) == 0 {
+		// Degenerate map: open a single cell so the level stays playable
+		p := gruid.Point{X: m.Width / 2, Y: m.Height / 2}
+		m.Grid.Set(p, FloorCell)
+		floor = []gruid.Point{p}
+	}
+	return Layout{Start: floor[rng.Intn(len(floor))], Regions: sectorRegions(m)}
+}
+
+// floorPather yields the walkable cardinal neighbors of a point. Doors
+// connect the floor on both sides, whether open, closed or locked.
+type floorPather struct {
+	m  *Map
+	nb paths.Neighbors
+}
+
+// Neighbors implements paths.Pather.
+func (fp *floorPather) Neighbors(p gruid.Point) []gruid.Point {
+	if !fp.connects(p) {
+		return nil
+	}
+	return fp.nb.Cardinal(p, fp.connects)
+}
+
+func (fp *floorPather) connects(p gruid.Point) bool {
+	return fp.m.isWalkable(p) || fp.m.isDoor(p)
+}
+
+// keepLargestFloorArea fills every floor area but the largest connected one
+// with walls, and returns the points of the remaining area.
+func keepLargestFloorArea(m *Map) []gruid.Point {
+	pr := paths.NewPathRange(m.Grid.Bounds())
+	pr.CCMapAll(&floorPather{m: m})
+
+	sizes := make(map[int]int)
+	it := m.Grid.Iterator()
+	for it.Next() {
+		if m.isWalkable(it.P()) {
+			sizes[pr.CCMapAt(it.P())]++
+		}
+	}
+
+	best, bestSize := -1, 0
+	for id, size := range sizes {
+		if size > bestSize || (size == bestSize && id < best) {
+			best, bestSize = id, size
+		}
+	}
+
+	var floor []gruid.Point
+	it.Reset()
+	for it.Next() {
+		if !m.isWalkable(it.P()) {
+			continue
+		}
+		if pr.CCMapAt(it.P()) == best {
+			floor = append(floor, it.P())
+		} else {
+			it.SetCell(WallCell)
+		}
+	}
+	return floor
+}
+
+// sectorRegions splits the map's floor into fixed-size sectors, skipping
+// sectors with too little floor to hold anything interesting.
+func sectorRegions(m *Map) [][]gruid.Point {
+	var regions [][]gruid.Point
+	for sy := 0; sy < m.Height; sy += sectorSize.Y {
+		for sx := 0; sx < m.Width; sx += sectorSize.X {
+			var region []gruid.Point
+			for y := sy; y < min(sy+sectorSize.Y, m.Height); y++ {
+				for x := sx; x < min(sx+sectorSize.X, m.Width); x++ {
+					p := gruid.Point{X: x, Y: y}
+					if m.isWalkable(p) {
+						region = append(region, p)
+					}
+				}
+			}
+			if len(region) >= 8 {
+				regions = append(regions, region)
+			}
+		}
+	}
+	return regions
+}
+
+// farthestFloor returns the floor point with the longest walking distance
+// from start, used to place the stairs down.
+func farthestFloor(m *Map, start gruid.Point) gruid.Point {
+	pr := paths.NewPathRange(m.Grid.Bounds())
+	nodes := pr.BreadthFirstMap(&floorPather{m: m}, []gruid.Point{start}, m.Width*m.Height)
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if !m.isDoor(nodes[i].P) {
+			return nodes[i].P
+		}
+	}
+	return start
+}
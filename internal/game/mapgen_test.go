@@ -0,0 +1,71 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"codeberg.org/anaseto/gruid/paths"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/config"
+)
+
+func TestMapGenerators(t *testing.T) {
+	cfg := config.DefaultConfig().Gameplay
+
+	for _, name := range config.MapGeneratorNames {
+		t.Run(name, func(t *testing.T) {
+			gen := newMapGenerator(name, cfg)
+			if gen == nil {
+				t.Fatalf("Generator %q should exist", name)
+			}
+
+			for seed := int64(1); seed <= 5; seed++ {
+				m := NewMap(config.DungeonWidth, config.DungeonHeight)
+				m.Grid.Fill(WallCell)
+				layout := gen.Generate(m, rand.New(rand.NewSource(seed)))
+
+				if !m.isWalkable(layout.Start) {
+					t.Fatalf("seed %d: start %v should be walkable", seed, layout.Start)
+				}
+				if len(layout.Regions) == 0 {
+					t.Errorf("seed %d: layout should have spawn regions", seed)
+				}
+
+				// Every floor tile and door must be reachable from the start
+				pr := paths.NewPathRange(m.Grid.Bounds())
+				reachable := len(pr.BreadthFirstMap(&floorPather{m: m}, []gruid.Point{layout.Start}, m.Width*m.Height))
+				walkable := 0
+				it := m.Grid.Iterator()
+				for it.Next() {
+					if m.isWalkable(it.P()) || m.isDoor(it.P()) {
+						walkable++
+					}
+				}
+				if reachable != walkable {
+					t.Errorf("seed %d: %d of %d floor tiles reachable from start", seed, reachable, walkable)
+				}
+			}
+		})
+	}
+}
+
+func TestMapGeneratorForDepth(t *testing.T) {
+	testCases := []struct {
+		depth    int
+		expected MapGenerator
+	}{
+		{1, RoomsGenerator{}},
+		{3, BSPGenerator{}},
+		{5, CaveGenerator{}},
+		{7, DrunkardGenerator{}},
+		{20, MazeGenerator{}},
+	}
+
+	for _, tc := range testCases {
+		gen := mapGeneratorForDepth(tc.depth)
+		if got, want := fmt.Sprintf("%T", gen), fmt.Sprintf("%T", tc.expected); got != want {
+			t.Errorf("Depth %d: expected %s, got %s", tc.depth, want, got)
+		}
+	}
+}
@@ -21,6 +21,8 @@ const (
 	modeCharacterSheet
 	modeInventory
 	modeFullMessageLog
+	modeTargeting
+	modeSpellSelect
 )
 
 // Model represents the game model that implements gruid.Model
@@ -39,6 +41,11 @@ type Model struct {
 	inventoryScreen   *ui.InventoryScreen
 	fullMessageScreen *ui.FullMessageScreen
 
+	// Ranged targeting state, valid while in modeTargeting
+	targeting targetingState
+	// Spells offered to the player, valid while in modeSpellSelect
+	spellChoices []Spell
+
 	// Debug information
 	lastUpdateTime time.Time
 	updateCount    uint64
@@ -57,9 +64,10 @@ type Model struct {
 	aiDebugInfo  *AIDebugInfo
 }
 
-// NewModel creates a new game model
-func NewModel(grid gruid.Grid) *Model {
+// NewModel creates a new game model whose run is driven by the given seed
+func NewModel(grid gruid.Grid, seed int64) *Model {
 	game := NewGame()
+	game.SetSeed(seed)
 
 	model := &Model{
 		grid:                 grid,
@@ -93,14 +101,13 @@ func (md *Model) init() gruid.Effect {
 	slog.Debug("Level initialized")
 	slog.Debug("About to process turn queue for the first time")
 
-	md.game.FOVSystem()
-	md.processTurnQueue()
+	md.game.runRound()
 
 	slog.Debug("Initial turn queue processing completed")
 	slog.Debug("========= Game Initialization Completed =========")
 
 	// No signal handling subscription needed - handled at main level
-	return gruid.Sub(utils.HandleSignals)
+	return gruid.Batch(gruid.Sub(utils.HandleSignals), md.resumeEffect())
 }
 
 // EndTurn finalizes player's turn and runs other events until next player
@@ -112,8 +119,7 @@ func (md *Model) EndTurn() gruid.Effect {
 	g := md.game
 	g.waitingForInput = false
 
-	g.monstersTurn()
-	md.processTurnQueue()
+	g.runRound()
 
 	// Process only game events (consequences of actions)
 	md.ProcessGameEvents()
@@ -126,8 +132,8 @@ func (md *Model) EndTurn() gruid.Effect {
 	md.UpdatePathfindingDebug()
 	md.UpdateAIDebug()
 
-	// Return nil to indicate the screen should be redrawn
-	return nil
+	// Redraw, and come back for the rest of the round if it yielded
+	return md.resumeEffect()
 }
 
 // GetDebugInfo returns current debug information
@@ -140,6 +146,8 @@ func (md *Model) GetDebugInfo() map[string]any {
 		"waitingForInput":      md.game.waitingForInput,
 		"turnQueueSize":        md.game.turnQueue.Len(),
 		"currentTime":          md.game.turnQueue.CurrentTime,
+		"turnsPending":         md.game.turnsPending,
+		"stalledTurns":         md.game.stalledTurns,
 		"showPathfindingDebug": md.showPathfindingDebug,
 		"eventQueueSize":       len(md.eventQueue),
 		"lastInputTime":        md.lastInputTime,
@@ -330,6 +338,10 @@ func (md *Model) processEvent(msg gruid.Msg) gruid.Effect {
 			// Cycle debug levels
 			md.CycleDebugLevel()
 			return nil
+		case "F6":
+			// Print system timings
+			md.game.systems.LogTimings()
+			return nil
 		}
 	}
 
@@ -30,23 +30,29 @@ func (md *Model) Update(msg gruid.Msg) gruid.Effect {
 	// Handle quit messages (from signals or manual quit)
 	if _, ok := msg.(gruid.MsgQuit); ok {
 		slog.Info("Received MsgQuit, terminating")
-		return gruid.End()
+		return md.end()
 	}
 
 	// Handle termination signal from our custom signal handler
 	if _, ok := msg.(utils.MsgTerminate); ok {
 		slog.Info("Received termination signal, terminating gracefully")
-		return gruid.End()
+		return md.end()
 	}
 
 	// Handle quit command
 	if key, ok := msg.(gruid.MsgKeyDown); ok && key.Key == "q" {
-		return gruid.End()
+		return md.end()
 	}
 
 	return md.processGameUpdate(msg)
 }
 
+// end terminates the game, logging how long its systems took
+func (md *Model) end() gruid.Effect {
+	md.game.systems.LogTimings()
+	return gruid.End()
+}
+
 // validateGameState checks for valid game state
 func (md *Model) validateGameState() error {
 	g := md.game
@@ -92,6 +98,9 @@ func (md *Model) processGameUpdate(msg gruid.Msg) gruid.Effect {
 		"currentTime", g.turnQueue.CurrentTime)
 
 	// Process based on current state
+	if _, ok := msg.(msgResumeTurns); ok && !g.turnsPending {
+		return nil // The round already completed
+	}
 	if g.waitingForInput {
 		return md.handlePlayerInput(msg)
 	}
@@ -121,6 +130,10 @@ func (md *Model) handlePlayerInput(msg gruid.Msg) gruid.Effect {
 		effect = md.processNormalModeInput(msg)
 	case modeCharacterSheet, modeInventory, modeFullMessageLog:
 		effect = md.processScreenModeInput(msg)
+	case modeTargeting:
+		effect = md.processTargetingModeInput(msg)
+	case modeSpellSelect:
+		effect = md.processSpellSelectInput(msg)
 	default:
 		slog.Debug("Unexpected game mode", "mode", md.mode)
 		return nil
@@ -194,16 +207,34 @@ func (md *Model) handleMouse(msg gruid.MsgMouse) gruid.Effect {
 	return nil
 }
 
+// msgResumeTurns asks the model to carry on with a round whose action
+// phase yielded to let the screen redraw
+type msgResumeTurns struct{}
+
+// resumeEffect returns a command resuming the round if it yielded, nil
+// otherwise
+func (md *Model) resumeEffect() gruid.Effect {
+	if !md.game.turnsPending {
+		return nil
+	}
+	return gruid.Cmd(func() gruid.Msg { return msgResumeTurns{} })
+}
+
 // processTurnQueueWithEffect processes the turn queue and returns appropriate UI effect
 func (md *Model) processTurnQueueWithEffect() gruid.Effect {
 	slog.Debug("Processing turn queue")
 
-	// Process the turn queue
-	md.processTurnQueue()
+	// Process the turn queue, picking up where the round yielded
+	if md.game.turnsPending {
+		md.game.resumeRound()
+		md.ProcessGameEvents()
+	} else {
+		md.processTurnQueue()
+	}
 
-	// Return nil to trigger a redraw
-	// This ensures the screen updates after monster moves
-	return nil
+	// The screen is redrawn after every update, so monster moves show
+	// while the rest of the round waits for the next slice
+	return md.resumeEffect()
 }
 
 // normalModeKeyDown processes a key press in normal mode
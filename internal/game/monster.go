@@ -3,9 +3,9 @@ package game
 import (
 	"fmt"
 	"log/slog"
-	"math/rand"
 
 	"codeberg.org/anaseto/gruid"
+	"codeberg.org/anaseto/gruid/paths"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
 )
@@ -37,6 +37,62 @@ func (g *Game) monstersTurn() {
 	}
 }
 
+// summonsTurn plans the next action of every summoned ally that has none
+// queued.
+func (g *Game) summonsTurn() {
+	query := ecs.NewQuery2[components.Summoned, components.TurnActor](g.ecs, components.CSummoned, components.CTurnActor)
+	query.Each(func(id ecs.EntityID, _ *components.Summoned, actor *components.TurnActor) {
+		if actor.IsAlive() && actor.PeekNextAction() == nil {
+			actor.AddAction(g.summonAction(id))
+		}
+	})
+}
+
+// summonAction picks a summon's action: attack an adjacent monster, close
+// in on the nearest visible one, or stay near the player.
+func (g *Game) summonAction(id ecs.EntityID) GameAction {
+	pos := g.ecs.GetPositionSafe(id)
+
+	var target ecs.EntityID
+	nearest := 0
+	fov := g.ecs.GetFOVSafe(id)
+	for _, other := range g.ecs.GetEntitiesWithComponents(components.CAITag, components.CHealth, components.CPosition) {
+		otherPos := g.ecs.GetPositionSafe(other)
+		if fov == nil || !fov.IsVisible(otherPos, g.dungeon.Width) {
+			continue
+		}
+		if d := paths.DistanceChebyshev(pos, otherPos); target == 0 || d < nearest {
+			target, nearest = other, d
+		}
+	}
+
+	switch {
+	case target != 0 && nearest == 1:
+		return AttackAction{AttackerID: id, TargetID: target}
+	case target != 0:
+		return MoveAction{Direction: getDirectionTowards(pos, g.ecs.GetPositionSafe(target)), EntityID: id}
+	case paths.DistanceChebyshev(pos, g.GetPlayerPosition()) > 2:
+		return MoveAction{Direction: getDirectionTowards(pos, g.GetPlayerPosition()), EntityID: id}
+	default:
+		return WaitAction{EntityID: id}
+	}
+}
+
+// planTurn plans the next actions of a monster or summon outside of the
+// once-a-round AI phase
+func (g *Game) planTurn(id ecs.EntityID) {
+	actor, ok := g.ecs.GetTurnActor(id)
+	if !ok || !actor.IsAlive() {
+		return
+	}
+	switch {
+	case g.ecs.HasComponent(id, components.CAITag) && g.ecs.HasPositionSafe(id):
+		g.generateActionSequence(id, &actor)
+	case g.ecs.HasComponent(id, components.CSummoned):
+		actor.AddAction(g.summonAction(id))
+	}
+}
+
 // generateActionSequence creates a strategic sequence of actions for an entity
 func (g *Game) generateActionSequence(entityID ecs.EntityID, actor *components.TurnActor) {
 	// Check monster's FOV using safe accessor
@@ -128,9 +184,9 @@ func (g *Game) generateBasicActionSequence(entityID ecs.EntityID, actor *compone
 		}
 
 		// Queue 2-3 random actions
-		for i := 0; i < rand.Intn(3)+1; i++ {
-			if rand.Intn(2) == 0 {
-				direction := directions[rand.Intn(len(directions))]
+		for i := 0; i < g.rand.Intn(3)+1; i++ {
+			if g.rand.Intn(2) == 0 {
+				direction := directions[g.rand.Intn(len(directions))]
 				actor.AddAction(MoveAction{Direction: direction, EntityID: entityID})
 			} else {
 				actor.AddAction(WaitAction{EntityID: entityID})
@@ -155,7 +211,7 @@ func moveMonster(g *Game, id ecs.EntityID) (GameAction, error) {
 		{X: 0, Y: 1},  // South
 	}
 	// This is a simple way to randomize the order of directions
-	rand.Shuffle(len(directions), func(i, j int) {
+	g.rand.Shuffle(len(directions), func(i, j int) {
 		directions[i], directions[j] = directions[j], directions[i]
 	})
 	var validMove *gruid.Point
@@ -222,7 +278,7 @@ func (g *Game) generateChaseSequence(entityID ecs.EntityID, actor *components.Tu
 // generateFleeSequence creates a sequence of actions for fleeing from the player
 func (g *Game) generateFleeSequence(entityID ecs.EntityID, actor *components.TurnActor, pos, playerPos gruid.Point) {
 	// Generate 3-5 flee actions for sustained escape
-	sequenceLength := rand.Intn(3) + 3
+	sequenceLength := g.rand.Intn(3) + 3
 
 	for i := 0; i < sequenceLength; i++ {
 		var action GameAction
@@ -264,7 +320,7 @@ func (g *Game) generateFleeSequence(entityID ecs.EntityID, actor *components.Tur
 // generateSearchSequence creates a sequence of actions for searching for the player
 func (g *Game) generateSearchSequence(entityID ecs.EntityID, actor *components.TurnActor, aiComp *components.AIComponent, pos gruid.Point) {
 	// Generate 2-3 search actions
-	sequenceLength := rand.Intn(2) + 2
+	sequenceLength := g.rand.Intn(2) + 2
 
 	for i := 0; i < sequenceLength; i++ {
 		var action GameAction
@@ -274,7 +330,7 @@ func (g *Game) generateSearchSequence(entityID ecs.EntityID, actor *components.T
 			directions := []gruid.Point{
 				{X: -1, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: 0, Y: 1},
 			}
-			direction := directions[rand.Intn(len(directions))]
+			direction := directions[g.rand.Intn(len(directions))]
 			action = MoveAction{Direction: direction, EntityID: entityID}
 		} else {
 			// Move towards last known player position
@@ -307,7 +363,7 @@ func (g *Game) generatePatrolSequence(entityID ecs.EntityID, actor *components.T
 	homeDistance := manhattanDistance(pos, aiComp.HomePosition)
 
 	// Generate 2-4 patrol actions
-	sequenceLength := rand.Intn(3) + 2
+	sequenceLength := g.rand.Intn(3) + 2
 
 	for i := 0; i < sequenceLength; i++ {
 		var action GameAction
@@ -332,7 +388,7 @@ func (g *Game) generatePatrolSequence(entityID ecs.EntityID, actor *components.T
 			directions := []gruid.Point{
 				{X: -1, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: 0, Y: 1},
 			}
-			direction := directions[rand.Intn(len(directions))]
+			direction := directions[g.rand.Intn(len(directions))]
 			action = MoveAction{Direction: direction, EntityID: entityID}
 		}
 
@@ -376,29 +432,29 @@ func (g *Game) generateIdleSequence(entityID ecs.EntityID, actor *components.Tur
 	switch aiComp.Behavior {
 	case components.AIBehaviorWander:
 		// Generate random movement sequence
-		sequenceLength := rand.Intn(3) + 1
+		sequenceLength := g.rand.Intn(3) + 1
 		directions := []gruid.Point{
 			{X: -1, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: 0, Y: 1},
 		}
 
 		for i := 0; i < sequenceLength; i++ {
-			if rand.Intn(3) == 0 {
+			if g.rand.Intn(3) == 0 {
 				// Occasionally wait
 				actor.AddAction(WaitAction{EntityID: entityID})
 			} else {
-				direction := directions[rand.Intn(len(directions))]
+				direction := directions[g.rand.Intn(len(directions))]
 				actor.AddAction(MoveAction{Direction: direction, EntityID: entityID})
 			}
 		}
 	case components.AIBehaviorGuard:
 		// Stay in place, occasionally look around
 		actor.AddAction(WaitAction{EntityID: entityID})
-		if rand.Intn(4) == 0 {
+		if g.rand.Intn(4) == 0 {
 			// Occasionally "look" in a direction (cosmetic move that might fail)
 			directions := []gruid.Point{
 				{X: -1, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: 0, Y: 1},
 			}
-			direction := directions[rand.Intn(len(directions))]
+			direction := directions[g.rand.Intn(len(directions))]
 			actor.AddAction(MoveAction{Direction: direction, EntityID: entityID})
 		}
 	default:
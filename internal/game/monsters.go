@@ -0,0 +1,264 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"unicode/utf8"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// MonstersFile is the data file, under assets/data, defining every monster
+// and the spawn tables used to place them
+const MonstersFile = "monsters.json"
+
+// behaviorRandom lets a template pick one of randomBehaviors per monster
+const behaviorRandom = "random"
+
+// aiBehaviors maps the behavior names used in data files to AI behaviors
+var aiBehaviors = map[string]components.AIBehavior{
+	"passive": components.AIBehaviorPassive,
+	"wander":  components.AIBehaviorWander,
+	"guard":   components.AIBehaviorGuard,
+	"hunter":  components.AIBehaviorHunter,
+	"fleeing": components.AIBehaviorFleeing,
+	"pack":    components.AIBehaviorPack,
+}
+
+// randomBehaviors are the behaviors a "random" monster picks from
+var randomBehaviors = []components.AIBehavior{
+	components.AIBehaviorWander,
+	components.AIBehaviorGuard,
+	components.AIBehaviorHunter,
+}
+
+// MonstersData is the layout of the monsters data file
+type MonstersData struct {
+	Monsters    []MonsterTemplate `json:"monsters"`
+	SpawnTables []SpawnTable      `json:"spawn_tables"`
+}
+
+// MonsterTemplate is the data-file form of a monster
+type MonsterTemplate struct {
+	ID       string                `json:"id"`
+	Name     string                `json:"name"`
+	Glyph    string                `json:"glyph"`
+	Color    string                `json:"color"` // "#RRGGBB"
+	Speed    uint64                `json:"speed"` // Time between turns, 100 is normal
+	HP       int                   `json:"hp"`
+	Combat   CombatDefinition      `json:"combat"`
+	Behavior string                `json:"behavior"` // A key of aiBehaviors, or "random"
+	FOVRange int                   `json:"fov_range"`
+	XP       int                   `json:"xp"`
+	Loot     []components.LootDrop `json:"loot,omitempty"`
+}
+
+// CombatDefinition is the data-file form of combat stats. Omitted or zero
+// fields keep the defaults of components.NewCombat.
+type CombatDefinition struct {
+	AttackPower    int `json:"attack_power,omitempty"`
+	Defense        int `json:"defense,omitempty"`
+	Accuracy       int `json:"accuracy,omitempty"`
+	DodgeChance    int `json:"dodge_chance,omitempty"`
+	CriticalChance int `json:"critical_chance,omitempty"`
+	CriticalDamage int `json:"critical_damage,omitempty"`
+}
+
+// toCombat fills in the defaults for omitted fields
+func (d CombatDefinition) toCombat() components.Combat {
+	combat := components.NewCombat()
+	for _, f := range []struct {
+		value int
+		field *int
+	}{
+		{d.AttackPower, &combat.AttackPower},
+		{d.Defense, &combat.Defense},
+		{d.Accuracy, &combat.Accuracy},
+		{d.DodgeChance, &combat.DodgeChance},
+		{d.CriticalChance, &combat.CriticalChance},
+		{d.CriticalDamage, &combat.CriticalDamage},
+	} {
+		if f.value != 0 {
+			*f.field = f.value
+		}
+	}
+	return combat
+}
+
+// validate checks that the template describes a spawnable monster
+func (t MonsterTemplate) validate() error {
+	if t.ID == "" || t.Name == "" {
+		return fmt.Errorf("monster must have an id and a name")
+	}
+	if utf8.RuneCountInString(t.Glyph) != 1 {
+		return fmt.Errorf("monster %q needs a single-character glyph", t.ID)
+	}
+	if _, err := parseHexColor(t.Color); err != nil {
+		return fmt.Errorf("monster %q has invalid color %q", t.ID, t.Color)
+	}
+	if t.Speed == 0 || t.HP <= 0 || t.FOVRange <= 0 {
+		return fmt.Errorf("monster %q needs a positive speed, hp and fov_range", t.ID)
+	}
+	if _, ok := aiBehaviors[t.Behavior]; !ok && t.Behavior != behaviorRandom {
+		return fmt.Errorf("monster %q has unknown behavior %q", t.ID, t.Behavior)
+	}
+	for _, drop := range t.Loot {
+		if drop.Item == "" || drop.Chance <= 0 || drop.Chance > 100 {
+			return fmt.Errorf("monster %q has a loot drop without an item or a 1-100 chance", t.ID)
+		}
+	}
+	return nil
+}
+
+// SpawnTable lists the monsters that appear on a range of dungeon depths
+type SpawnTable struct {
+	MinDepth      int          `json:"min_depth"`
+	MaxDepth      int          `json:"max_depth"`      // 0 = no upper bound
+	ExtraMonsters int          `json:"extra_monsters"` // Added to the monster cap of each region
+	Entries       []SpawnEntry `json:"entries"`
+}
+
+// SpawnEntry gives a monster relative odds of appearing
+type SpawnEntry struct {
+	Monster string `json:"monster"` // Template ID
+	Weight  int    `json:"weight"`
+}
+
+// covers reports whether the table applies to the given depth
+func (t SpawnTable) covers(depth int) bool {
+	return depth >= t.MinDepth && (t.MaxDepth == 0 || depth <= t.MaxDepth)
+}
+
+// MonsterCatalog holds the monster templates and spawn tables loaded from data
+type MonsterCatalog struct {
+	templates map[string]MonsterTemplate // By ID
+	tables    []SpawnTable               // In file order
+}
+
+// NewMonsterCatalog builds a catalog, rejecting invalid templates and spawn
+// entries that reference unknown monsters
+func NewMonsterCatalog(data MonstersData) (*MonsterCatalog, error) {
+	catalog := &MonsterCatalog{templates: make(map[string]MonsterTemplate, len(data.Monsters))}
+	var errs []error
+	for _, tmpl := range data.Monsters {
+		if err := tmpl.validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, exists := catalog.templates[tmpl.ID]; exists {
+			errs = append(errs, fmt.Errorf("duplicate monster %q", tmpl.ID))
+			continue
+		}
+		catalog.templates[tmpl.ID] = tmpl
+	}
+
+	for _, table := range data.SpawnTables {
+		if table.MinDepth < 1 || (table.MaxDepth != 0 && table.MaxDepth < table.MinDepth) {
+			errs = append(errs, fmt.Errorf("spawn table has invalid depth range %d-%d", table.MinDepth, table.MaxDepth))
+			continue
+		}
+		valid := table
+		valid.Entries = nil
+		for _, entry := range table.Entries {
+			if _, ok := catalog.templates[entry.Monster]; !ok || entry.Weight <= 0 {
+				errs = append(errs, fmt.Errorf("spawn table %d-%d: invalid entry for %q", table.MinDepth, table.MaxDepth, entry.Monster))
+				continue
+			}
+			valid.Entries = append(valid.Entries, entry)
+		}
+		catalog.tables = append(catalog.tables, valid)
+	}
+	return catalog, errors.Join(errs...)
+}
+
+// LoadMonsters loads the monster catalog embedded from assets/data. Invalid
+// entries are logged and skipped so one bad monster doesn't remove all the
+// others.
+func LoadMonsters() *MonsterCatalog {
+	data, err := loadData[MonstersData](MonstersFile)
+	if err != nil {
+		slog.Error("Failed to load monsters, no monsters available", "error", err)
+		return &MonsterCatalog{templates: map[string]MonsterTemplate{}}
+	}
+
+	catalog, err := NewMonsterCatalog(data)
+	if err != nil {
+		slog.Warn("Skipped invalid monster definitions", "error", err)
+	}
+	return catalog
+}
+
+// Template returns the monster template with the given ID
+func (c *MonsterCatalog) Template(id string) (MonsterTemplate, bool) {
+	tmpl, ok := c.templates[id]
+	return tmpl, ok
+}
+
+// SpawnTable returns the first spawn table covering the given depth
+func (c *MonsterCatalog) SpawnTable(depth int) (SpawnTable, bool) {
+	for _, table := range c.tables {
+		if table.covers(depth) {
+			return table, true
+		}
+	}
+	return SpawnTable{}, false
+}
+
+// RandomMonster picks a monster from a spawn table, weighted by its entries
+func (c *MonsterCatalog) RandomMonster(table SpawnTable, rng *rand.Rand) (MonsterTemplate, bool) {
+	total := 0
+	for _, entry := range table.Entries {
+		total += entry.Weight
+	}
+	if total <= 0 {
+		return MonsterTemplate{}, false
+	}
+
+	roll := rng.Intn(total)
+	for _, entry := range table.Entries {
+		if roll < entry.Weight {
+			return c.templates[entry.Monster], true
+		}
+		roll -= entry.Weight
+	}
+	return MonsterTemplate{}, false
+}
+
+// scaleSpawnCount multiplies a monster count by the configured spawn rate.
+// The fractional part becomes the chance of one more monster, so rates
+// like 1.5 average out instead of being rounded away.
+func scaleSpawnCount(n int, rate float64, rng *rand.Rand) int {
+	if n <= 0 || rate <= 0 {
+		return 0
+	}
+	scaled := float64(n) * rate
+	count := int(scaled)
+	if rng.Float64() < scaled-float64(count) {
+		count++
+	}
+	return count
+}
+
+// dropLoot rolls the loot table of a dying entity, spawning the items won
+// at its position
+func (g *Game) dropLoot(id ecs.EntityID, pos gruid.Point) {
+	reward, ok := g.ecs.GetReward(id)
+	if !ok {
+		return
+	}
+	for _, drop := range reward.Loot {
+		if g.rand.Intn(100) >= drop.Chance {
+			continue
+		}
+		item, ok := g.items.Item(drop.Item)
+		if !ok {
+			slog.Warn("Unknown loot item", "item", drop.Item, "entity", id)
+			continue
+		}
+		g.commands.Spawn(g.itemComponents(item, max(drop.Quantity, 1), pos)...)
+	}
+}
@@ -0,0 +1,171 @@
+package game
+
+import (
+	"math/rand"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func TestMonstersFile_IsValid(t *testing.T) {
+	data, err := loadData[MonstersData](MonstersFile)
+	if err != nil {
+		t.Fatalf("Failed to load %s: %v", MonstersFile, err)
+	}
+	if _, err := NewMonsterCatalog(data); err != nil {
+		t.Errorf("Invalid monster definitions: %v", err)
+	}
+
+	// Loot must name items that exist
+	items := LoadItems()
+	for _, tmpl := range data.Monsters {
+		for _, drop := range tmpl.Loot {
+			if _, ok := items.Item(drop.Item); !ok {
+				t.Errorf("Monster %q drops unknown item %q", tmpl.ID, drop.Item)
+			}
+		}
+	}
+}
+
+func TestLoadMonsters_OutsideRepo(t *testing.T) {
+	t.Chdir(t.TempDir()) // Installed games don't run from a checkout
+
+	monsters := LoadMonsters()
+	if _, ok := monsters.Template("rat"); !ok {
+		t.Error("Expected the embedded monsters loaded")
+	}
+	if _, ok := monsters.SpawnTable(1); !ok {
+		t.Error("Expected the embedded spawn tables loaded")
+	}
+}
+
+func TestMonsterTemplate_Validate(t *testing.T) {
+	valid := MonsterTemplate{ID: "rat", Name: "Rat", Glyph: "r", Color: "#FF0000", Speed: 100, HP: 3, Behavior: "wander", FOVRange: 5}
+
+	tests := []struct {
+		name    string
+		modify  func(m *MonsterTemplate)
+		wantErr bool
+	}{
+		{"valid", func(m *MonsterTemplate) {}, false},
+		{"random behavior", func(m *MonsterTemplate) { m.Behavior = "random" }, false},
+		{"missing id", func(m *MonsterTemplate) { m.ID = "" }, true},
+		{"bad color", func(m *MonsterTemplate) { m.Color = "red" }, true},
+		{"no hp", func(m *MonsterTemplate) { m.HP = 0 }, true},
+		{"no speed", func(m *MonsterTemplate) { m.Speed = 0 }, true},
+		{"unknown behavior", func(m *MonsterTemplate) { m.Behavior = "sleepy" }, true},
+		{"loot without chance", func(m *MonsterTemplate) { m.Loot = []components.LootDrop{{Item: "Gold Coin"}} }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := valid
+			tt.modify(&tmpl)
+			if err := tmpl.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMonsterCatalog_SpawnTableByDepth(t *testing.T) {
+	rat := MonsterTemplate{ID: "rat", Name: "Rat", Glyph: "r", Color: "#FF0000", Speed: 100, HP: 3, Behavior: "wander", FOVRange: 5}
+	troll := rat
+	troll.ID, troll.Name = "troll", "Troll"
+
+	catalog, err := NewMonsterCatalog(MonstersData{
+		Monsters: []MonsterTemplate{rat, troll},
+		SpawnTables: []SpawnTable{
+			{MinDepth: 1, MaxDepth: 2, Entries: []SpawnEntry{{Monster: "rat", Weight: 1}}},
+			{MinDepth: 3, ExtraMonsters: 2, Entries: []SpawnEntry{{Monster: "troll", Weight: 1}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected catalog error: %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	for depth, want := range map[int]string{1: "rat", 2: "rat", 3: "troll", 20: "troll"} {
+		table, ok := catalog.SpawnTable(depth)
+		if !ok {
+			t.Fatalf("No spawn table for depth %d", depth)
+		}
+		if tmpl, _ := catalog.RandomMonster(table, rng); tmpl.ID != want {
+			t.Errorf("Depth %d spawned %q, want %q", depth, tmpl.ID, want)
+		}
+	}
+	if table, _ := catalog.SpawnTable(5); table.ExtraMonsters != 2 {
+		t.Errorf("Expected 2 extra monsters deep down, got %d", table.ExtraMonsters)
+	}
+}
+
+func TestMonsterCatalog_RejectsUnknownSpawnEntry(t *testing.T) {
+	_, err := NewMonsterCatalog(MonstersData{
+		SpawnTables: []SpawnTable{{MinDepth: 1, Entries: []SpawnEntry{{Monster: "dragon", Weight: 1}}}},
+	})
+	if err == nil {
+		t.Error("Expected an error for a spawn entry naming an unknown monster")
+	}
+}
+
+func TestScaleSpawnCount(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	if got := scaleSpawnCount(3, 0, rng); got != 0 {
+		t.Errorf("Rate 0 should spawn nothing, got %d", got)
+	}
+	if got := scaleSpawnCount(3, 2, rng); got != 6 {
+		t.Errorf("Rate 2 should double the count, got %d", got)
+	}
+
+	// Fractional rates average out
+	total := 0
+	for range 1000 {
+		total += scaleSpawnCount(1, 1.5, rng)
+	}
+	if total < 1400 || total > 1600 {
+		t.Errorf("Expected about 1500 monsters at rate 1.5, got %d", total)
+	}
+}
+
+func TestSpawnMonster_FromTemplate(t *testing.T) {
+	g := createTestGame()
+	tmpl := MonsterTemplate{
+		ID: "orc", Name: "Orc", Glyph: "o", Color: "#00FF00", Speed: 120, HP: 15,
+		Combat: CombatDefinition{AttackPower: 3, Defense: 1}, Behavior: "hunter", FOVRange: 7, XP: 25,
+		Loot: []components.LootDrop{{Item: "Gold Coin", Chance: 100, Quantity: 7}},
+	}
+
+	id := g.SpawnMonster(tmpl, gruid.Point{X: 5, Y: 5})
+
+	if hp := g.ecs.GetHealthSafe(id).MaxHP; hp != 15 {
+		t.Errorf("Expected 15 HP, got %d", hp)
+	}
+	combat, _ := g.ecs.GetCombat(id)
+	if combat.AttackPower != 3 || combat.Defense != 1 || combat.Accuracy != components.NewCombat().Accuracy {
+		t.Errorf("Unexpected combat stats %+v", combat)
+	}
+	if ai, _ := g.ecs.GetAIComponent(id); ai.Behavior != components.AIBehaviorHunter {
+		t.Errorf("Expected hunter behavior, got %v", ai.Behavior)
+	}
+	if r := g.ecs.GetRenderableSafe(id); r.Glyph != 'o' || r.Color != 0x00FF00 {
+		t.Errorf("Unexpected renderable %+v", r)
+	}
+
+	// Killing it awards the template's XP and drops its loot
+	g.PlayerID = g.ecs.AddEntity()
+	g.ecs.AddComponents(g.PlayerID, components.Name{Name: "Player"}, components.NewExperience())
+	g.handleEntityDeath(id, "Orc", g.PlayerID)
+	g.commands.Apply()
+
+	if xp := g.ecs.GetExperienceSafe(g.PlayerID).TotalXP; xp != 25 {
+		t.Errorf("Expected 25 XP, got %d", xp)
+	}
+	loot := g.ecs.GetEntitiesAtWithComponents(gruid.Point{X: 5, Y: 5}, components.CItemPickup)
+	if len(loot) != 1 {
+		t.Fatalf("Expected 1 loot pile, got %d", len(loot))
+	}
+	if pickup, _ := g.ecs.GetItemPickup(loot[0]); pickup.Item.Name != "Gold Coin" || pickup.Quantity != 7 {
+		t.Errorf("Unexpected loot %s x%d", pickup.Item.Name, pickup.Quantity)
+	}
+}
@@ -76,9 +76,10 @@ func (pm *PathfindingManager) Estimation(from, to gruid.Point) int {
 	return paths.DistanceManhattan(from, to)
 }
 
-// isWalkable checks if a position is walkable
+// isWalkable checks if a position is walkable. Closed doors count, since
+// actors open them by bumping into them.
 func (pm *PathfindingManager) isWalkable(p gruid.Point) bool {
-	return pm.game.dungeon.InBounds(p) && pm.game.dungeon.isWalkable(p)
+	return pm.game.dungeon.InBounds(p) && pm.game.dungeon.isPassable(p)
 }
 
 // FindPath computes a path from start to goal using the best available algorithm
@@ -4,6 +4,7 @@ import (
 	"log/slog"
 
 	"codeberg.org/anaseto/gruid"
+	"codeberg.org/anaseto/gruid/rl"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui"
@@ -37,6 +38,15 @@ const (
 	ActionUseSelectedItem
 	ActionEquipSelectedItem
 	ActionDropSelectedItem
+	ActionDescend
+	ActionAscend
+	ActionFire
+	ActionTargetNext
+	ActionTargetPrev
+	ActionTargetConfirm
+	ActionCast
+	ActionOpenDoor
+	ActionCloseDoor
 )
 
 type actionError int
@@ -74,6 +84,24 @@ func (md *Model) normalModeAction(playerAction playerAction) (again bool, eff gr
 		actor.AddAction(action)
 		return false, eff, nil
 
+	case ActionDescend:
+		return md.handleStairsAction(StairsDownCell)
+
+	case ActionAscend:
+		return md.handleStairsAction(StairsUpCell)
+
+	case ActionFire:
+		return md.handleFireAction()
+
+	case ActionCast:
+		return md.handleCastAction()
+
+	case ActionOpenDoor:
+		return md.handleDoorAction(true)
+
+	case ActionCloseDoor:
+		return md.handleDoorAction(false)
+
 	case ActionPickup:
 		return md.handlePickupAction()
 
@@ -159,6 +187,67 @@ func (md *Model) handlePickupAction() (again bool, eff gruid.Effect, err error)
 	return false, eff, nil
 }
 
+// handleStairsAction queues a stairs action if the player stands on the
+// matching stairs tile
+func (md *Model) handleStairsAction(stairs rl.Cell) (again bool, eff gruid.Effect, err error) {
+	g := md.game
+
+	if g.dungeon.Grid.At(g.GetPlayerPosition()) != stairs {
+		if stairs == StairsDownCell {
+			g.log.AddMessagef(ui.ColorStatusBad, "There are no stairs down here.")
+		} else {
+			g.log.AddMessagef(ui.ColorStatusBad, "There are no stairs up here.")
+		}
+		return true, eff, nil // Don't consume turn
+	}
+
+	var action GameAction
+	if stairs == StairsDownCell {
+		action = DescendStairsAction{EntityID: g.PlayerID}
+	} else {
+		action = AscendStairsAction{EntityID: g.PlayerID}
+	}
+
+	actor, _ := g.ecs.GetTurnActor(g.PlayerID)
+	actor.AddAction(action)
+
+	return false, eff, nil
+}
+
+// handleDoorAction queues opening or closing a door next to the player
+func (md *Model) handleDoorAction(open bool) (again bool, eff gruid.Effect, err error) {
+	g := md.game
+	pos := g.GetPlayerPosition()
+
+	for _, d := range []gruid.Point{{X: 0, Y: -1}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: -1, Y: 0}} {
+		p := pos.Add(d)
+		if !g.dungeon.InBounds(p) {
+			continue
+		}
+
+		var action GameAction
+		switch cell := g.dungeon.Grid.At(p); {
+		case open && (cell == DoorClosedCell || cell == DoorLockedCell):
+			action = OpenDoorAction{EntityID: g.PlayerID, Pos: p}
+		case !open && cell == DoorOpenCell:
+			action = CloseDoorAction{EntityID: g.PlayerID, Pos: p}
+		default:
+			continue
+		}
+
+		actor, _ := g.ecs.GetTurnActor(g.PlayerID)
+		actor.AddAction(action)
+		return false, eff, nil
+	}
+
+	if open {
+		g.log.AddMessagef(ui.ColorStatusBad, "There is no closed door next to you.")
+	} else {
+		g.log.AddMessagef(ui.ColorStatusBad, "There is no open door next to you.")
+	}
+	return true, eff, nil // Don't consume turn
+}
+
 // handleDropAction handles dropping items (simplified - drops first item)
 func (md *Model) handleDropAction() (again bool, eff gruid.Effect, err error) {
 	g := md.game
@@ -279,6 +368,11 @@ func (md *Model) handleHelpAction() (again bool, eff gruid.Effect, err error) {
 	g.log.AddMessagef(ui.ColorStatusGood, "=== HELP ===")
 	g.log.AddMessagef(ui.ColorStatusGood, "Movement: Arrow keys, WASD, or hjkl")
 	g.log.AddMessagef(ui.ColorStatusGood, "Wait: . (period) or Space")
+	g.log.AddMessagef(ui.ColorStatusGood, "Stairs: > to descend, < to ascend")
+	g.log.AddMessagef(ui.ColorStatusGood, "Doors: o to open, c to close, or walk into one")
+	g.log.AddMessagef(ui.ColorStatusGood, "Chests: walk into one to take its contents")
+	g.log.AddMessagef(ui.ColorStatusGood, "Fire: f to aim (Tab cycles, Enter fires, Esc cancels)")
+	g.log.AddMessagef(ui.ColorStatusGood, "Cast: z, then a letter to pick a spell")
 	g.log.AddMessagef(ui.ColorStatusGood, "")
 	g.log.AddMessagef(ui.ColorStatusGood, "=== Inventory ===")
 	g.log.AddMessagef(ui.ColorStatusGood, "g - Pick up item")
@@ -1,6 +1,7 @@
 package game
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 
@@ -9,11 +10,9 @@ import (
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
 )
 
-// UpdateEntityPosition updates an entity's position and maintains the spatial grid
-func (g *Game) UpdateEntityPosition(id ecs.EntityID, oldPos, newPos gruid.Point) {
-	// Update the spatial grid
-	g.spatialGrid.Move(id, oldPos, newPos)
-}
+// errBlocked is returned by EntityBump when something that can't be
+// attacked stands in the way
+var errBlocked = errors.New("way blocked")
 
 // EntityBump attempts to move the entity with the given ID by the delta.
 // It checks for map boundaries and collisions with other entities.
@@ -44,6 +43,17 @@ func (g *Game) EntityBump(entityID ecs.EntityID, delta gruid.Point) (moved bool,
 			continue // Don't interact with self
 		}
 
+		// Allies never attack each other; the player swaps places with them
+		if g.isAllied(entityID, otherID) {
+			if entityID != g.PlayerID {
+				return false, fmt.Errorf("entity %d blocked by ally %d: %w", entityID, otherID, errBlocked)
+			}
+			if err := g.ecs.MoveEntity(otherID, currentPos); err != nil {
+				return false, fmt.Errorf("failed to move entity %d: %w", otherID, err)
+			}
+			break
+		}
+
 		// Check if the target entity has health (i.e., is attackable)
 		if g.ecs.HasComponent(otherID, components.CHealth) {
 			// Target is attackable. Queue an AttackAction for the bumping entity.
@@ -70,19 +80,17 @@ func (g *Game) EntityBump(entityID ecs.EntityID, delta gruid.Point) (moved bool,
 		} else {
 			// Bumped into a non-attackable entity (e.g., another player, item, scenery)
 			slog.Debug("Entity bumped into non-attackable entity", "entityId", entityID, "otherId", otherID)
-			return false, nil // Block movement
+			return false, fmt.Errorf("entity %d blocked by %d: %w", entityID, otherID, errBlocked)
 		}
 	}
 
-	// If no collision, move the entity
+	// If no collision, move the entity. The spatial grid follows through
+	// the position hooks.
 	err = g.ecs.MoveEntity(entityID, newPos)
 	if err != nil {
 		return false, fmt.Errorf("failed to move entity %d: %w", entityID, err)
 	}
 
-	// Update the spatial grid
-	g.UpdateEntityPosition(entityID, currentPos, newPos)
-
 	// Successfully moved
 	return true, nil
 }
@@ -0,0 +1,164 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui"
+)
+
+const (
+	rangedSkillAccuracy = 5 // Accuracy gained per RangedWeapons skill point
+	rangedRangePenalty  = 3 // Accuracy lost per cell beyond the first
+)
+
+// bresenhamLine returns the cells on the line from `from` to `to`, both
+// included, using Bresenham's algorithm.
+func bresenhamLine(from, to gruid.Point) []gruid.Point {
+	dx := abs(to.X - from.X)
+	dy := -abs(to.Y - from.Y)
+	sx, sy := 1, 1
+	if from.X > to.X {
+		sx = -1
+	}
+	if from.Y > to.Y {
+		sy = -1
+	}
+
+	line := []gruid.Point{from}
+	p := from
+	err := dx + dy
+	for p != to {
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			p.X += sx
+		}
+		if e2 <= dx {
+			err += dx
+			p.Y += sy
+		}
+		line = append(line, p)
+	}
+
+	return line
+}
+
+// projectilePath traces a projectile fired from `from` towards `to`. The
+// returned path excludes the origin and stops at the first blocking entity
+// (returned as hit, 0 if none), before the first wall, or after maxRange
+// cells. Projectiles aimed at an empty cell keep flying past it.
+func (g *Game) projectilePath(from, to gruid.Point, maxRange int) (path []gruid.Point, hit ecs.EntityID) {
+	if from == to {
+		return nil, 0
+	}
+
+	// The line goes through the aimed cell, then repeats its pattern past it
+	// so shots keep flying to full range
+	line := bresenhamLine(from, to)[1:]
+	delta := to.Sub(from)
+	for end := to; len(line) < maxRange; end = end.Add(delta) {
+		line = append(line, bresenhamLine(end, end.Add(delta))[1:]...)
+	}
+
+	for _, p := range line {
+		if len(path) >= maxRange || !g.dungeon.isWalkable(p) {
+			break
+		}
+		path = append(path, p)
+		for _, id := range g.ecs.GetEntitiesAtWithComponents(p, components.CBlocksMovement) {
+			if g.ecs.HasComponent(id, components.CHealth) {
+				return path, id
+			}
+		}
+	}
+
+	return path, 0
+}
+
+// rangedWeapon returns the entity's equipped ranged weapon, if any.
+func (g *Game) rangedWeapon(id ecs.EntityID) (components.Item, bool) {
+	equipment, ok := g.ecs.GetEquipment(id)
+	if !ok || equipment.Weapon == nil || !equipment.Weapon.IsRanged() {
+		return components.Item{}, false
+	}
+	return *equipment.Weapon, true
+}
+
+// findAmmo returns the inventory item used as ammunition by weapon.
+func (g *Game) findAmmo(id ecs.EntityID, weapon components.Item) (components.Item, bool) {
+	inventory := g.ecs.GetInventorySafe(id)
+	for _, stack := range inventory.Items {
+		if stack.Item.Name == weapon.AmmoType && stack.Quantity > 0 {
+			return stack.Item, true
+		}
+	}
+	return components.Item{}, false
+}
+
+// resolveRangedAttack rolls a projectile attack from attacker against target
+// at the given distance. Damage is driven by dexterity instead of strength
+// and adds the ammunition's bonus; accuracy grows with the RangedWeapons
+// skill and drops with distance.
+func (g *Game) resolveRangedAttack(attackerID, targetID ecs.EntityID, ammo components.Item, distance int) AttackResult {
+	attacker := g.effectiveCombat(attackerID)
+	stats := g.effectiveStats(attackerID)
+	skills := g.ecs.GetSkillsSafe(attackerID)
+
+	attacker.AttackPower += ammo.AttackBonus - attributeModifier(stats.Strength) + attributeModifier(stats.Dexterity)
+	attacker.Accuracy += skills.RangedWeapons*rangedSkillAccuracy - (distance-1)*rangedRangePenalty
+
+	return g.rollAttack(attackerID, attacker, g.effectiveCombat(targetID))
+}
+
+// FireAction represents an entity firing its ranged weapon at a target cell.
+type FireAction struct {
+	ShooterID ecs.EntityID
+	Target    gruid.Point
+}
+
+// Execute fires one piece of ammunition along a line towards the target.
+func (a FireAction) Execute(g *Game) (cost uint, err error) {
+	weapon, ok := g.rangedWeapon(a.ShooterID)
+	if !ok {
+		return 0, fmt.Errorf("entity %d has no ranged weapon equipped", a.ShooterID)
+	}
+
+	ammo, ok := g.findAmmo(a.ShooterID, weapon)
+	if !ok {
+		if a.ShooterID == g.PlayerID {
+			g.log.AddMessagef(ui.ColorStatusBad, "You have no %ss left.", weapon.AmmoType)
+		}
+		return 0, fmt.Errorf("entity %d has no %s", a.ShooterID, weapon.AmmoType)
+	}
+
+	if ammoID, ok := g.carriedItem(a.ShooterID, ammo.Name, 1); ok {
+		g.consumeItem(ammoID, 1)
+	}
+
+	from := g.ecs.GetPositionSafe(a.ShooterID)
+	path, hitID := g.projectilePath(from, a.Target, weapon.Range)
+	shooterName := g.ecs.GetNameSafe(a.ShooterID)
+
+	slog.Info("Projectile fired", "shooter", shooterName, "shooterId", a.ShooterID, "target", a.Target, "pathLength", len(path), "hitId", hitID)
+
+	if hitID == 0 {
+		g.log.AddMessagef(ui.ColorStatusNeutral, "%s's %s flies off and hits nothing.", shooterName, ammo.Name)
+		return 100, nil
+	}
+
+	result := g.resolveRangedAttack(a.ShooterID, hitID, ammo, len(path))
+	g.applyAttack(a.ShooterID, hitID, result, "shoots")
+
+	return 100, nil
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,138 @@
+package game
+
+import (
+	"slices"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func TestBresenhamLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to gruid.Point
+		want     []gruid.Point
+	}{
+		{"single cell", gruid.Point{X: 2, Y: 2}, gruid.Point{X: 2, Y: 2}, []gruid.Point{{X: 2, Y: 2}}},
+		{"horizontal", gruid.Point{X: 0, Y: 0}, gruid.Point{X: 3, Y: 0}, []gruid.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}}},
+		{"diagonal", gruid.Point{X: 3, Y: 3}, gruid.Point{X: 1, Y: 1}, []gruid.Point{{X: 3, Y: 3}, {X: 2, Y: 2}, {X: 1, Y: 1}}},
+		{"shallow", gruid.Point{X: 0, Y: 0}, gruid.Point{X: 5, Y: 2}, []gruid.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 4, Y: 2}, {X: 5, Y: 2}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bresenhamLine(tt.from, tt.to); !slices.Equal(got, tt.want) {
+				t.Errorf("bresenhamLine(%v, %v) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+// createRangedTestGame sets up an archer with a bow and arrows at (2,5)
+func createRangedTestGame(arrows int) *Game {
+	g := createTestGame()
+	bow, _ := g.items.Item("Short Bow")
+	arrow, _ := g.items.Item("Arrow")
+
+	g.PlayerID = g.ecs.AddEntity()
+	g.ecs.AddComponents(g.PlayerID,
+		gruid.Point{X: 2, Y: 5},
+		components.Name{Name: "Player"},
+		components.NewEquipment(),
+		components.NewInventory(10),
+	)
+	g.equipItem(g.PlayerID, g.giveItem(g.PlayerID, bow, 1))
+	g.giveItem(g.PlayerID, arrow, arrows)
+	return g
+}
+
+func addTestMonster(g *Game, pos gruid.Point) ecs.EntityID {
+	id := g.ecs.AddEntity()
+	g.ecs.AddComponents(id,
+		pos,
+		components.AITag{},
+		components.BlocksMovement{},
+		components.Name{Name: "Orc"},
+		components.NewHealth(100),
+	)
+	return id
+}
+
+func TestProjectilePath(t *testing.T) {
+	g := createRangedTestGame(1)
+	from := gruid.Point{X: 2, Y: 5}
+
+	// Shots at empty cells keep flying until the wall
+	path, hit := g.projectilePath(from, gruid.Point{X: 4, Y: 5}, 10)
+	if hit != 0 {
+		t.Errorf("Expected no hit, got entity %d", hit)
+	}
+	if len(path) == 0 || path[len(path)-1] != (gruid.Point{X: 8, Y: 5}) {
+		t.Errorf("Expected path to stop before the east wall, got %v", path)
+	}
+
+	// Range limits the path
+	path, _ = g.projectilePath(from, gruid.Point{X: 8, Y: 5}, 3)
+	if len(path) != 3 {
+		t.Errorf("Expected path of length 3, got %v", path)
+	}
+
+	// Blocking entities stop the projectile
+	monster := addTestMonster(g, gruid.Point{X: 5, Y: 5})
+	path, hit = g.projectilePath(from, gruid.Point{X: 8, Y: 5}, 10)
+	if hit != monster {
+		t.Errorf("Expected to hit monster %d, got %d", monster, hit)
+	}
+	if path[len(path)-1] != (gruid.Point{X: 5, Y: 5}) {
+		t.Errorf("Expected path to end on the monster, got %v", path)
+	}
+}
+
+func TestProjectilePath_PassesAimedCell(t *testing.T) {
+	g := createTestGame()
+	g.dungeon = NewMap(21, 21)
+	g.dungeon.Grid.Fill(FloorCell)
+	from := gruid.Point{X: 10, Y: 10}
+
+	for dy := -8; dy <= 8; dy++ {
+		for dx := -8; dx <= 8; dx++ {
+			to := from.Add(gruid.Point{X: dx, Y: dy})
+			if to == from {
+				continue
+			}
+			path, _ := g.projectilePath(from, to, 8)
+			if !slices.Contains(path, to) {
+				t.Errorf("Expected the shot at offset (%d,%d) to pass the aimed cell, got %v", dx, dy, path)
+			}
+			if len(path) != 8 {
+				t.Errorf("Expected the shot at offset (%d,%d) to fly full range, got %v", dx, dy, path)
+			}
+		}
+	}
+}
+
+func TestFireAction_UsesAmmo(t *testing.T) {
+	g := createRangedTestGame(2)
+	addTestMonster(g, gruid.Point{X: 5, Y: 5})
+	action := FireAction{ShooterID: g.PlayerID, Target: gruid.Point{X: 5, Y: 5}}
+
+	for want := 1; want >= 0; want-- {
+		cost, err := action.Execute(g)
+		if err != nil {
+			t.Fatalf("Unexpected error firing: %v", err)
+		}
+		if cost != 100 {
+			t.Errorf("Expected cost 100, got %d", cost)
+		}
+		inventory := g.ecs.GetInventorySafe(g.PlayerID)
+		if got := inventory.GetItemCount("Arrow"); got != want {
+			t.Errorf("Expected %d arrows left, got %d", want, got)
+		}
+	}
+
+	if _, err := action.Execute(g); err == nil {
+		t.Error("Expected an error when firing without ammo")
+	}
+}
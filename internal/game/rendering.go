@@ -5,6 +5,7 @@ import (
 	"log/slog"
 
 	"codeberg.org/anaseto/gruid" // Needed for FOV type
+	"codeberg.org/anaseto/gruid/paths"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui" // For colors
@@ -93,6 +94,14 @@ func (md *Model) Draw() gruid.Grid {
 	// Render entities in the viewport
 	md.renderEntitiesInViewport(g.ecs, playerFOVComp, g.dungeon.Width)
 
+	// Show monsters sensed through walls by detection magic
+	md.drawDetectedMonsters(g, playerFOVComp)
+
+	// Highlight the line of fire while aiming
+	if md.mode == modeTargeting {
+		md.drawTargeting(g)
+	}
+
 	// Draw debug overlays if enabled
 	if md.debugLevel != DebugNone {
 		md.drawDebugOverlays(g, playerFOVComp)
@@ -224,6 +233,26 @@ func (md *Model) renderEntitiesInViewport(world *ecs.ECS, playerFOV *components.
 	}
 }
 
+// drawDetectedMonsters draws the monsters out of sight that lie within the
+// player's detection radius.
+func (md *Model) drawDetectedMonsters(g *Game, playerFOV *components.FOV) {
+	radius := g.detectionRadius(g.PlayerID)
+	if radius == 0 {
+		return
+	}
+
+	playerPos := g.GetPlayerPosition()
+	query := ecs.NewQuery2[gruid.Point, components.Renderable](g.ecs, components.CPosition, components.CRenderable).With(components.CAITag)
+	query.Each(func(id ecs.EntityID, pos *gruid.Point, _ *components.Renderable) {
+		if playerFOV.IsVisible(*pos, g.dungeon.Width) ||
+			!md.camera.IsInViewport(pos.X, pos.Y) ||
+			paths.DistanceChebyshev(playerPos, *pos) > radius {
+			return
+		}
+		md.drawEntityInViewport(g.ecs, *pos, id)
+	})
+}
+
 // drawEntityInViewport draws an entity using camera coordinates
 func (md *Model) drawEntityInViewport(ecs *ecs.ECS, worldPos gruid.Point, entityID ecs.EntityID) {
 	// Use safe accessor - no error handling needed!
@@ -531,6 +560,26 @@ func (gda *gameDataAdapter) GetDepth() int {
 	return gda.game.GetDepth()
 }
 
+func (gda *gameDataAdapter) GetSeed() int64 {
+	return gda.game.GetSeed()
+}
+
+func (gda *gameDataAdapter) EffectiveStats() components.Stats {
+	return gda.game.EffectiveStats()
+}
+
+func (gda *gameDataAdapter) EffectiveCombat() components.Combat {
+	return gda.game.EffectiveCombat()
+}
+
+func (gda *gameDataAdapter) PlayerSpeed() (current, base int) {
+	return gda.game.PlayerSpeed()
+}
+
+func (gda *gameDataAdapter) PlayerBurden() (carried, limit int) {
+	return gda.game.PlayerBurden()
+}
+
 func (gda *gameDataAdapter) Stats() ui.GameStats {
 	return &gameStatsAdapter{gda.game.Stats()}
 }
@@ -0,0 +1,47 @@
+package game
+
+import "math/rand"
+
+// countingSource wraps the run's random source and counts the values drawn
+// from it, so the random state can be saved as (seed, draws) and replayed
+// exactly when a game is loaded.
+type countingSource struct {
+	src   rand.Source64
+	draws uint64
+}
+
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{src: rand.NewSource(seed).(rand.Source64)}
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.draws = 0
+}
+
+// SetSeed reseeds the run's random source. Every random draw in the game
+// goes through g.rand, so the same seed reproduces the same run.
+func (g *Game) SetSeed(seed int64) {
+	g.Seed = seed
+	g.randSource = newCountingSource(seed)
+	g.rand = rand.New(g.randSource)
+}
+
+// restoreRandState reseeds the random source and fast-forwards it past the
+// given number of draws, resuming the sequence of a saved run.
+func (g *Game) restoreRandState(seed int64, draws uint64) {
+	g.SetSeed(seed)
+	for range draws {
+		g.randSource.Int63()
+	}
+}
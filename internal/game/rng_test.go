@@ -0,0 +1,63 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func newSeededGame(seed int64) *Game {
+	g := NewGame()
+	g.SetSeed(seed)
+	g.InitLevel()
+	return g
+}
+
+func TestSeededRuns_AreDeterministic(t *testing.T) {
+	a := newSeededGame(42)
+	b := newSeededGame(42)
+
+	if a.GetPlayerPosition() != b.GetPlayerPosition() {
+		t.Errorf("Player positions differ: %v vs %v", a.GetPlayerPosition(), b.GetPlayerPosition())
+	}
+
+	it := a.dungeon.Grid.Iterator()
+	for it.Next() {
+		if b.dungeon.Grid.At(it.P()) != it.Cell() {
+			t.Fatalf("Maps differ at %v", it.P())
+		}
+	}
+
+	posA := a.ecs.GetEntitiesWithComponent(components.CPosition)
+	posB := b.ecs.GetEntitiesWithComponent(components.CPosition)
+	if len(posA) != len(posB) {
+		t.Fatalf("Entity counts differ: %d vs %d", len(posA), len(posB))
+	}
+	for _, id := range posA {
+		if a.ecs.GetPositionSafe(id) != b.ecs.GetPositionSafe(id) {
+			t.Errorf("Entity %d placed differently: %v vs %v", id, a.ecs.GetPositionSafe(id), b.ecs.GetPositionSafe(id))
+		}
+	}
+}
+
+func TestRestoreRandState_ResumesSequence(t *testing.T) {
+	g := NewGame()
+	g.SetSeed(7)
+	for range 10 {
+		g.rand.Intn(100)
+	}
+	draws := g.randSource.draws
+
+	want := make([]int, 5)
+	for i := range want {
+		want[i] = g.rand.Intn(1000)
+	}
+
+	restored := NewGame()
+	restored.restoreRandState(7, draws)
+	for i, w := range want {
+		if got := restored.rand.Intn(1000); got != w {
+			t.Fatalf("Draw %d after restore: expected %d, got %d", i, w, got)
+		}
+	}
+}
@@ -28,6 +28,17 @@ func (r Rect) Intersects(other Rect) bool {
 		r.Y1 <= other.Y2 && r.Y2 >= other.Y1
 }
 
+// Points returns the floor points carved by createRoom for this rectangle.
+func (r Rect) Points() []gruid.Point {
+	var pts []gruid.Point
+	for y := r.Y1 + 1; y < r.Y2; y++ {
+		for x := r.X1 + 1; x < r.X2; x++ {
+			pts = append(pts, gruid.Point{X: x, Y: y})
+		}
+	}
+	return pts
+}
+
 // Constants for map generation (roomMaxSize, roomMinSize, maxRooms)
 // are now defined centrally (e.g., in game.go)
 
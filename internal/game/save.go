@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"codeberg.org/anaseto/gruid"
@@ -18,31 +20,44 @@ import (
 
 // SaveData represents the complete game state for serialization
 type SaveData struct {
-	Version   string         `json:"version"`
-	Timestamp time.Time      `json:"timestamp"`
-	PlayerID  ecs.EntityID   `json:"player_id"`
-	Depth     int            `json:"depth"`
-	Entities  []SavedEntity  `json:"entities"`
-	Map       SavedMap       `json:"map"`
-	TurnQueue SavedTurnQueue `json:"turn_queue"`
-	Messages  []SavedMessage `json:"messages"`
-	GameStats SavedGameStats `json:"game_stats"`
+	Version     string         `json:"version"`
+	Timestamp   time.Time      `json:"timestamp"`
+	PlayerID    ecs.EntityID   `json:"player_id"`
+	Depth       int            `json:"depth"`
+	Seed        int64          `json:"seed"`
+	RandDraws   uint64         `json:"rand_draws"` // Values drawn from the seeded source so far
+	Entities    []SavedEntity  `json:"entities"`
+	Generations []uint32       `json:"generations"` // Entity slot generations, so removed entities' IDs stay stale
+	Map         SavedMap       `json:"map"`
+	TurnQueue   SavedTurnQueue `json:"turn_queue"`
+	Levels      []SavedLevel   `json:"levels"` // Visited floors other than the current one
+	Messages    []SavedMessage `json:"messages"`
+	GameStats   SavedGameStats `json:"game_stats"`
 }
 
 // SavedEntity represents an entity and its components
 type SavedEntity struct {
-	ID         ecs.EntityID           `json:"id"`
-	Components map[string]interface{} `json:"components"`
+	ID         ecs.EntityID                                 `json:"id"`
+	Components map[components.ComponentType]json.RawMessage `json:"components"` // Encoded by components.MarshalComponent
 }
 
 // SavedMap represents the map state
 type SavedMap struct {
 	Width    int      `json:"width"`
 	Height   int      `json:"height"`
-	Cells    [][]int  `json:"cells"`    // Grid data
+	Cells    [][]int  `json:"cells"`    // Grid data, door states included
 	Explored []uint64 `json:"explored"` // Explored bitset
 }
 
+// SavedLevel represents a visited floor the player is not currently on.
+// TurnQueue.CurrentTime holds the time the player left the floor.
+type SavedLevel struct {
+	Depth     int            `json:"depth"`
+	Map       SavedMap       `json:"map"`
+	Entities  []SavedEntity  `json:"entities"`
+	TurnQueue SavedTurnQueue `json:"turn_queue"`
+}
+
 // SavedTurnQueue represents the turn queue state
 type SavedTurnQueue struct {
 	CurrentTime uint64                `json:"current_time"`
@@ -53,6 +68,14 @@ type SavedTurnQueue struct {
 type SavedTurnQueueEntry struct {
 	EntityID ecs.EntityID `json:"entity_id"`
 	Time     uint64       `json:"time"`
+	Timer    *SavedTimer  `json:"timer,omitempty"`
+}
+
+// SavedTimer represents a pending world timer
+type SavedTimer struct {
+	ID   turn.TimerID    `json:"id"`
+	Kind string          `json:"kind"`
+	Data json.RawMessage `json:"data,omitempty"`
 }
 
 // SavedMessage represents a log message
@@ -72,7 +95,7 @@ type SavedGameStats struct {
 }
 
 const (
-	SaveVersion = "1.0.0"
+	SaveVersion = "1.6.0"
 	SaveDir     = "assets/saves"
 	SaveFile    = "game.save"
 )
@@ -89,114 +112,43 @@ func (g *Game) SaveGame() error {
 		Timestamp: time.Now(),
 		PlayerID:  g.PlayerID,
 		Depth:     g.Depth,
+		Seed:      g.Seed,
+		RandDraws: g.randSource.draws,
 	}
 
 	// Save entities and their components
-	entities := g.ecs.GetAllEntities()
-	for _, entityID := range entities {
-		savedEntity := SavedEntity{
-			ID:         entityID,
-			Components: make(map[string]interface{}),
-		}
-
-		// Save each component type
-		if pos, ok := g.ecs.GetPosition(entityID); ok {
-			savedEntity.Components["position"] = pos
-		}
-		if renderable, ok := g.ecs.GetRenderable(entityID); ok {
-			savedEntity.Components["renderable"] = renderable
-		}
-		if health, ok := g.ecs.GetHealth(entityID); ok {
-			savedEntity.Components["health"] = health
-		}
-		if name, ok := g.ecs.GetName(entityID); ok {
-			savedEntity.Components["name"] = name
-		}
-		if g.ecs.HasComponent(entityID, components.CPlayerTag) {
-			savedEntity.Components["player_tag"] = true
-		}
-		if g.ecs.HasComponent(entityID, components.CAITag) {
-			savedEntity.Components["ai_tag"] = true
-		}
-		if g.ecs.HasComponent(entityID, components.CBlocksMovement) {
-			savedEntity.Components["blocks_movement"] = true
-		}
-		if g.ecs.HasComponent(entityID, components.CCorpseTag) {
-			savedEntity.Components["corpse_tag"] = true
-		}
-		if turnActor, ok := g.ecs.GetTurnActor(entityID); ok {
-			savedEntity.Components["turn_actor"] = turnActor
-		}
-
-		// Save new components
-		if inventory, ok := g.ecs.GetInventory(entityID); ok {
-			savedEntity.Components["inventory"] = inventory
-		}
-		if equipment, ok := g.ecs.GetEquipment(entityID); ok {
-			savedEntity.Components["equipment"] = equipment
-		}
-		if itemPickup, ok := g.ecs.GetItemPickup(entityID); ok {
-			savedEntity.Components["item_pickup"] = itemPickup
-		}
-		if aiComponent, ok := g.ecs.GetAIComponent(entityID); ok {
-			savedEntity.Components["ai_component"] = aiComponent
-		}
-		if stats, ok := g.ecs.GetStats(entityID); ok {
-			savedEntity.Components["stats"] = stats
-		}
-		if experience, ok := g.ecs.GetExperience(entityID); ok {
-			savedEntity.Components["experience"] = experience
-		}
-		if skills, ok := g.ecs.GetSkills(entityID); ok {
-			savedEntity.Components["skills"] = skills
-		}
-		if combat, ok := g.ecs.GetCombat(entityID); ok {
-			savedEntity.Components["combat"] = combat
-		}
-		if mana, ok := g.ecs.GetMana(entityID); ok {
-			savedEntity.Components["mana"] = mana
-		}
-		if stamina, ok := g.ecs.GetStamina(entityID); ok {
-			savedEntity.Components["stamina"] = stamina
-		}
-		if statusEffects, ok := g.ecs.GetStatusEffects(entityID); ok {
-			savedEntity.Components["status_effects"] = statusEffects
-		}
-
-		saveData.Entities = append(saveData.Entities, savedEntity)
+	entities, err := saveEntities(g.ecs)
+	if err != nil {
+		return fmt.Errorf("failed to save entities: %w", err)
 	}
+	saveData.Entities = entities
+	saveData.Generations = g.ecs.SlotGenerations()
 
 	// Save map state
-	saveData.Map = SavedMap{
-		Width:    g.dungeon.Width,
-		Height:   g.dungeon.Height,
-		Explored: g.dungeon.Explored,
-	}
-
-	// Convert grid to serializable format
-	saveData.Map.Cells = make([][]int, g.dungeon.Height)
-	for y := 0; y < g.dungeon.Height; y++ {
-		saveData.Map.Cells[y] = make([]int, g.dungeon.Width)
-		for x := 0; x < g.dungeon.Width; x++ {
-			point := gruid.Point{X: x, Y: y}
-			cell := g.dungeon.Grid.At(point)
-			saveData.Map.Cells[y][x] = int(cell)
-		}
-	}
+	saveData.Map = saveMap(g.dungeon)
 
 	// Save turn queue state with all entries
-	queueSnapshot := g.turnQueue.Snapshot()
-	savedEntries := make([]SavedTurnQueueEntry, len(queueSnapshot))
-	for i, entry := range queueSnapshot {
-		savedEntries[i] = SavedTurnQueueEntry{
-			EntityID: entry.EntityID,
-			Time:     entry.Time,
-		}
-	}
-
 	saveData.TurnQueue = SavedTurnQueue{
 		CurrentTime: g.turnQueue.CurrentTime,
-		Entries:     savedEntries,
+		Entries:     saveTurnEntries(g.turnQueue.Snapshot()),
+	}
+
+	// Save every other visited floor
+	for _, depth := range slices.Sorted(maps.Keys(g.levels)) {
+		lvl := g.levels[depth]
+		entities, err := saveEntities(lvl.World)
+		if err != nil {
+			return fmt.Errorf("failed to save level %d: %w", depth, err)
+		}
+		saveData.Levels = append(saveData.Levels, SavedLevel{
+			Depth:    lvl.Depth,
+			Map:      saveMap(lvl.Map),
+			Entities: entities,
+			TurnQueue: SavedTurnQueue{
+				CurrentTime: lvl.LeftAt,
+				Entries:     saveTurnEntries(lvl.Turns),
+			},
+		})
 	}
 
 	// Save messages with timestamps
@@ -264,338 +216,62 @@ func (g *Game) LoadGame() error {
 		slog.Warn("Save file version differs from current version", "version", saveData.Version, "currentVersion", SaveVersion)
 	}
 
+	// Decode every entity before touching the current game, so a save that
+	// can't be read leaves it as it was
+	entities, err := loadEntities(saveData.Entities)
+	if err != nil {
+		return fmt.Errorf("failed to load entities: %w", err)
+	}
+	levelEntities := make([][]loadedEntity, len(saveData.Levels))
+	for i, savedLevel := range saveData.Levels {
+		if levelEntities[i], err = loadEntities(savedLevel.Entities); err != nil {
+			return fmt.Errorf("failed to load level %d: %w", savedLevel.Depth, err)
+		}
+	}
+
 	// Clear current game state
-	g.ecs = ecs.NewECS()
-	g.spatialGrid.Clear()
+	g.setWorld(ecs.NewECS())
 
 	// Restore basic game state
 	g.PlayerID = saveData.PlayerID
 	g.Depth = saveData.Depth
 
-	// Restore map
-	g.dungeon = NewMap(saveData.Map.Width, saveData.Map.Height)
-	g.dungeon.Explored = saveData.Map.Explored
+	// Resume the run's random sequence where it was saved
+	g.restoreRandState(saveData.Seed, saveData.RandDraws)
 
-	// Restore grid cells
-	for y := 0; y < saveData.Map.Height; y++ {
-		for x := 0; x < saveData.Map.Width; x++ {
-			if y < len(saveData.Map.Cells) && x < len(saveData.Map.Cells[y]) {
-				point := gruid.Point{X: x, Y: y}
-				cell := rl.Cell(saveData.Map.Cells[y][x])
-				g.dungeon.Grid.Set(point, cell)
-			}
-		}
-	}
+	// Restore map
+	g.dungeon = loadMap(saveData.Map)
 
-	// Restore entities
-	for _, savedEntity := range saveData.Entities {
-		// Create entity with specific ID
-		if err := g.ecs.AddEntityWithID(savedEntity.ID); err != nil {
-			slog.Error("Failed to create entity", "id", savedEntity.ID, "error", err)
-			continue
-		}
-		entityID := savedEntity.ID
-
-		// Restore components
-		for compType, compData := range savedEntity.Components {
-			switch compType {
-			case "position":
-				if pos, ok := compData.(map[string]interface{}); ok {
-					point := gruid.Point{
-						X: int(pos["X"].(float64)),
-						Y: int(pos["Y"].(float64)),
-					}
-					g.ecs.AddComponent(entityID, components.CPosition, point)
-					g.spatialGrid.Add(entityID, point)
-				}
-			case "health":
-				if healthData, ok := compData.(map[string]interface{}); ok {
-					health := components.Health{
-						CurrentHP: int(healthData["CurrentHP"].(float64)),
-						MaxHP:     int(healthData["MaxHP"].(float64)),
-					}
-					g.ecs.AddComponent(entityID, components.CHealth, health)
-				}
-			case "player_tag":
-				g.ecs.AddComponent(entityID, components.CPlayerTag, components.PlayerTag{})
-			case "ai_tag":
-				g.ecs.AddComponent(entityID, components.CAITag, components.AITag{})
-			case "blocks_movement":
-				g.ecs.AddComponent(entityID, components.CBlocksMovement, components.BlocksMovement{})
-			case "corpse_tag":
-				g.ecs.AddComponent(entityID, components.CCorpseTag, components.CorpseTag{})
-
-			case "renderable":
-				if renderData, ok := compData.(map[string]interface{}); ok {
-					renderable := components.Renderable{
-						Glyph: rune(renderData["Glyph"].(float64)),
-						Color: gruid.Color(renderData["Color"].(float64)),
-					}
-					g.ecs.AddComponent(entityID, components.CRenderable, renderable)
-				}
-
-			case "name":
-				if nameStr, ok := compData.(string); ok {
-					g.ecs.AddComponent(entityID, components.CName, nameStr)
-				}
-
-			case "turn_actor":
-				if actorData, ok := compData.(map[string]interface{}); ok {
-					actor := components.TurnActor{
-						Speed:        uint64(actorData["Speed"].(float64)),
-						Alive:        actorData["Alive"].(bool),
-						NextTurnTime: uint64(actorData["NextTurnTime"].(float64)),
-					}
-					g.ecs.AddComponent(entityID, components.CTurnActor, actor)
-				}
-
-			// New components
-			case "inventory":
-				if invData, ok := compData.(map[string]interface{}); ok {
-					inventory := components.Inventory{
-						Capacity: int(invData["Capacity"].(float64)),
-						Items:    []components.ItemStack{},
-					}
-					if itemsData, ok := invData["Items"].([]interface{}); ok {
-						for _, itemData := range itemsData {
-							if itemMap, ok := itemData.(map[string]interface{}); ok {
-								// Reconstruct ItemStack
-								stack := components.ItemStack{
-									Quantity: int(itemMap["Quantity"].(float64)),
-								}
-								// Reconstruct Item
-								if itemInfo, ok := itemMap["Item"].(map[string]interface{}); ok {
-									stack.Item = components.Item{
-										Name:        itemInfo["Name"].(string),
-										Description: itemInfo["Description"].(string),
-										Type:        components.ItemType(itemInfo["Type"].(float64)),
-										Glyph:       rune(itemInfo["Glyph"].(float64)),
-										Color:       gruid.Color(itemInfo["Color"].(float64)),
-										Value:       int(itemInfo["Value"].(float64)),
-										Stackable:   itemInfo["Stackable"].(bool),
-										MaxStack:    int(itemInfo["MaxStack"].(float64)),
-									}
-								}
-								inventory.Items = append(inventory.Items, stack)
-							}
-						}
-					}
-					g.ecs.AddComponent(entityID, components.CInventory, inventory)
-				}
-
-			case "equipment":
-				if eqData, ok := compData.(map[string]interface{}); ok {
-					equipment := components.Equipment{}
-					// Restore weapon
-					if weaponData, ok := eqData["Weapon"]; ok && weaponData != nil {
-						if weaponMap, ok := weaponData.(map[string]interface{}); ok {
-							weapon := components.Item{
-								Name:        weaponMap["Name"].(string),
-								Description: weaponMap["Description"].(string),
-								Type:        components.ItemType(weaponMap["Type"].(float64)),
-								Glyph:       rune(weaponMap["Glyph"].(float64)),
-								Color:       gruid.Color(weaponMap["Color"].(float64)),
-								Value:       int(weaponMap["Value"].(float64)),
-								Stackable:   weaponMap["Stackable"].(bool),
-								MaxStack:    int(weaponMap["MaxStack"].(float64)),
-							}
-							equipment.Weapon = &weapon
-						}
-					}
-					// Restore armor
-					if armorData, ok := eqData["Armor"]; ok && armorData != nil {
-						if armorMap, ok := armorData.(map[string]interface{}); ok {
-							armor := components.Item{
-								Name:        armorMap["Name"].(string),
-								Description: armorMap["Description"].(string),
-								Type:        components.ItemType(armorMap["Type"].(float64)),
-								Glyph:       rune(armorMap["Glyph"].(float64)),
-								Color:       gruid.Color(armorMap["Color"].(float64)),
-								Value:       int(armorMap["Value"].(float64)),
-								Stackable:   armorMap["Stackable"].(bool),
-								MaxStack:    int(armorMap["MaxStack"].(float64)),
-							}
-							equipment.Armor = &armor
-						}
-					}
-					g.ecs.AddComponent(entityID, components.CEquipment, equipment)
-				}
-
-			case "experience":
-				if expData, ok := compData.(map[string]interface{}); ok {
-					experience := components.Experience{
-						Level:           int(expData["Level"].(float64)),
-						CurrentXP:       int(expData["CurrentXP"].(float64)),
-						XPToNextLevel:   int(expData["XPToNextLevel"].(float64)),
-						TotalXP:         int(expData["TotalXP"].(float64)),
-						SkillPoints:     int(expData["SkillPoints"].(float64)),
-						AttributePoints: int(expData["AttributePoints"].(float64)),
-					}
-					g.ecs.AddComponent(entityID, components.CExperience, experience)
-				}
-
-			case "item_pickup":
-				if pickupData, ok := compData.(map[string]interface{}); ok {
-					pickup := components.ItemPickup{
-						Quantity: int(pickupData["Quantity"].(float64)),
-					}
-					// Restore the Item
-					if itemData, ok := pickupData["Item"].(map[string]interface{}); ok {
-						pickup.Item = components.Item{
-							Name:        itemData["Name"].(string),
-							Description: itemData["Description"].(string),
-							Type:        components.ItemType(itemData["Type"].(float64)),
-							Glyph:       rune(itemData["Glyph"].(float64)),
-							Color:       gruid.Color(itemData["Color"].(float64)),
-							Value:       int(itemData["Value"].(float64)),
-							Stackable:   itemData["Stackable"].(bool),
-							MaxStack:    int(itemData["MaxStack"].(float64)),
-						}
-					}
-					g.ecs.AddComponent(entityID, components.CItemPickup, pickup)
-				}
-
-			case "ai_component":
-				if aiData, ok := compData.(map[string]interface{}); ok {
-					aiComponent := components.AIComponent{
-						Behavior:       components.AIBehavior(aiData["Behavior"].(float64)),
-						State:          components.AIState(aiData["State"].(float64)),
-						PatrolRadius:   int(aiData["PatrolRadius"].(float64)),
-						AggroRange:     int(aiData["AggroRange"].(float64)),
-						FleeThreshold:  aiData["FleeThreshold"].(float64),
-						SearchTurns:    int(aiData["SearchTurns"].(float64)),
-						MaxSearchTurns: int(aiData["MaxSearchTurns"].(float64)),
-					}
-					// Restore LastKnownPlayerPos
-					if posData, ok := aiData["LastKnownPlayerPos"].(map[string]interface{}); ok {
-						aiComponent.LastKnownPlayerPos = gruid.Point{
-							X: int(posData["X"].(float64)),
-							Y: int(posData["Y"].(float64)),
-						}
-					}
-					// Restore HomePosition
-					if homeData, ok := aiData["HomePosition"].(map[string]interface{}); ok {
-						aiComponent.HomePosition = gruid.Point{
-							X: int(homeData["X"].(float64)),
-							Y: int(homeData["Y"].(float64)),
-						}
-					}
-					g.ecs.AddComponent(entityID, components.CAIComponent, aiComponent)
-				}
-
-			case "stats":
-				if statsData, ok := compData.(map[string]interface{}); ok {
-					stats := components.Stats{
-						Strength:     int(statsData["Strength"].(float64)),
-						Dexterity:    int(statsData["Dexterity"].(float64)),
-						Constitution: int(statsData["Constitution"].(float64)),
-						Intelligence: int(statsData["Intelligence"].(float64)),
-						Wisdom:       int(statsData["Wisdom"].(float64)),
-						Charisma:     int(statsData["Charisma"].(float64)),
-					}
-					g.ecs.AddComponent(entityID, components.CStats, stats)
-				}
-
-			case "skills":
-				if skillsData, ok := compData.(map[string]interface{}); ok {
-					skills := components.Skills{
-						MeleeWeapons:  int(skillsData["MeleeWeapons"].(float64)),
-						RangedWeapons: int(skillsData["RangedWeapons"].(float64)),
-						Defense:       int(skillsData["Defense"].(float64)),
-						Stealth:       int(skillsData["Stealth"].(float64)),
-						Perception:    int(skillsData["Perception"].(float64)),
-						Medicine:      int(skillsData["Medicine"].(float64)),
-						Crafting:      int(skillsData["Crafting"].(float64)),
-						Evocation:     int(skillsData["Evocation"].(float64)),
-						Conjuration:   int(skillsData["Conjuration"].(float64)),
-						Enchantment:   int(skillsData["Enchantment"].(float64)),
-						Divination:    int(skillsData["Divination"].(float64)),
-						Lockpicking:   int(skillsData["Lockpicking"].(float64)),
-					}
-					g.ecs.AddComponent(entityID, components.CSkills, skills)
-				}
-
-			case "combat":
-				if combatData, ok := compData.(map[string]interface{}); ok {
-					combat := components.Combat{
-						AttackPower:    int(combatData["AttackPower"].(float64)),
-						Defense:        int(combatData["Defense"].(float64)),
-						Accuracy:       int(combatData["Accuracy"].(float64)),
-						DodgeChance:    int(combatData["DodgeChance"].(float64)),
-						CriticalChance: int(combatData["CriticalChance"].(float64)),
-						CriticalDamage: int(combatData["CriticalDamage"].(float64)),
-					}
-					g.ecs.AddComponent(entityID, components.CCombat, combat)
-				}
-
-			case "mana":
-				if manaData, ok := compData.(map[string]interface{}); ok {
-					mana := components.Mana{
-						CurrentMP: int(manaData["CurrentMP"].(float64)),
-						MaxMP:     int(manaData["MaxMP"].(float64)),
-						RegenRate: int(manaData["RegenRate"].(float64)),
-					}
-					g.ecs.AddComponent(entityID, components.CMana, mana)
-				}
-
-			case "stamina":
-				if staminaData, ok := compData.(map[string]interface{}); ok {
-					stamina := components.Stamina{
-						CurrentSP: int(staminaData["CurrentSP"].(float64)),
-						MaxSP:     int(staminaData["MaxSP"].(float64)),
-						RegenRate: int(staminaData["RegenRate"].(float64)),
-					}
-					g.ecs.AddComponent(entityID, components.CStamina, stamina)
-				}
-
-			case "status_effects":
-				if effectsData, ok := compData.(map[string]interface{}); ok {
-					statusEffects := components.StatusEffects{
-						Effects: []components.StatusEffect{},
-					}
-					// Restore effects array
-					if effectsArray, ok := effectsData["Effects"].([]interface{}); ok {
-						for _, effectData := range effectsArray {
-							if effectMap, ok := effectData.(map[string]interface{}); ok {
-								effect := components.StatusEffect{
-									Name:            effectMap["Name"].(string),
-									Description:     effectMap["Description"].(string),
-									Duration:        int(effectMap["Duration"].(float64)),
-									StrengthMod:     int(effectMap["StrengthMod"].(float64)),
-									DexterityMod:    int(effectMap["DexterityMod"].(float64)),
-									ConstitutionMod: int(effectMap["ConstitutionMod"].(float64)),
-									IntelligenceMod: int(effectMap["IntelligenceMod"].(float64)),
-									WisdomMod:       int(effectMap["WisdomMod"].(float64)),
-									CharismaMod:     int(effectMap["CharismaMod"].(float64)),
-									AttackMod:       int(effectMap["AttackMod"].(float64)),
-									DefenseMod:      int(effectMap["DefenseMod"].(float64)),
-									AccuracyMod:     int(effectMap["AccuracyMod"].(float64)),
-									DodgeMod:        int(effectMap["DodgeMod"].(float64)),
-								}
-								statusEffects.Effects = append(statusEffects.Effects, effect)
-							}
-						}
-					}
-					g.ecs.AddComponent(entityID, components.CStatusEffects, statusEffects)
-				}
-			}
-		}
-	}
+	// Restore entities. Slot generations go first so that entities removed
+	// before saving do not get their IDs back.
+	g.ecs.RestoreSlotGenerations(saveData.Generations)
+	restoreEntities(g.ecs, entities)
 
 	// Restore turn queue with all entries
 	g.turnQueue.CurrentTime = saveData.TurnQueue.CurrentTime
-
-	// Convert saved entries back to TurnEntry format
-	queueEntries := make([]turn.TurnEntry, len(saveData.TurnQueue.Entries))
-	for i, savedEntry := range saveData.TurnQueue.Entries {
-		queueEntries[i] = turn.TurnEntry{
-			EntityID: savedEntry.EntityID,
-			Time:     savedEntry.Time,
+	g.turnQueue.RestoreFromSnapshot(loadTurnEntries(saveData.TurnQueue.Entries))
+
+	// Restore every other visited floor. Their entities live outside the
+	// active ECS, so reserve their IDs to keep new entities from reusing them.
+	g.levels = make(map[int]*Level)
+	for i, savedLevel := range saveData.Levels {
+		lvl := &Level{
+			Depth:  savedLevel.Depth,
+			Map:    loadMap(savedLevel.Map),
+			World:  ecs.NewECS(),
+			Turns:  loadTurnEntries(savedLevel.TurnQueue.Entries),
+			LeftAt: savedLevel.TurnQueue.CurrentTime,
+		}
+		g.turnQueue.ReserveTimerIDs(lvl.Turns)
+		restoreEntities(lvl.World, levelEntities[i])
+		for _, savedEntity := range savedLevel.Entities {
+			g.ecs.ReserveEntityID(savedEntity.ID)
 		}
+		g.levels[lvl.Depth] = lvl
 	}
 
-	// Restore the queue from snapshot
-	g.turnQueue.RestoreFromSnapshot(queueEntries)
+	// Pathfinding caches the map bounds, so rebuild it for the loaded map
+	g.pathfindingMgr = NewPathfindingManager(g)
 
 	// Restore messages with timestamps
 	g.log.Messages = []log.Message{}
@@ -619,6 +295,135 @@ func (g *Game) LoadGame() error {
 	return nil
 }
 
+// saveEntities converts every entity in world into its serializable form
+func saveEntities(world *ecs.ECS) ([]SavedEntity, error) {
+	var saved []SavedEntity
+	for _, entityID := range world.GetAllEntities() {
+		savedEntity := SavedEntity{
+			ID:         entityID,
+			Components: make(map[components.ComponentType]json.RawMessage),
+		}
+		for compType, comp := range world.ComponentsOf(entityID) {
+			data, err := components.MarshalComponent(compType, comp)
+			if err != nil {
+				return nil, fmt.Errorf("entity %d: %w", entityID, err)
+			}
+			savedEntity.Components[compType] = data
+		}
+		saved = append(saved, savedEntity)
+	}
+	return saved, nil
+}
+
+// saveMap converts a map into its serializable form
+func saveMap(m *Map) SavedMap {
+	saved := SavedMap{
+		Width:    m.Width,
+		Height:   m.Height,
+		Explored: m.Explored,
+	}
+
+	// Convert grid to serializable format
+	saved.Cells = make([][]int, m.Height)
+	for y := 0; y < m.Height; y++ {
+		saved.Cells[y] = make([]int, m.Width)
+		for x := 0; x < m.Width; x++ {
+			point := gruid.Point{X: x, Y: y}
+			saved.Cells[y][x] = int(m.Grid.At(point))
+		}
+	}
+
+	return saved
+}
+
+// saveTurnEntries converts turn queue entries into their serializable form
+func saveTurnEntries(entries []turn.TurnEntry) []SavedTurnQueueEntry {
+	saved := make([]SavedTurnQueueEntry, len(entries))
+	for i, entry := range entries {
+		saved[i] = SavedTurnQueueEntry{
+			EntityID: entry.EntityID,
+			Time:     entry.Time,
+		}
+		if entry.IsTimer() {
+			saved[i].Timer = &SavedTimer{ID: entry.Timer.ID, Kind: entry.Timer.Kind, Data: entry.Timer.Data}
+		}
+	}
+	return saved
+}
+
+// loadedEntity is a saved entity with its components decoded
+type loadedEntity struct {
+	id    ecs.EntityID
+	comps map[components.ComponentType]any
+}
+
+// loadEntities decodes saved entities. It fails on the first component of
+// an unknown type or that does not decode, before anything is restored.
+func loadEntities(saved []SavedEntity) ([]loadedEntity, error) {
+	loaded := make([]loadedEntity, 0, len(saved))
+	for _, savedEntity := range saved {
+		entity := loadedEntity{id: savedEntity.ID, comps: make(map[components.ComponentType]any, len(savedEntity.Components))}
+		for compType, data := range savedEntity.Components {
+			comp, err := components.UnmarshalComponent(compType, data)
+			if err != nil {
+				return nil, fmt.Errorf("entity %d: %w", savedEntity.ID, err)
+			}
+			entity.comps[compType] = comp
+		}
+		loaded = append(loaded, entity)
+	}
+	return loaded, nil
+}
+
+// restoreEntities recreates loaded entities in world with their original
+// IDs
+func restoreEntities(world *ecs.ECS, entities []loadedEntity) {
+	for _, entity := range entities {
+		if err := world.AddEntityWithID(entity.id); err != nil {
+			slog.Error("Failed to create entity", "id", entity.id, "error", err)
+			continue
+		}
+		for _, compType := range slices.Sorted(maps.Keys(entity.comps)) {
+			world.AddComponent(entity.id, compType, entity.comps[compType])
+		}
+	}
+}
+
+// loadMap rebuilds a map from its serialized form
+func loadMap(saved SavedMap) *Map {
+	m := NewMap(saved.Width, saved.Height)
+	if saved.Explored != nil {
+		m.Explored = saved.Explored
+	}
+
+	// Restore grid cells
+	for y := 0; y < saved.Height; y++ {
+		for x := 0; x < saved.Width; x++ {
+			if y < len(saved.Cells) && x < len(saved.Cells[y]) {
+				point := gruid.Point{X: x, Y: y}
+				m.Grid.Set(point, rl.Cell(saved.Cells[y][x]))
+			}
+		}
+	}
+
+	return m
+}
+
+// loadTurnEntries converts saved turn queue entries back to TurnEntry format
+func loadTurnEntries(saved []SavedTurnQueueEntry) []turn.TurnEntry {
+	entries := make([]turn.TurnEntry, len(saved))
+	for i, savedEntry := range saved {
+		entries[i] = turn.TurnEntry{
+			EntityID: savedEntry.EntityID,
+			Time:     savedEntry.Time,
+		}
+		if timer := savedEntry.Timer; timer != nil {
+			entries[i].Timer = &turn.Timer{ID: timer.ID, Kind: timer.Kind, Data: timer.Data}
+		}
+	}
+	return entries
+}
+
 // HasSaveFile checks if a save file exists
 func HasSaveFile() bool {
 	savePath := filepath.Join(SaveDir, SaveFile)
@@ -0,0 +1,67 @@
+package game
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func TestSaveEntities_RoundTripsEveryComponent(t *testing.T) {
+	g := createTestGame()
+	g.SpawnPlayer(gruid.Point{X: 2, Y: 2}, g.items)
+	monster := g.SpawnMonster(MonsterTemplate{ID: "rat", Name: "Rat", Glyph: "r", Speed: 80, HP: 3, Behavior: "wander", FOVRange: 4}, gruid.Point{X: 5, Y: 5})
+
+	saved, err := saveEntities(g.ecs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []SavedEntity
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadEntities(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	world := ecs.NewECS()
+	restoreEntities(world, loaded)
+
+	for _, id := range []ecs.EntityID{g.PlayerID, monster} {
+		want, got := g.ecs.ComponentsOf(id), world.ComponentsOf(id)
+		if len(got) != len(want) {
+			t.Errorf("Entity %d: expected %d components back, got %d", id, len(want), len(got))
+		}
+		for compType, comp := range want {
+			if reflect.TypeOf(got[compType]) != reflect.TypeOf(comp) {
+				t.Errorf("Entity %d: expected %s restored as %T, got %T", id, compType, comp, got[compType])
+			}
+		}
+	}
+	if name := world.GetNameSafe(monster); name != "Rat" {
+		t.Errorf("Expected the monster's name restored, got %q", name)
+	}
+	if fov := world.GetFOVSafe(g.PlayerID); fov == nil || fov.Range != 10 || fov.GetFOVCalculator() == nil {
+		t.Errorf("Expected the player's FOV restored with its calculator, got %+v", fov)
+	}
+}
+
+func TestLoadEntities_FailsOnUnknownComponent(t *testing.T) {
+	saved := []SavedEntity{{
+		ID: ecs.NewEntityID(1, 0),
+		Components: map[components.ComponentType]json.RawMessage{
+			components.CHealth: json.RawMessage(`{"CurrentHP": 3, "MaxHP": 5}`),
+			"position":         json.RawMessage(`{"X": 1, "Y": 1}`),
+		},
+	}}
+	if _, err := loadEntities(saved); err == nil {
+		t.Error("Expected an unknown component type to fail loading")
+	}
+}
@@ -0,0 +1,226 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// Phase is a named step of a game round. The scheduler runs the systems of
+// a phase in the order they were added.
+type Phase int
+
+const (
+	PhasePreTurn    Phase = iota // Before actors take turns: perception and AI planning
+	PhaseAction                  // Actors take their turns until the player's comes up
+	PhasePostAction              // After every executed action
+	PhaseEndOfRound              // Once the player is back in control
+	phaseCount
+)
+
+// String returns the phase name used in timing reports
+func (p Phase) String() string {
+	switch p {
+	case PhasePreTurn:
+		return "pre-turn"
+	case PhaseAction:
+		return "action"
+	case PhasePostAction:
+		return "post-action"
+	case PhaseEndOfRound:
+		return "end-of-round"
+	default:
+		return "unknown"
+	}
+}
+
+// System is a unit of game logic run by the Scheduler. Reads and Writes
+// declare the components it accesses, so the data flow between systems is
+// visible without reading their code.
+type System interface {
+	Name() string
+	Reads() []components.ComponentType
+	Writes() []components.ComponentType
+	Run(g *Game)
+}
+
+// funcSystem adapts a function to the System interface
+type funcSystem struct {
+	name   string
+	reads  []components.ComponentType
+	writes []components.ComponentType
+	run    func(g *Game)
+}
+
+// NewSystem creates a System running fn.
+func NewSystem(name string, reads, writes []components.ComponentType, fn func(g *Game)) System {
+	return funcSystem{name: name, reads: reads, writes: writes, run: fn}
+}
+
+func (s funcSystem) Name() string                       { return s.name }
+func (s funcSystem) Reads() []components.ComponentType  { return s.reads }
+func (s funcSystem) Writes() []components.ComponentType { return s.writes }
+func (s funcSystem) Run(g *Game)                        { s.run(g) }
+
+// SystemTiming accumulates the run time of a system in one phase
+type SystemTiming struct {
+	Phase Phase
+	Name  string
+	Runs  int
+	Total time.Duration
+	Max   time.Duration
+}
+
+// Average returns the mean run time.
+func (t SystemTiming) Average() time.Duration {
+	if t.Runs == 0 {
+		return 0
+	}
+	return t.Total / time.Duration(t.Runs)
+}
+
+// scheduledSystem is a system with its timing in one phase
+type scheduledSystem struct {
+	system System
+	timing SystemTiming
+}
+
+// Scheduler runs systems phase by phase and times each of them. A system
+// may be added to several phases; it is timed separately in each.
+type Scheduler struct {
+	phases [phaseCount][]*scheduledSystem
+}
+
+// NewScheduler creates a scheduler without systems.
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Add appends a system to a phase. Names must be unique within a phase.
+func (s *Scheduler) Add(phase Phase, system System) error {
+	if phase < 0 || phase >= phaseCount {
+		return fmt.Errorf("unknown phase %d", phase)
+	}
+	for _, scheduled := range s.phases[phase] {
+		if scheduled.system.Name() == system.Name() {
+			return fmt.Errorf("system %q already runs in phase %s", system.Name(), phase)
+		}
+	}
+	s.phases[phase] = append(s.phases[phase], &scheduledSystem{
+		system: system,
+		timing: SystemTiming{Phase: phase, Name: system.Name()},
+	})
+	return nil
+}
+
+// Systems returns the systems of a phase in run order.
+func (s *Scheduler) Systems(phase Phase) []System {
+	systems := make([]System, 0, len(s.phases[phase]))
+	for _, scheduled := range s.phases[phase] {
+		systems = append(systems, scheduled.system)
+	}
+	return systems
+}
+
+// Run runs every system of a phase in order. Each system is followed by a
+// sync point applying the structural changes it deferred to g.commands.
+func (s *Scheduler) Run(g *Game, phase Phase) {
+	for _, scheduled := range s.phases[phase] {
+		start := time.Now()
+		scheduled.system.Run(g)
+		g.commands.Apply()
+		elapsed := time.Since(start)
+
+		scheduled.timing.Runs++
+		scheduled.timing.Total += elapsed
+		scheduled.timing.Max = max(scheduled.timing.Max, elapsed)
+	}
+}
+
+// Timings returns the timing of every scheduled system, by phase then run
+// order.
+func (s *Scheduler) Timings() []SystemTiming {
+	var timings []SystemTiming
+	for _, systems := range s.phases {
+		for _, scheduled := range systems {
+			timings = append(timings, scheduled.timing)
+		}
+	}
+	return timings
+}
+
+// LogTimings writes the timings to the log, on request and at shutdown.
+func (s *Scheduler) LogTimings() {
+	for _, t := range s.Timings() {
+		slog.Info("System timing", "phase", t.Phase, "system", t.Name, "runs", t.Runs, "avg", t.Average(), "max", t.Max, "total", t.Total)
+	}
+}
+
+// newGameScheduler registers the game's systems in their phases
+func newGameScheduler() *Scheduler {
+	s := NewScheduler()
+
+	// Fields of view are brought up to date before monsters plan with them
+	// and after every action, since actions move entities around
+	fov := NewSystem("fov",
+		[]components.ComponentType{components.CPosition},
+		[]components.ComponentType{components.CFOV},
+		(*Game).FOVSystem,
+	)
+	monsterAI := NewSystem("monster-ai",
+		[]components.ComponentType{components.CAITag, components.CPosition, components.CFOV, components.CHealth},
+		[]components.ComponentType{components.CTurnActor, components.CAIComponent, components.CPathfindingComponent},
+		(*Game).monstersTurn,
+	)
+	summonAI := NewSystem("summon-ai",
+		[]components.ComponentType{components.CSummoned, components.CPosition, components.CFOV},
+		[]components.ComponentType{components.CTurnActor},
+		(*Game).summonsTurn,
+	)
+	turns := NewSystem("turn-queue",
+		[]components.ComponentType{components.CTurnActor, components.CStatusEffects},
+		[]components.ComponentType{components.CTurnActor, components.CHealth, components.CMana, components.CStatusEffects, components.CSummoned},
+		(*Game).runTurnQueue,
+	)
+	cleanup := NewSystem("turn-queue-cleanup",
+		[]components.ComponentType{components.CTurnActor, components.CHealth, components.CCorpseTag},
+		nil,
+		(*Game).cleanupTurnQueue,
+	)
+
+	for _, step := range []struct {
+		phase  Phase
+		system System
+	}{
+		{PhasePreTurn, fov},
+		{PhasePreTurn, monsterAI},
+		{PhasePreTurn, summonAI},
+		{PhaseAction, turns},
+		{PhasePostAction, fov},
+		{PhaseEndOfRound, cleanup},
+	} {
+		if err := s.Add(step.phase, step.system); err != nil {
+			panic(err) // The set above is fixed, so this is a programming error
+		}
+	}
+	return s
+}
+
+// runRound runs every phase until the player's next turn: the actors plan,
+// act in turn order, then the round is wrapped up. If the action phase
+// yields first, the round is left pending for resumeRound.
+func (g *Game) runRound() {
+	g.systems.Run(g, PhasePreTurn)
+	g.resumeRound()
+}
+
+// resumeRound carries on with the action phase of the current round and
+// wraps the round up once it completes
+func (g *Game) resumeRound() {
+	g.systems.Run(g, PhaseAction)
+	if !g.turnsPending {
+		g.systems.Run(g, PhaseEndOfRound)
+	}
+}
@@ -0,0 +1,65 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScheduler_RunsPhasesInOrderAndTimes(t *testing.T) {
+	s := NewScheduler()
+	var order []string
+	record := func(name string) System {
+		return NewSystem(name, nil, nil, func(*Game) { order = append(order, name) })
+	}
+
+	for _, step := range []struct {
+		phase Phase
+		name  string
+	}{
+		{PhasePreTurn, "a"}, {PhasePreTurn, "b"}, {PhaseEndOfRound, "c"},
+	} {
+		if err := s.Add(step.phase, record(step.name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Add(PhasePreTurn, record("a")); err == nil {
+		t.Error("Expected a duplicate system name in a phase to be rejected")
+	}
+	if err := s.Add(PhaseAction, record("a")); err != nil {
+		t.Errorf("Expected a system to be allowed in another phase: %v", err)
+	}
+
+	g := NewGame()
+	s.Run(g, PhasePreTurn)
+	s.Run(g, PhaseEndOfRound)
+	s.Run(g, PhasePreTurn)
+
+	if want := []string{"a", "b", "c", "a", "b"}; !slices.Equal(order, want) {
+		t.Errorf("Expected run order %v, got %v", want, order)
+	}
+
+	runs := map[string]int{}
+	for _, timing := range s.Timings() {
+		runs[timing.Phase.String()+"/"+timing.Name] = timing.Runs
+	}
+	want := map[string]int{"pre-turn/a": 2, "pre-turn/b": 2, "action/a": 0, "end-of-round/c": 1}
+	for key, n := range want {
+		if runs[key] != n {
+			t.Errorf("Expected %s to run %d times, got %d", key, n, runs[key])
+		}
+	}
+}
+
+func TestGameScheduler_Systems(t *testing.T) {
+	s := newGameScheduler()
+	var names []string
+	for _, system := range s.Systems(PhasePreTurn) {
+		names = append(names, system.Name())
+		if len(system.Writes()) == 0 {
+			t.Errorf("Expected %s to declare the components it writes", system.Name())
+		}
+	}
+	if want := []string{"fov", "monster-ai", "summon-ai"}; !slices.Equal(names, want) {
+		t.Errorf("Expected pre-turn systems %v, got %v", want, names)
+	}
+}
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+)
+
+// Speed is expressed in percent of normal speed: an actor at 200% takes
+// half as long as a normal one to recover from the same action. The base
+// comes from TurnActor.Speed, which is the time a normal action takes the
+// actor, and status effects and encumbrance add percent modifiers.
+const (
+	normalSpeed      = 100
+	minSpeedModifier = -75 // Slows can't bring an actor below a quarter of its speed
+
+	normalActionTime = 100 // TurnActor.Speed of a normal actor, and of actors missing one
+
+	carryPerStrength = 5   // Weight carried without penalty per point of strength
+	burdenedModifier = -25 // Carrying more than the limit
+	overloadModifier = -50 // Carrying more than one and a half times the limit
+)
+
+// burden returns the weight an entity carries and the most it can carry
+// without slowing down. Entities without attributes have no limit.
+func (g *Game) burden(id ecs.EntityID) (carried, limit int, limited bool) {
+	for _, child := range g.ecs.Children(id) {
+		if pickup, ok := g.ecs.GetItemPickup(child); ok {
+			carried += pickup.Item.Weight * pickup.Quantity
+		}
+	}
+	if !g.ecs.HasStatsSafe(id) {
+		return carried, 0, false
+	}
+	return carried, g.effectiveStats(id).Strength * carryPerStrength, true
+}
+
+// speedModifier returns the summed percent change to an entity's speed
+// from status effects and encumbrance
+func (g *Game) speedModifier(id ecs.EntityID) int {
+	effects := g.ecs.GetStatusEffectsSafe(id)
+	modifier := effects.SpeedModifier()
+
+	if carried, limit, limited := g.burden(id); limited {
+		switch {
+		case carried > limit*3/2:
+			modifier += overloadModifier
+		case carried > limit:
+			modifier += burdenedModifier
+		}
+	}
+	return max(modifier, minSpeedModifier)
+}
+
+// baseActionTime returns how long a normal action takes the entity before
+// modifiers
+func (g *Game) baseActionTime(id ecs.EntityID) uint64 {
+	if actor, ok := g.ecs.GetTurnActor(id); ok && actor.Speed > 0 {
+		return actor.Speed
+	}
+	return normalActionTime
+}
+
+// actionDelay converts the cost of an action into the time until the
+// actor's next turn, scaled by its current speed
+func (g *Game) actionDelay(id ecs.EntityID, cost uint) uint64 {
+	return uint64(cost) * g.baseActionTime(id) / uint64(normalSpeed+g.speedModifier(id))
+}
+
+// speedPercent returns the entity's current speed in percent of normal
+func (g *Game) speedPercent(id ecs.EntityID) int {
+	return (normalSpeed + g.speedModifier(id)) * normalActionTime / int(g.baseActionTime(id))
+}
+
+// baseSpeedPercent returns the entity's speed in percent of normal without
+// modifiers
+func (g *Game) baseSpeedPercent(id ecs.EntityID) int {
+	return normalSpeed * normalActionTime / int(g.baseActionTime(id))
+}
+
+// PlayerSpeed returns the player's current and unmodified speed in percent
+// of normal, for UI access
+func (g *Game) PlayerSpeed() (current, base int) {
+	return g.speedPercent(g.PlayerID), g.baseSpeedPercent(g.PlayerID)
+}
+
+// PlayerBurden returns the weight the player carries and can carry without
+// slowing down, for UI access
+func (g *Game) PlayerBurden() (carried, limit int) {
+	carried, limit, _ = g.burden(g.PlayerID)
+	return carried, limit
+}
+
+// relativeSpeed describes how fast an entity acts compared with the player
+func (g *Game) relativeSpeed(id ecs.EntityID) string {
+	ratio := g.speedPercent(id) * 100 / max(g.speedPercent(g.PlayerID), 1)
+	switch {
+	case ratio >= 190:
+		return fmt.Sprintf("much faster than you (%d%%)", ratio)
+	case ratio > 120:
+		return fmt.Sprintf("faster than you (%d%%)", ratio)
+	case ratio >= 80:
+		return fmt.Sprintf("about as fast as you (%d%%)", ratio)
+	case ratio > 55:
+		return fmt.Sprintf("slower than you (%d%%)", ratio)
+	default:
+		return fmt.Sprintf("much slower than you (%d%%)", ratio)
+	}
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func TestActionDelay_ScalesWithSpeed(t *testing.T) {
+	tests := []struct {
+		name    string
+		effects []components.StatusEffect
+		want    uint64
+	}{
+		{"normal", nil, 100},
+		{"hasted", []components.StatusEffect{components.NewHasteEffect(5)}, 50},
+		{"slowed", []components.StatusEffect{components.NewSlowEffect(5)}, 200},
+		{"hasted and slowed", []components.StatusEffect{components.NewHasteEffect(5), components.NewSlowEffect(5)}, 66},
+		{"slow floor", []components.StatusEffect{components.NewSlowEffect(5), {Name: "Frozen", Duration: 5, SpeedMod: -90}}, 400},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := createAfflictedTestGame(10, tt.effects...)
+			if got := g.actionDelay(g.PlayerID, 100); got != tt.want {
+				t.Errorf("actionDelay() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestActionDelay_UsesActorBaseSpeed(t *testing.T) {
+	g := createTestGame()
+	rat := g.ecs.AddEntity()
+	g.ecs.AddComponents(rat, components.NewTurnActor(80))
+
+	if got := g.actionDelay(rat, 100); got != 80 {
+		t.Errorf("Expected a normal action to take the rat 80, got %d", got)
+	}
+	if got := g.actionDelay(rat, 50); got != 40 {
+		t.Errorf("Expected a cheap action to take the rat 40, got %d", got)
+	}
+}
+
+func TestSpeedModifier_Encumbrance(t *testing.T) {
+	g := createHolderTestGame(10)
+	g.ecs.AddComponents(g.PlayerID, components.NewStats()) // Strength 10 carries 50
+	rock := components.Item{Name: "Rock", Type: components.ItemTypeMisc, Stackable: true, MaxStack: 100, Weight: 10}
+
+	g.giveItem(g.PlayerID, rock, 5)
+	if got := g.speedModifier(g.PlayerID); got != 0 {
+		t.Errorf("Expected no penalty at the limit, got %d", got)
+	}
+	g.giveItem(g.PlayerID, rock, 1)
+	if got := g.speedModifier(g.PlayerID); got != burdenedModifier {
+		t.Errorf("Expected the burdened penalty, got %d", got)
+	}
+	g.giveItem(g.PlayerID, rock, 2)
+	if got := g.speedModifier(g.PlayerID); got != overloadModifier {
+		t.Errorf("Expected the overload penalty, got %d", got)
+	}
+	if carried, limit := g.PlayerBurden(); carried != 80 || limit != 50 {
+		t.Errorf("Expected a burden of 80 / 50, got %d / %d", carried, limit)
+	}
+}
+
+func TestProcessTurnQueue_HastedPlayerActsSooner(t *testing.T) {
+	g := createAfflictedTestGame(10, components.NewHasteEffect(5))
+	md := &Model{game: g}
+
+	actor := g.ecs.GetTurnActorSafe(g.PlayerID)
+	actor.AddAction(WaitAction{EntityID: g.PlayerID})
+	md.processTurnQueue()
+
+	if !g.waitingForInput || g.turnQueue.CurrentTime != 50 {
+		t.Errorf("Expected the hasted player's next turn at time 50, got %d", g.turnQueue.CurrentTime)
+	}
+}
+
+func TestDescribeCreatureAt_RelativeSpeed(t *testing.T) {
+	g := createAfflictedTestGame(10)
+	g.ecs.AddComponents(g.PlayerID, components.NewFOVComponent(8, g.dungeon.Width, g.dungeon.Height))
+	g.FOVSystem()
+
+	rat := addTestMonster(g, gruid.Point{X: 6, Y: 5})
+	g.ecs.AddComponents(rat, components.Name{Name: "Rat"}, components.NewTurnActor(50))
+
+	if got := g.describeCreatureAt(gruid.Point{X: 6, Y: 5}); !strings.HasPrefix(got, "Rat: moves much faster than you") {
+		t.Errorf("Unexpected description %q", got)
+	}
+	if got := g.describeCreatureAt(gruid.Point{X: 7, Y: 7}); got != "" {
+		t.Errorf("Expected no description of an empty cell, got %q", got)
+	}
+}
@@ -0,0 +1,358 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"unicode/utf8"
+
+	"codeberg.org/anaseto/gruid"
+	"codeberg.org/anaseto/gruid/paths"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui"
+)
+
+// SpellsFile is the data file, under assets/data, defining every spell
+const SpellsFile = "spells.json"
+
+// SpellKind selects what a spell does when cast
+type SpellKind string
+
+const (
+	SpellBolt   SpellKind = "bolt"   // Projectile that damages the first creature hit
+	SpellHeal   SpellKind = "heal"   // Restores the caster's health
+	SpellSummon SpellKind = "summon" // Conjures an ally next to the caster
+	SpellBuff   SpellKind = "buff"   // Temporary combat modifier on the caster
+	SpellDetect SpellKind = "detect" // Senses nearby monsters through walls
+)
+
+// Magic schools, each trained by the Skills field of the same name
+const (
+	SchoolEvocation   = "evocation"
+	SchoolConjuration = "conjuration"
+	SchoolEnchantment = "enchantment"
+	SchoolDivination  = "divination"
+)
+
+// Spell is a data-defined spell. Its power is Power plus PowerPerSkill for
+// every level of the caster's skill in the spell's school; it is the damage
+// of bolts, the HP healed, the summon's strength, the buff's modifier and
+// the detection radius.
+type Spell struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	Kind          SpellKind `json:"kind"`
+	School        string    `json:"school"`
+	ManaCost      int       `json:"mana_cost"`
+	Power         int       `json:"power"`
+	PowerPerSkill int       `json:"power_per_skill"`
+	Range         int       `json:"range,omitempty"`    // Bolts only
+	Duration      int       `json:"duration,omitempty"` // Turns a buff, detection or summon lasts
+	Stat          string    `json:"stat,omitempty"`     // Buffs: "attack", "defense", "accuracy" or "dodge"
+	Summon        string    `json:"summon,omitempty"`   // Summons: name of the creature
+	Glyph         string    `json:"glyph,omitempty"`    // Summons: map glyph of the creature
+}
+
+// IsTargeted reports whether casting the spell requires picking a target
+func (s Spell) IsTargeted() bool {
+	return s.Kind == SpellBolt
+}
+
+// startingSpells are the spells the player knows at the start of a run
+var startingSpells = []string{"magic_missile", "minor_heal", "stoneskin", "summon_wolf", "detect_monsters"}
+
+// validateSpell checks a spell definition for missing or inconsistent fields
+func validateSpell(s Spell) error {
+	if s.ID == "" || s.Name == "" {
+		return fmt.Errorf("spell must have an id and a name")
+	}
+	if !slices.Contains([]string{SchoolEvocation, SchoolConjuration, SchoolEnchantment, SchoolDivination}, s.School) {
+		return fmt.Errorf("spell %q has unknown school %q", s.ID, s.School)
+	}
+	if s.ManaCost < 0 || s.Power < 0 || s.PowerPerSkill < 0 {
+		return fmt.Errorf("spell %q has negative mana cost or power", s.ID)
+	}
+
+	switch s.Kind {
+	case SpellBolt:
+		if s.Range <= 0 {
+			return fmt.Errorf("bolt spell %q needs a positive range", s.ID)
+		}
+	case SpellHeal:
+	case SpellBuff:
+		if !slices.Contains([]string{"attack", "defense", "accuracy", "dodge"}, s.Stat) {
+			return fmt.Errorf("buff spell %q has unknown stat %q", s.ID, s.Stat)
+		}
+		if s.Duration <= 0 {
+			return fmt.Errorf("buff spell %q needs a positive duration", s.ID)
+		}
+	case SpellSummon:
+		if s.Summon == "" || utf8.RuneCountInString(s.Glyph) != 1 {
+			return fmt.Errorf("summon spell %q needs a creature name and a single-character glyph", s.ID)
+		}
+		if s.Duration <= 0 {
+			return fmt.Errorf("summon spell %q needs a positive duration", s.ID)
+		}
+	case SpellDetect:
+		if s.Duration <= 0 {
+			return fmt.Errorf("detect spell %q needs a positive duration", s.ID)
+		}
+	default:
+		return fmt.Errorf("spell %q has unknown kind %q", s.ID, s.Kind)
+	}
+
+	return nil
+}
+
+// newSpellRegistry indexes spells by ID, rejecting invalid or duplicate ones
+func newSpellRegistry(spells []Spell) (map[string]Spell, error) {
+	registry := make(map[string]Spell, len(spells))
+	var errs []error
+	for _, s := range spells {
+		if err := validateSpell(s); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, exists := registry[s.ID]; exists {
+			errs = append(errs, fmt.Errorf("duplicate spell id %q", s.ID))
+			continue
+		}
+		registry[s.ID] = s
+	}
+	return registry, errors.Join(errs...)
+}
+
+// LoadSpells loads the spell definitions embedded from assets/data
+func LoadSpells() map[string]Spell {
+	spells, err := loadData[[]Spell](SpellsFile)
+	if err != nil {
+		slog.Error("Failed to load spells, no spells available", "error", err)
+		return map[string]Spell{}
+	}
+
+	registry, err := newSpellRegistry(spells)
+	if err != nil {
+		slog.Warn("Skipped invalid spell definitions", "error", err)
+	}
+	return registry
+}
+
+// knownSpells returns the spells in an entity's spellbook, in book order
+func (g *Game) knownSpells(id ecs.EntityID) []Spell {
+	var known []Spell
+	for _, spellID := range g.ecs.GetSpellbookSafe(id).Spells {
+		if spell, ok := g.spells[spellID]; ok {
+			known = append(known, spell)
+		}
+	}
+	return known
+}
+
+// schoolSkill returns the caster's skill level in a magic school
+func schoolSkill(skills components.Skills, school string) int {
+	switch school {
+	case SchoolEvocation:
+		return skills.Evocation
+	case SchoolConjuration:
+		return skills.Conjuration
+	case SchoolEnchantment:
+		return skills.Enchantment
+	case SchoolDivination:
+		return skills.Divination
+	}
+	return 0
+}
+
+// spellPower returns the strength of a spell cast by the given entity
+func (g *Game) spellPower(casterID ecs.EntityID, spell Spell) int {
+	skill := schoolSkill(g.ecs.GetSkillsSafe(casterID), spell.School)
+	return spell.Power + skill*spell.PowerPerSkill
+}
+
+// CastSpellAction represents an entity casting a spell from its spellbook.
+// Target is only used by targeted spells.
+type CastSpellAction struct {
+	CasterID ecs.EntityID
+	SpellID  string
+	Target   gruid.Point
+}
+
+// Execute spends the spell's mana and applies its effect.
+func (a CastSpellAction) Execute(g *Game) (cost uint, err error) {
+	spell, ok := g.spells[a.SpellID]
+	if !ok {
+		return 0, fmt.Errorf("unknown spell %q", a.SpellID)
+	}
+
+	spellbook := g.ecs.GetSpellbookSafe(a.CasterID)
+	if !spellbook.Knows(a.SpellID) {
+		return 0, fmt.Errorf("entity %d does not know %s", a.CasterID, spell.Name)
+	}
+
+	mana, ok := g.ecs.GetMana(a.CasterID)
+	if !ok || !mana.UseMana(spell.ManaCost) {
+		if a.CasterID == g.PlayerID {
+			g.log.AddMessagef(ui.ColorStatusBad, "You don't have enough mana to cast %s.", spell.Name)
+		}
+		return 0, fmt.Errorf("entity %d lacks mana for %s", a.CasterID, spell.Name)
+	}
+	g.ecs.AddComponent(a.CasterID, components.CMana, mana)
+
+	power := g.spellPower(a.CasterID, spell)
+	casterName := g.ecs.GetNameSafe(a.CasterID)
+	slog.Info("Spell cast", "caster", casterName, "casterId", a.CasterID, "spell", spell.ID, "power", power)
+
+	switch spell.Kind {
+	case SpellBolt:
+		g.castBolt(a.CasterID, spell, power, a.Target)
+	case SpellHeal:
+		g.castHeal(a.CasterID, spell, power)
+	case SpellBuff:
+		g.castBuff(a.CasterID, spell, power)
+	case SpellSummon:
+		g.castSummon(a.CasterID, spell, power)
+	case SpellDetect:
+		g.castDetect(a.CasterID, spell, power)
+	}
+
+	return 100, nil
+}
+
+// castBolt fires a magical projectile. Bolts never miss and ignore armor.
+func (g *Game) castBolt(casterID ecs.EntityID, spell Spell, power int, target gruid.Point) {
+	from := g.ecs.GetPositionSafe(casterID)
+	_, hitID := g.projectilePath(from, target, spell.Range)
+	if hitID == 0 {
+		g.log.AddMessagef(ui.ColorStatusNeutral, "%s's %s hits nothing.", g.ecs.GetNameSafe(casterID), spell.Name)
+		return
+	}
+	g.applyAttack(casterID, hitID, AttackResult{Hit: true, Damage: max(power, 1)}, "blasts")
+}
+
+// castHeal restores up to power HP to the caster
+func (g *Game) castHeal(casterID ecs.EntityID, spell Spell, power int) {
+	health, ok := g.ecs.GetHealth(casterID)
+	if !ok {
+		return
+	}
+
+	healed := min(power, health.MaxHP-health.CurrentHP)
+	health.CurrentHP += healed
+	g.ecs.AddComponent(casterID, components.CHealth, health)
+
+	g.log.AddMessagef(ui.ColorStatusGood, "%s casts %s and recovers %d HP.", g.ecs.GetNameSafe(casterID), spell.Name, healed)
+}
+
+// castBuff grants the caster a temporary modifier equal to power
+func (g *Game) castBuff(casterID ecs.EntityID, spell Spell, power int) {
+	effect := components.StatusEffect{
+		Name:        spell.Name,
+		Duration:    spell.Duration,
+		Type:        "buff",
+		Description: spell.Description,
+	}
+	switch spell.Stat {
+	case "attack":
+		effect.AttackMod = power
+	case "defense":
+		effect.DefenseMod = power
+	case "accuracy":
+		effect.AccuracyMod = power
+	case "dodge":
+		effect.DodgeMod = power
+	}
+
+	g.addStatusEffect(casterID, effect)
+	g.log.AddMessagef(ui.ColorStatusGood, "%s casts %s (+%d %s).", g.ecs.GetNameSafe(casterID), spell.Name, power, spell.Stat)
+}
+
+// castDetect lets the caster sense monsters within power cells
+func (g *Game) castDetect(casterID ecs.EntityID, spell Spell, power int) {
+	g.addStatusEffect(casterID, components.StatusEffect{
+		Name:           spell.Name,
+		Duration:       spell.Duration,
+		Type:           "buff",
+		Description:    spell.Description,
+		DetectMonsters: power,
+	})
+
+	pos := g.ecs.GetPositionSafe(casterID)
+	sensed := 0
+	for _, id := range g.ecs.GetEntitiesWithComponents(components.CAITag, components.CPosition) {
+		if paths.DistanceChebyshev(pos, g.ecs.GetPositionSafe(id)) <= power {
+			sensed++
+		}
+	}
+	g.log.AddMessagef(ui.ColorStatusGood, "%s casts %s and senses %d creatures nearby.", g.ecs.GetNameSafe(casterID), spell.Name, sensed)
+}
+
+// castSummon conjures an allied creature on a free cell next to the caster.
+// Its health and attack grow with power.
+func (g *Game) castSummon(casterID ecs.EntityID, spell Spell, power int) {
+	pos, ok := g.freeCellNear(g.ecs.GetPositionSafe(casterID))
+	if !ok {
+		g.log.AddMessagef(ui.ColorStatusBad, "There is no room for the %s to appear.", spell.Summon)
+		return
+	}
+
+	glyph, _ := utf8.DecodeRuneInString(spell.Glyph)
+	combat := components.NewCombat()
+	combat.AttackPower = max(power/2, 1)
+
+	id := g.SpawnBlueprint("summon", pos,
+		components.Summoned{TurnsLeft: spell.Duration},
+		components.Name{Name: spell.Summon},
+		components.Renderable{Glyph: glyph, Color: ui.ColorStatusGood},
+		components.NewHealth(power),
+		combat,
+	)
+	g.turnQueue.Add(id, g.turnQueue.CurrentTime+100)
+
+	g.log.AddMessagef(ui.ColorStatusGood, "%s casts %s. A %s appears!", g.ecs.GetNameSafe(casterID), spell.Name, spell.Summon)
+}
+
+// freeCellNear returns a walkable, unoccupied cell adjacent to p
+func (g *Game) freeCellNear(p gruid.Point) (gruid.Point, bool) {
+	for _, d := range []gruid.Point{
+		{X: 1, Y: 0}, {X: -1, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: -1},
+		{X: 1, Y: 1}, {X: -1, Y: 1}, {X: 1, Y: -1}, {X: -1, Y: -1},
+	} {
+		q := p.Add(d)
+		if g.dungeon.isWalkable(q) && len(g.ecs.GetEntitiesAtWithComponents(q, components.CBlocksMovement)) == 0 {
+			return q, true
+		}
+	}
+	return gruid.Point{}, false
+}
+
+// addStatusEffect adds or refreshes a status effect on an entity
+func (g *Game) addStatusEffect(id ecs.EntityID, effect components.StatusEffect) {
+	effects, ok := g.ecs.GetStatusEffects(id)
+	if !ok {
+		effects = components.NewStatusEffects()
+	}
+	effects.AddEffect(effect)
+	g.ecs.AddComponent(id, components.CStatusEffects, effects)
+}
+
+// detectionRadius returns the largest monster detection radius granted by
+// the entity's active effects, or 0 if it senses nothing.
+func (g *Game) detectionRadius(id ecs.EntityID) int {
+	radius := 0
+	for _, effect := range g.ecs.GetStatusEffectsSafe(id).Effects {
+		radius = max(radius, effect.DetectMonsters)
+	}
+	return radius
+}
+
+// isAllied reports whether two entities fight on the same side: the player
+// and their summoned creatures.
+func (g *Game) isAllied(a, b ecs.EntityID) bool {
+	friendly := func(id ecs.EntityID) bool {
+		return id == g.PlayerID || g.ecs.HasSummonedSafe(id)
+	}
+	return friendly(a) && friendly(b)
+}
@@ -0,0 +1,178 @@
+package game
+
+import (
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+func TestSpellsFile_IsValid(t *testing.T) {
+	spells, err := loadData[[]Spell](SpellsFile)
+	if err != nil {
+		t.Fatalf("Failed to load %s: %v", SpellsFile, err)
+	}
+	registry, err := newSpellRegistry(spells)
+	if err != nil {
+		t.Errorf("Invalid spell definitions: %v", err)
+	}
+
+	for _, id := range startingSpells {
+		if _, ok := registry[id]; !ok {
+			t.Errorf("Starting spell %q is not defined", id)
+		}
+	}
+}
+
+func TestLoadSpells_OutsideRepo(t *testing.T) {
+	t.Chdir(t.TempDir()) // Installed games don't run from a checkout
+
+	if _, ok := LoadSpells()["magic_missile"]; !ok {
+		t.Error("Expected the embedded spells loaded")
+	}
+}
+
+func TestValidateSpell(t *testing.T) {
+	valid := Spell{ID: "bolt", Name: "Bolt", Kind: SpellBolt, School: SchoolEvocation, Range: 5}
+
+	tests := []struct {
+		name    string
+		modify  func(s *Spell)
+		wantErr bool
+	}{
+		{"valid", func(s *Spell) {}, false},
+		{"missing id", func(s *Spell) { s.ID = "" }, true},
+		{"unknown kind", func(s *Spell) { s.Kind = "fireworks" }, true},
+		{"unknown school", func(s *Spell) { s.School = "necromancy" }, true},
+		{"bolt without range", func(s *Spell) { s.Range = 0 }, true},
+		{"buff with unknown stat", func(s *Spell) { s.Kind, s.Duration, s.Stat = SpellBuff, 5, "luck" }, true},
+		{"summon without creature", func(s *Spell) { s.Kind, s.Duration = SpellSummon, 5 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spell := valid
+			tt.modify(&spell)
+			if err := validateSpell(spell); (err != nil) != tt.wantErr {
+				t.Errorf("validateSpell() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// createCasterTestGame sets up a caster at (2,5) knowing the starting spells
+func createCasterTestGame(mp int) *Game {
+	g := createTestGame()
+	g.spells = LoadSpells()
+
+	g.PlayerID = g.ecs.AddEntity()
+	g.ecs.AddComponents(g.PlayerID,
+		gruid.Point{X: 2, Y: 5},
+		components.Name{Name: "Player"},
+		components.NewHealth(20),
+		components.NewSkills(),
+		components.NewMana(mp),
+		components.NewSpellbook(startingSpells...),
+	)
+	return g
+}
+
+func TestCastSpell_ConsumesMana(t *testing.T) {
+	g := createCasterTestGame(3)
+	action := CastSpellAction{CasterID: g.PlayerID, SpellID: "minor_heal"}
+
+	if _, err := action.Execute(g); err != nil {
+		t.Fatalf("Unexpected error casting: %v", err)
+	}
+	if mp := g.ecs.GetManaSafe(g.PlayerID).CurrentMP; mp != 0 {
+		t.Errorf("Expected 0 MP left, got %d", mp)
+	}
+
+	if _, err := action.Execute(g); err == nil {
+		t.Error("Expected an error when casting without enough mana")
+	}
+}
+
+func TestCastSpell_RequiresKnownSpell(t *testing.T) {
+	g := createCasterTestGame(10)
+	g.ecs.AddComponent(g.PlayerID, components.CSpellbook, components.NewSpellbook())
+
+	action := CastSpellAction{CasterID: g.PlayerID, SpellID: "minor_heal"}
+	if _, err := action.Execute(g); err == nil {
+		t.Error("Expected an error casting an unknown spell")
+	}
+	if mp := g.ecs.GetManaSafe(g.PlayerID).CurrentMP; mp != 10 {
+		t.Errorf("Expected mana to be untouched, got %d", mp)
+	}
+}
+
+func TestCastSpell_BoltDamagesTarget(t *testing.T) {
+	g := createCasterTestGame(10)
+	monster := addTestMonster(g, gruid.Point{X: 6, Y: 5})
+
+	action := CastSpellAction{CasterID: g.PlayerID, SpellID: "magic_missile", Target: gruid.Point{X: 6, Y: 5}}
+	if _, err := action.Execute(g); err != nil {
+		t.Fatalf("Unexpected error casting: %v", err)
+	}
+
+	want := 100 - g.spellPower(g.PlayerID, g.spells["magic_missile"])
+	if hp := g.ecs.GetHealthSafe(monster).CurrentHP; hp != want {
+		t.Errorf("Expected monster HP %d, got %d", want, hp)
+	}
+}
+
+func TestCastSpell_HealIsCapped(t *testing.T) {
+	g := createCasterTestGame(10)
+	health := g.ecs.GetHealthSafe(g.PlayerID)
+	health.CurrentHP = health.MaxHP - 1
+	g.ecs.AddComponent(g.PlayerID, components.CHealth, health)
+
+	action := CastSpellAction{CasterID: g.PlayerID, SpellID: "minor_heal"}
+	if _, err := action.Execute(g); err != nil {
+		t.Fatalf("Unexpected error casting: %v", err)
+	}
+
+	if hp := g.ecs.GetHealthSafe(g.PlayerID); hp.CurrentHP != hp.MaxHP {
+		t.Errorf("Expected full health %d, got %d", hp.MaxHP, hp.CurrentHP)
+	}
+}
+
+func TestCastSpell_SummonAppearsNextToCaster(t *testing.T) {
+	g := createCasterTestGame(10)
+
+	action := CastSpellAction{CasterID: g.PlayerID, SpellID: "summon_wolf"}
+	if _, err := action.Execute(g); err != nil {
+		t.Fatalf("Unexpected error casting: %v", err)
+	}
+
+	summons := g.ecs.GetEntitiesWithComponents(components.CSummoned)
+	if len(summons) != 1 {
+		t.Fatalf("Expected 1 summon, got %d", len(summons))
+	}
+	if !g.isAllied(g.PlayerID, summons[0]) {
+		t.Error("Expected the summon to be allied with the player")
+	}
+
+	for range g.spells["summon_wolf"].Duration {
+		g.actorUpkeep(summons[0])
+	}
+	g.commands.Apply()
+	if g.ecs.EntityExists(summons[0]) {
+		t.Error("Expected the summon to fade away once its duration ran out")
+	}
+}
+
+func TestSpellPower_ScalesWithSkill(t *testing.T) {
+	g := createCasterTestGame(10)
+	spell := g.spells["magic_missile"]
+
+	base := g.spellPower(g.PlayerID, spell)
+
+	skills := g.ecs.GetSkillsSafe(g.PlayerID)
+	skills.Evocation += 3
+	g.ecs.AddComponent(g.PlayerID, components.CSkills, skills)
+
+	if got, want := g.spellPower(g.PlayerID, spell), base+3*spell.PowerPerSkill; got != want {
+		t.Errorf("Expected spell power %d, got %d", want, got)
+	}
+}
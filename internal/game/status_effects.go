@@ -0,0 +1,87 @@
+package game
+
+import (
+	"log/slog"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui"
+)
+
+// confusedStumbleChance is the percent chance that a confused actor moves
+// in a random direction instead of the intended one.
+const confusedStumbleChance = 50
+
+// tickStatusEffects applies one turn of an entity's status effects: poison
+// deals damage, regeneration heals, and durations count down. Expired
+// effects are logged when the player can see the entity. It returns false
+// if the effects killed the entity.
+func (g *Game) tickStatusEffects(id ecs.EntityID) bool {
+	effects, ok := g.ecs.GetStatusEffects(id)
+	if !ok || len(effects.Effects) == 0 {
+		return true
+	}
+
+	name := g.ecs.GetNameSafe(id)
+	damage, healing := effects.TickTotals()
+	if health, ok := g.ecs.GetHealth(id); ok && (damage > 0 || healing > 0) {
+		health.CurrentHP = min(health.CurrentHP-damage+healing, health.MaxHP)
+		g.ecs.AddComponent(id, components.CHealth, health)
+
+		if damage > 0 {
+			if id == g.PlayerID {
+				g.AddDamageTaken(damage)
+			}
+			if g.playerCanSee(id) {
+				g.log.AddMessagef(ui.ColorStatusBad, "%s takes %d poison damage.", name, damage)
+			}
+		}
+		slog.Debug("Status effects ticked", "entityId", id, "damage", damage, "healing", healing, "hp", health.CurrentHP)
+
+		if health.IsDead() {
+			g.handleEntityDeath(id, name, 0)
+			return false
+		}
+	}
+
+	expired := effects.UpdateEffects()
+	g.ecs.AddComponent(id, components.CStatusEffects, effects)
+	for _, effect := range expired {
+		if id == g.PlayerID {
+			g.log.AddMessagef(ui.ColorStatusNeutral, "%s wears off.", effect.Name)
+		} else if g.playerCanSee(id) {
+			g.log.AddMessagef(ui.ColorStatusNeutral, "%s's %s wears off.", name, effect.Name)
+		}
+	}
+
+	return true
+}
+
+// confusedDirection returns the direction a move actually takes: a random
+// one some of the time if the entity is confused, dir otherwise.
+func (g *Game) confusedDirection(id ecs.EntityID, dir gruid.Point) gruid.Point {
+	effects := g.ecs.GetStatusEffectsSafe(id)
+	if !effects.IsConfused() || g.rand.Intn(100) >= confusedStumbleChance {
+		return dir
+	}
+
+	directions := []gruid.Point{
+		{X: -1, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: -1}, {X: 0, Y: 1},
+		{X: -1, Y: -1}, {X: 1, Y: -1}, {X: -1, Y: 1}, {X: 1, Y: 1},
+	}
+	stumble := directions[g.rand.Intn(len(directions))]
+	if id == g.PlayerID && stumble != dir {
+		g.log.AddMessagef(ui.ColorStatusBad, "You stumble in confusion.")
+	}
+	return stumble
+}
+
+// playerCanSee reports whether the entity is the player or in their view
+func (g *Game) playerCanSee(id ecs.EntityID) bool {
+	if id == g.PlayerID {
+		return true
+	}
+	fov := g.ecs.GetFOVSafe(g.PlayerID)
+	return fov != nil && fov.IsVisible(g.ecs.GetPositionSafe(id), g.dungeon.Width)
+}
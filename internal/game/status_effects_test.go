@@ -0,0 +1,103 @@
+package game
+
+import (
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// createAfflictedTestGame sets up a player at (5,5) under the given effects
+func createAfflictedTestGame(hp int, effects ...components.StatusEffect) *Game {
+	g := createTestGame()
+	g.PlayerID = g.ecs.AddEntity()
+
+	statusEffects := components.NewStatusEffects()
+	for _, effect := range effects {
+		statusEffects.AddEffect(effect)
+	}
+	g.ecs.AddComponents(g.PlayerID,
+		gruid.Point{X: 5, Y: 5},
+		components.Name{Name: "Player"},
+		components.NewHealth(hp),
+		components.NewTurnActor(100),
+		statusEffects,
+	)
+	g.turnQueue.Add(g.PlayerID, 0)
+	return g
+}
+
+func TestTickStatusEffects_PoisonAndRegeneration(t *testing.T) {
+	g := createAfflictedTestGame(20, components.NewPoisonEffect(3, 2), components.NewRegenerationEffect(1, 1))
+	health := g.ecs.GetHealthSafe(g.PlayerID)
+	health.CurrentHP = 10
+	g.ecs.AddComponent(g.PlayerID, components.CHealth, health)
+
+	wantHP := []int{8, 5, 5}
+	for turn, want := range wantHP {
+		if !g.tickStatusEffects(g.PlayerID) {
+			t.Fatalf("Turn %d: player should survive", turn)
+		}
+		if hp := g.ecs.GetHealthSafe(g.PlayerID).CurrentHP; hp != want {
+			t.Errorf("Turn %d: expected %d HP, got %d", turn, want, hp)
+		}
+	}
+
+	if effects := g.ecs.GetStatusEffectsSafe(g.PlayerID); len(effects.Effects) != 0 {
+		t.Errorf("Expected all effects to expire, got %v", effects.Effects)
+	}
+}
+
+func TestTickStatusEffects_PoisonCanKill(t *testing.T) {
+	g := createAfflictedTestGame(10)
+	monster := addTestMonster(g, gruid.Point{X: 3, Y: 3})
+	g.addStatusEffect(monster, components.NewPoisonEffect(100, 3))
+
+	if g.tickStatusEffects(monster) {
+		t.Error("Expected the poison to kill the monster")
+	}
+	g.commands.Apply()
+	if !g.ecs.HasComponent(monster, components.CCorpseTag) {
+		t.Error("Expected the monster to leave a corpse")
+	}
+}
+
+func TestProcessTurnQueue_ParalysisSkipsTurns(t *testing.T) {
+	g := createAfflictedTestGame(10, components.NewParalysisEffect(2))
+	md := &Model{game: g}
+
+	md.processTurnQueue()
+
+	if !g.waitingForInput {
+		t.Fatal("Expected the player to get a turn once paralysis wore off")
+	}
+	if g.turnQueue.CurrentTime != 200 {
+		t.Errorf("Expected two skipped turns (time 200), got time %d", g.turnQueue.CurrentTime)
+	}
+	if effects := g.ecs.GetStatusEffectsSafe(g.PlayerID); effects.IsParalyzed() {
+		t.Error("Expected paralysis to have worn off")
+	}
+}
+
+func TestConfusedDirection(t *testing.T) {
+	dir := gruid.Point{X: 1, Y: 0}
+
+	g := createAfflictedTestGame(10)
+	for range 20 {
+		if got := g.confusedDirection(g.PlayerID, dir); got != dir {
+			t.Fatalf("Unconfused move changed direction to %v", got)
+		}
+	}
+
+	g = createAfflictedTestGame(10, components.NewConfusionEffect(5))
+	g.SetSeed(1)
+	stumbled := false
+	for range 50 {
+		if g.confusedDirection(g.PlayerID, dir) != dir {
+			stumbled = true
+		}
+	}
+	if !stumbled {
+		t.Error("Expected a confused entity to stumble at least once in 50 moves")
+	}
+}
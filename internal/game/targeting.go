@@ -0,0 +1,242 @@
+package game
+
+import (
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"codeberg.org/anaseto/gruid"
+	"codeberg.org/anaseto/gruid/paths"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/config"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui"
+)
+
+// targetingState tracks the cursor while the player aims a ranged attack
+// or a targeted spell.
+type targetingState struct {
+	cursor   gruid.Point
+	targets  []ecs.EntityID // Visible monsters, nearest first
+	index    int            // Selected entry of targets, -1 after free cursor movement
+	maxRange int
+	spellID  string // Spell being aimed, empty when firing the ranged weapon
+}
+
+// visibleMonsters returns the living monsters in the player's view ordered
+// by distance, then by ID so the order is stable.
+func (g *Game) visibleMonsters() []ecs.EntityID {
+	fov := g.ecs.GetFOVSafe(g.PlayerID)
+	if fov == nil {
+		return nil
+	}
+
+	playerPos := g.GetPlayerPosition()
+	var monsters []ecs.EntityID
+	query := ecs.NewQuery2[gruid.Point, components.Health](g.ecs, components.CPosition, components.CHealth).With(components.CAITag)
+	query.Each(func(id ecs.EntityID, pos *gruid.Point, _ *components.Health) {
+		if fov.IsVisible(*pos, g.dungeon.Width) {
+			monsters = append(monsters, id)
+		}
+	})
+
+	slices.SortFunc(monsters, func(a, b ecs.EntityID) int {
+		da := paths.DistanceChebyshev(playerPos, g.ecs.GetPositionSafe(a))
+		db := paths.DistanceChebyshev(playerPos, g.ecs.GetPositionSafe(b))
+		if da != db {
+			return da - db
+		}
+		return int(a - b)
+	})
+
+	return monsters
+}
+
+// handleFireAction enters targeting mode if the player can shoot
+func (md *Model) handleFireAction() (again bool, eff gruid.Effect, err error) {
+	g := md.game
+
+	weapon, ok := g.rangedWeapon(g.PlayerID)
+	if !ok {
+		g.log.AddMessagef(ui.ColorStatusBad, "You have no ranged weapon equipped.")
+		return true, eff, nil // Don't consume turn
+	}
+	if _, ok := g.findAmmo(g.PlayerID, weapon); !ok {
+		g.log.AddMessagef(ui.ColorStatusBad, "You have no %ss left.", weapon.AmmoType)
+		return true, eff, nil // Don't consume turn
+	}
+
+	md.startTargeting(weapon.Range, "")
+	g.log.AddMessagef(ui.ColorStatusNeutral, "Aim with %s: Tab cycles targets, Enter fires, Esc cancels.", weapon.Name)
+	return true, eff, nil // Don't consume turn until firing
+}
+
+// startTargeting enters targeting mode with the cursor on the nearest
+// visible monster, for the ranged weapon or for spellID if set.
+func (md *Model) startTargeting(maxRange int, spellID string) {
+	md.targeting = targetingState{
+		cursor:   md.game.GetPlayerPosition(),
+		targets:  md.game.visibleMonsters(),
+		index:    -1,
+		maxRange: maxRange,
+		spellID:  spellID,
+	}
+	if len(md.targeting.targets) > 0 {
+		md.selectTarget(0)
+	}
+	md.mode = modeTargeting
+}
+
+// selectTarget moves the cursor onto the i-th visible monster
+func (md *Model) selectTarget(i int) {
+	t := &md.targeting
+	n := len(t.targets)
+	if n == 0 {
+		return
+	}
+	t.index = ((i % n) + n) % n
+	t.cursor = md.game.ecs.GetPositionSafe(t.targets[t.index])
+}
+
+// moveTargetCursor moves the cursor freely, staying on the map
+func (md *Model) moveTargetCursor(delta gruid.Point) {
+	next := md.targeting.cursor.Add(delta)
+	if md.game.dungeon.InBounds(next) {
+		md.targeting.cursor = next
+		md.targeting.index = -1
+	}
+}
+
+// processTargetingModeInput handles input while aiming
+func (md *Model) processTargetingModeInput(msg gruid.Msg) gruid.Effect {
+	var again bool
+	var effect gruid.Effect
+	var err error
+
+	switch msg := msg.(type) {
+	case gruid.MsgKeyDown:
+		again, effect, err = md.targetingModeAction(KEYS_TARGETING[msg.Key])
+		if _, ok := err.(actionError); ok {
+			err = fmt.Errorf("key '%s' does nothing while aiming", msg.Key)
+		}
+	case gruid.MsgMouse:
+		again, effect, err = md.targetingModeMouse(msg)
+	default:
+		slog.Debug("Unhandled message type in targeting mode", "type", fmt.Sprintf("%T", msg))
+		return nil
+	}
+
+	if err != nil {
+		slog.Debug("Error processing targeting input", "error", err)
+	}
+	if again {
+		return effect
+	}
+	return md.EndTurn()
+}
+
+// targetingModeMouse lets the mouse pick the target cell; clicking fires
+func (md *Model) targetingModeMouse(msg gruid.MsgMouse) (again bool, eff gruid.Effect, err error) {
+	x, y := md.camera.ScreenToWorld(msg.P.X, msg.P.Y)
+	p := gruid.Point{X: x, Y: y}
+	if !md.game.dungeon.InBounds(p) {
+		return true, eff, nil
+	}
+
+	switch msg.Action {
+	case gruid.MouseMove:
+		md.targeting.cursor = p
+		md.targeting.index = -1
+		return true, eff, nil
+	case gruid.MouseMain:
+		md.targeting.cursor = p
+		return md.targetingModeAction(ActionTargetConfirm)
+	}
+	return true, eff, nil
+}
+
+// targetingModeAction processes actions while aiming
+func (md *Model) targetingModeAction(action playerAction) (again bool, eff gruid.Effect, err error) {
+	g := md.game
+	t := &md.targeting
+
+	switch action {
+	case ActionW, ActionS, ActionN, ActionE:
+		md.moveTargetCursor(keyToDir(action))
+		return true, eff, nil
+
+	case ActionTargetNext:
+		md.selectTarget(t.index + 1)
+		return true, eff, nil
+
+	case ActionTargetPrev:
+		md.selectTarget(t.index - 1)
+		return true, eff, nil
+
+	case ActionTargetConfirm:
+		if t.cursor == g.GetPlayerPosition() {
+			g.log.AddMessagef(ui.ColorStatusBad, "You need to aim away from yourself.")
+			return true, eff, nil
+		}
+		md.mode = modeNormal
+		if t.spellID != "" {
+			md.queuePlayerAction(CastSpellAction{CasterID: g.PlayerID, SpellID: t.spellID, Target: t.cursor})
+		} else {
+			md.queuePlayerAction(FireAction{ShooterID: g.PlayerID, Target: t.cursor})
+		}
+		return false, eff, nil
+
+	case ActionCloseScreen:
+		md.mode = modeNormal
+		return true, eff, nil
+
+	default:
+		return true, eff, actionErrorUnknown
+	}
+}
+
+// drawTargeting highlights the projectile path and the cursor
+func (md *Model) drawTargeting(g *Game) {
+	t := md.targeting
+	path, _ := g.projectilePath(g.GetPlayerPosition(), t.cursor, t.maxRange)
+
+	highlight := func(p gruid.Point, bg gruid.Color) {
+		screenX, screenY, visible := md.camera.WorldToScreen(p.X, p.Y)
+		if !visible {
+			return
+		}
+		sp := gruid.Point{X: screenX, Y: screenY}
+		cell := md.grid.At(sp)
+		if cell.Rune == ' ' {
+			cell.Rune = '*'
+		}
+		cell.Style.Bg = bg
+		md.grid.Set(sp, cell)
+	}
+
+	for _, p := range path {
+		highlight(p, ui.ColorTargetPath)
+	}
+	highlight(t.cursor, ui.ColorTargetCursor)
+
+	// Describe what is under the cursor on the bottom line of the map
+	if desc := g.describeCreatureAt(t.cursor); desc != "" {
+		md.drawPanelText(desc, config.MapViewportX, config.MapViewportY+config.MapViewportHeight-1, ui.ColorUIText)
+	}
+}
+
+// describeCreatureAt names the visible creature at p and how fast it is
+// compared with the player, or returns "" if there is none
+func (g *Game) describeCreatureAt(p gruid.Point) string {
+	fov := g.ecs.GetFOVSafe(g.PlayerID)
+	if fov == nil || !fov.IsVisible(p, g.dungeon.Width) {
+		return ""
+	}
+	for _, id := range g.spatialGrid.GetEntitiesAt(p) {
+		if id == g.PlayerID || !g.ecs.HasComponent(id, components.CTurnActor) {
+			continue
+		}
+		return fmt.Sprintf("%s: moves %s", g.ecs.GetNameSafe(id), g.relativeSpeed(id))
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"codeberg.org/anaseto/gruid"
+	"codeberg.org/anaseto/gruid/paths"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+	turn "github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/turn_queue"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui"
+)
+
+// World timers are events scheduled in the turn queue alongside entity
+// turns. Their arguments are stored as JSON so pending timers are saved
+// with the queue and fire the same way after loading.
+const (
+	TimerExplosion      = "explosion"
+	TimerReinforcements = "reinforcements"
+	TimerDespawn        = "despawn"
+)
+
+// ExplosionTimer damages every creature within Radius of Pos
+type ExplosionTimer struct {
+	Pos    gruid.Point `json:"pos"`
+	Radius int         `json:"radius"`
+	Damage int         `json:"damage"`
+}
+
+// ReinforcementsTimer spawns Count monsters of a template around Pos
+type ReinforcementsTimer struct {
+	Pos     gruid.Point `json:"pos"`
+	Monster string      `json:"monster"` // Monster template ID
+	Count   int         `json:"count"`
+}
+
+// DespawnTimer removes an entity, such as a gas cloud that dissipates
+type DespawnTimer struct {
+	EntityID ecs.EntityID `json:"entity_id"`
+}
+
+// timerHandler runs a fired timer with its encoded arguments
+type timerHandler func(g *Game, data json.RawMessage) error
+
+// timerHandlers maps timer kinds to what happens when they fire
+var timerHandlers = map[string]timerHandler{
+	TimerExplosion:      decodeTimer((*Game).explode),
+	TimerReinforcements: decodeTimer((*Game).sendReinforcements),
+	TimerDespawn:        decodeTimer((*Game).despawnTimer),
+}
+
+// decodeTimer adapts a handler taking typed arguments to a timerHandler
+func decodeTimer[T any](fn func(g *Game, args T)) timerHandler {
+	return func(g *Game, data json.RawMessage) error {
+		var args T
+		if err := json.Unmarshal(data, &args); err != nil {
+			return err
+		}
+		fn(g, args)
+		return nil
+	}
+}
+
+// ScheduleTimer schedules a world timer of the given kind to fire delay time
+// units from now. args is encoded as the timer's arguments.
+func (g *Game) ScheduleTimer(delay uint64, kind string, args any) (turn.TimerID, error) {
+	if _, ok := timerHandlers[kind]; !ok {
+		return 0, fmt.Errorf("unknown timer kind %q", kind)
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode %s timer: %w", kind, err)
+	}
+	return g.turnQueue.ScheduleTimer(g.turnQueue.CurrentTime+delay, kind, data), nil
+}
+
+// fireTimer runs the handler of a timer popped from the turn queue
+func (g *Game) fireTimer(timer *turn.Timer) {
+	handler, ok := timerHandlers[timer.Kind]
+	if !ok {
+		slog.Error("Dropping timer of unknown kind", "timerId", timer.ID, "kind", timer.Kind)
+		return
+	}
+	if err := handler(g, timer.Data); err != nil {
+		slog.Error("Timer failed", "timerId", timer.ID, "kind", timer.Kind, "error", err)
+	}
+}
+
+// explode damages every living creature within the explosion's radius
+func (g *Game) explode(args ExplosionTimer) {
+	if fov := g.ecs.GetFOVSafe(g.PlayerID); fov != nil && fov.IsVisible(args.Pos, g.dungeon.Width) {
+		g.log.AddMessagef(ui.ColorStatusBad, "Something explodes!")
+	}
+
+	for _, id := range g.ecs.GetEntitiesWithComponents(components.CHealth, components.CPosition) {
+		if paths.DistanceChebyshev(args.Pos, g.ecs.GetPositionSafe(id)) > args.Radius {
+			continue
+		}
+		health := g.ecs.GetHealthSafe(id)
+		if health.IsDead() {
+			continue
+		}
+		health.CurrentHP -= args.Damage
+		g.ecs.AddComponent(id, components.CHealth, health)
+
+		name := g.ecs.GetNameSafe(id)
+		if id == g.PlayerID {
+			g.AddDamageTaken(args.Damage)
+		}
+		if g.playerCanSee(id) {
+			g.log.AddMessagef(ui.ColorStatusBad, "The blast hits %s for %d damage.", name, args.Damage)
+		}
+		if health.IsDead() {
+			g.handleEntityDeath(id, name, 0)
+		}
+	}
+}
+
+// sendReinforcements spawns monsters on free cells around the arrival point
+func (g *Game) sendReinforcements(args ReinforcementsTimer) {
+	tmpl, ok := g.monsters.Template(args.Monster)
+	if !ok {
+		slog.Error("Unknown reinforcement monster", "monster", args.Monster)
+		return
+	}
+	for range args.Count {
+		pos, ok := g.freeCellNear(args.Pos)
+		if !ok {
+			return
+		}
+		g.SpawnMonster(tmpl, pos)
+	}
+}
+
+// despawnTimer removes the entity if it is still around
+func (g *Game) despawnTimer(args DespawnTimer) {
+	if g.ecs.EntityExists(args.EntityID) {
+		g.commands.Despawn(args.EntityID)
+	}
+}
@@ -0,0 +1,130 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+	turn "github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/turn_queue"
+)
+
+func TestTimers_FireInTurnOrder(t *testing.T) {
+	g := createAfflictedTestGame(20)
+	md := &Model{game: g}
+	blast := ExplosionTimer{Pos: gruid.Point{X: 5, Y: 5}, Radius: 1, Damage: 3}
+
+	// Due at the same time as the player's turn: fires first
+	if _, err := g.ScheduleTimer(0, TimerExplosion, blast); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.ScheduleTimer(150, TimerExplosion, blast); err != nil {
+		t.Fatal(err)
+	}
+
+	wantHP := []int{17, 17, 14}
+	for turnNum, want := range wantHP {
+		if turnNum > 0 {
+			actor := g.ecs.GetTurnActorSafe(g.PlayerID)
+			actor.AddAction(WaitAction{EntityID: g.PlayerID})
+		}
+		md.processTurnQueue()
+		if !g.waitingForInput {
+			t.Fatalf("Turn %d: expected the player's turn", turnNum)
+		}
+		if hp := g.ecs.GetHealthSafe(g.PlayerID).CurrentHP; hp != want {
+			t.Errorf("Turn %d: expected %d HP, got %d", turnNum, want, hp)
+		}
+	}
+	if g.turnQueue.Len() != 1 {
+		t.Errorf("Expected only the player left in the queue, got %d entries", g.turnQueue.Len())
+	}
+}
+
+func TestTimers_DelayCountsFromTheCurrentTurn(t *testing.T) {
+	g := createAfflictedTestGame(20)
+	md := &Model{game: g}
+	g.turnQueue.Add(g.PlayerID, 50)
+
+	// A slow actor goes first and comes back long after the player
+	slug := g.ecs.AddEntity()
+	actor := components.NewTurnActor(300)
+	actor.AddAction(WaitAction{EntityID: slug})
+	g.ecs.AddComponents(slug, components.Name{Name: "Slug"}, components.NewHealth(3), actor)
+	g.turnQueue.Add(slug, 0)
+
+	md.processTurnQueue()
+	if !g.waitingForInput || g.turnQueue.CurrentTime != 50 {
+		t.Fatalf("Expected the player's turn at time 50, got %d", g.turnQueue.CurrentTime)
+	}
+
+	// Due at 60, before the player's next turn at 150
+	if _, err := g.ScheduleTimer(10, TimerExplosion, ExplosionTimer{Pos: gruid.Point{X: 5, Y: 5}, Damage: 3}); err != nil {
+		t.Fatal(err)
+	}
+	player := g.ecs.GetTurnActorSafe(g.PlayerID)
+	player.AddAction(WaitAction{EntityID: g.PlayerID})
+	md.processTurnQueue()
+
+	if g.turnQueue.CurrentTime != 150 {
+		t.Errorf("Expected the player's next turn at time 150, got %d", g.turnQueue.CurrentTime)
+	}
+	if hp := g.ecs.GetHealthSafe(g.PlayerID).CurrentHP; hp != 17 {
+		t.Errorf("Expected the timer to fire before the player's next turn, got %d HP", hp)
+	}
+}
+
+func TestTimers_SurviveSnapshotAndCancel(t *testing.T) {
+	g := createAfflictedTestGame(20)
+	gas := g.ecs.AddEntity()
+	first, _ := g.ScheduleTimer(300, TimerDespawn, DespawnTimer{EntityID: gas})
+	second, _ := g.ScheduleTimer(200, TimerDespawn, DespawnTimer{EntityID: gas})
+
+	g.turnQueue.RestoreFromSnapshot(g.turnQueue.Snapshot())
+	if !g.turnQueue.CancelTimer(second) {
+		t.Fatal("Expected the restored timer to be cancellable by its ID")
+	}
+	if g.turnQueue.CancelTimer(second) {
+		t.Error("Expected a cancelled timer to be gone")
+	}
+
+	// IDs of timers dropped from the queue are not handed out again
+	g.turnQueue.RestoreFromSnapshot(nil)
+	if third, _ := g.ScheduleTimer(10, TimerDespawn, DespawnTimer{}); third == first || third == second {
+		t.Errorf("Expected a fresh timer ID, got %d again", third)
+	}
+	if _, err := g.ScheduleTimer(10, "meteor", nil); err == nil {
+		t.Error("Expected an unknown timer kind to be rejected")
+	}
+}
+
+func TestTimers_RoundTripThroughSave(t *testing.T) {
+	g := createAfflictedTestGame(20)
+	g.ScheduleTimer(50, TimerReinforcements, ReinforcementsTimer{Pos: gruid.Point{X: 2, Y: 2}, Monster: "rat", Count: 2})
+
+	data, err := json.Marshal(saveTurnEntries(g.turnQueue.Snapshot()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved []SavedTurnQueueEntry
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewGame()
+	loaded.turnQueue.RestoreFromSnapshot(loadTurnEntries(saved))
+
+	var timers []turn.TurnEntry
+	for _, entry := range loaded.turnQueue.Snapshot() {
+		if entry.IsTimer() {
+			timers = append(timers, entry)
+		}
+	}
+	if len(timers) != 1 || timers[0].Time != 50 || timers[0].Timer.Kind != TimerReinforcements {
+		t.Fatalf("Expected the reinforcements timer back at time 50, got %+v", timers)
+	}
+	var args ReinforcementsTimer
+	if err := json.Unmarshal(timers[0].Timer.Data, &args); err != nil || args.Monster != "rat" || args.Count != 2 {
+		t.Errorf("Expected the timer's arguments back, got %+v (%v)", args, err)
+	}
+}
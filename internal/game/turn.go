@@ -2,40 +2,82 @@ package game
 
 import (
 	"log/slog"
+	"time"
 
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+	turn "github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/turn_queue"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ui"
 )
 
-// processTurnQueue processes turns for actors until it's the player's turn
-// or the queue is exhausted for the current time step.
+// defaultTurnBudget is how long the turn loop runs before yielding to the
+// UI, so a crowded level still redraws every frame or so
+const defaultTurnBudget = 8 * time.Millisecond
+
+// processTurnQueue runs the action phase: actors take their turns until
+// it's the player's turn, the queue is exhausted or the turn budget is spent.
 func (md *Model) processTurnQueue() {
-	g := md.game
-	slog.Debug("========= processTurnQueue started =========")
+	md.game.systems.Run(md.game, PhaseAction)
+}
 
-	// Periodically clean up the queue
+// cleanupTurnQueue periodically drops dead and invalid actors from the queue.
+func (g *Game) cleanupTurnQueue() {
 	metrics := g.turnQueue.CleanupDeadEntities(g.ecs)
 	if metrics.EntitiesRemoved > 10 {
 		slog.Info("Turn queue cleanup", "removed", metrics.EntitiesRemoved, "time", metrics.ProcessingTime)
 	}
+}
+
+// runTurnQueue processes turns for actors and fires due world timers until
+// it's the player's turn or the queue is exhausted. The post-action systems
+// run after every executed action and timer. Once the turn budget is spent
+// it yields with turnsPending set, so the UI can draw before it resumes.
+func (g *Game) runTurnQueue() {
+	slog.Debug("========= processTurnQueue started =========")
 
 	g.turnQueue.PrintQueue()
+	g.turnsPending = false
+	deadline := time.Now().Add(g.turnBudget)
+
+	// Actors put back at the same time without acting, since the last time
+	// anything happened
+	idle := make(map[ecs.EntityID]uint64)
 
 	// Process turns until we need player input or run out of actors
-	for i := range 100 { // Limit iterations to prevent infinite loops
-		slog.Debug("Turn queue iteration", "iteration", i)
+	for processed := 0; ; processed++ {
+		// Sync point: deaths and despawns from the previous turn's upkeep
+		// must land before the next actor is picked
+		g.commands.Apply()
 
-		if g.turnQueue.IsEmpty() {
-			slog.Debug("Turn queue is empty.")
-			slog.Debug("========= processTurnQueue ended (queue empty) =========")
+		if g.IsGameOver() {
+			slog.Debug("========= processTurnQueue ended (game over) =========")
+			return
+		}
+
+		if processed > 0 && time.Now().After(deadline) {
+			g.turnsPending = true
+			slog.Debug("========= processTurnQueue yielded (budget spent) =========", "processed", processed, "time", g.turnQueue.CurrentTime)
 			return
 		}
 
 		turnEntry, ok := g.turnQueue.Next()
 		if !ok {
-			slog.Debug("Error: Queue does not have any more actors.")
-			slog.Debug("========= processTurnQueue ended (Next error) =========")
+			slog.Debug("Turn queue is empty.")
+			slog.Debug("========= processTurnQueue ended (queue empty) =========")
 			return
 		}
+		g.turnQueue.CurrentTime = turnEntry.Time
+
+		// Timers fire in turn order, and the world reacts to them like
+		// to an action
+		if turnEntry.IsTimer() {
+			slog.Debug("Firing timer", "timerId", turnEntry.Timer.ID, "kind", turnEntry.Timer.Kind, "time", turnEntry.Time)
+			g.fireTimer(turnEntry.Timer)
+			g.commands.Apply()
+			g.systems.Run(g, PhasePostAction)
+			clear(idle)
+			continue
+		}
 
 		slog.Debug("Processing actor", "entityId", turnEntry.EntityID, "time", turnEntry.Time)
 		actor := g.ecs.GetTurnActorSafe(turnEntry.EntityID)
@@ -50,19 +92,39 @@ func (md *Model) processTurnQueue() {
 		}
 
 		isPlayer := turnEntry.EntityID == g.PlayerID
+
+		// Paralyzed actors lose their turn, but their effects keep ticking
+		if effects := g.ecs.GetStatusEffectsSafe(turnEntry.EntityID); effects.IsParalyzed() {
+			slog.Debug("Entity is paralyzed, skipping turn", "entityId", turnEntry.EntityID)
+			if isPlayer {
+				g.log.AddMessagef(ui.ColorStatusBad, "You are paralyzed and cannot act!")
+			}
+			g.turnQueue.Add(turnEntry.EntityID, turnEntry.Time+g.actionDelay(turnEntry.EntityID, 100))
+			g.actorUpkeep(turnEntry.EntityID)
+			clear(idle)
+			continue
+		}
+
 		action := actor.NextAction()
 
 		if isPlayer && action == nil {
 			g.waitingForInput = true
 			slog.Debug("It's the player's turn, waiting for input.")
-			g.turnQueue.Add(turnEntry.EntityID, turnEntry.Time)
+			g.turnQueue.Requeue(turnEntry)
 			slog.Debug("========= processTurnQueue ended (player's turn) =========")
 			return
 		}
 
+		// AI plans once a round, so actors faster than the player run out
+		// of actions before the round is over and plan again here
+		if action == nil {
+			g.planTurn(turnEntry.EntityID)
+			action = actor.NextAction()
+		}
+
 		if action == nil {
 			slog.Debug("Entity has no actions, rescheduling turn", "entityId", turnEntry.EntityID, "time", turnEntry.Time)
-			g.turnQueue.Add(turnEntry.EntityID, turnEntry.Time)
+			g.requeueIdle(turnEntry, idle)
 			continue
 		}
 
@@ -72,24 +134,80 @@ func (md *Model) processTurnQueue() {
 
 			// On failure, reschedule with appropriate delay
 			if isPlayer {
-				g.turnQueue.Add(turnEntry.EntityID, turnEntry.Time)
+				g.turnQueue.Requeue(turnEntry)
 			} else {
-				g.turnQueue.Add(turnEntry.EntityID, turnEntry.Time+100)
+				g.turnQueue.Add(turnEntry.EntityID, turnEntry.Time+g.actionDelay(turnEntry.EntityID, 100))
 			}
 			continue
 		}
 
-		g.FOVSystem()
+		// Sync point: apply the action's structural changes before anything
+		// else looks at the world
+		g.commands.Apply()
+		g.systems.Run(g, PhasePostAction)
 
 		slog.Debug("Action executed", "entityId", turnEntry.EntityID, "cost", cost)
 
-		// Update the game time and schedule next turn
-		g.turnQueue.CurrentTime = turnEntry.Time + uint64(cost)
-		g.turnQueue.Add(turnEntry.EntityID, g.turnQueue.CurrentTime)
+		// An action taking no time, like a bump queueing an attack, leaves
+		// the actor due now without anything having moved forward
+		delay := g.actionDelay(turnEntry.EntityID, cost)
+		if delay == 0 {
+			if isPlayer {
+				g.turnQueue.Requeue(turnEntry)
+			} else {
+				g.requeueIdle(turnEntry, idle)
+			}
+			continue
+		}
+
+		// Schedule the next turn, sooner for faster actors
+		g.turnQueue.Add(turnEntry.EntityID, turnEntry.Time+delay)
+		g.actorUpkeep(turnEntry.EntityID)
+
+		clear(idle)
 
 		// Increment turn count for statistics
 		g.IncrementTurnCount()
 	}
+}
+
+// requeueIdle puts back an actor that has nothing to do even after planning,
+// or only actions taking no time, unchanged, for the world to catch up. An
+// actor found idle again at the same time with nothing having happened in
+// between would be put back forever: the stall is reported and the actor
+// loses its turn instead.
+func (g *Game) requeueIdle(entry turn.TurnEntry, idle map[ecs.EntityID]uint64) {
+	if at, ok := idle[entry.EntityID]; ok && at == entry.Time {
+		slog.Warn("Turn loop made no progress, skipping the actor's turn", "entityId", entry.EntityID, "name", g.ecs.GetNameSafe(entry.EntityID), "time", entry.Time)
+		g.stalledTurns++
+		g.turnQueue.Add(entry.EntityID, entry.Time+g.actionDelay(entry.EntityID, 100))
+		delete(idle, entry.EntityID)
+		return
+	}
+	idle[entry.EntityID] = entry.Time
+	g.turnQueue.Requeue(entry)
+}
+
+// actorUpkeep runs the per-turn bookkeeping of an actor after it acted:
+// mana regenerates, status effects tick down and summons fade over time.
+func (g *Game) actorUpkeep(id ecs.EntityID) {
+	if mana, ok := g.ecs.GetMana(id); ok && mana.CurrentMP < mana.MaxMP {
+		mana.RegenerateMana()
+		g.ecs.AddComponent(id, components.CMana, mana)
+	}
 
-	slog.Debug("========= processTurnQueue ended (iteration limit reached) =========")
+	if !g.tickStatusEffects(id) {
+		return // Died from its effects
+	}
+
+	if summoned, ok := g.ecs.GetSummoned(id); ok {
+		summoned.TurnsLeft--
+		if summoned.TurnsLeft > 0 {
+			g.ecs.AddComponent(id, components.CSummoned, summoned)
+			return
+		}
+
+		g.log.AddMessagef(ui.ColorStatusNeutral, "%s fades away.", g.ecs.GetNameSafe(id))
+		g.commands.Despawn(id)
+	}
 }
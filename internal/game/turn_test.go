@@ -0,0 +1,145 @@
+package game
+
+import (
+	"testing"
+
+	"codeberg.org/anaseto/gruid"
+
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
+)
+
+// addTestActor adds an actor due at time with the given actions queued
+func addTestActor(g *Game, time uint64, actions ...func(ecs.EntityID) GameAction) ecs.EntityID {
+	id := g.ecs.AddEntity()
+	actor := components.NewTurnActor(100)
+	for _, action := range actions {
+		actor.AddAction(action(id))
+	}
+	g.ecs.AddComponents(id, components.Name{Name: "Rat"}, components.NewHealth(3), actor)
+	g.turnQueue.Add(id, time)
+	return id
+}
+
+func queuedWait(id ecs.EntityID) GameAction { return WaitAction{EntityID: id} }
+
+func TestRunRound_YieldsAndResumes(t *testing.T) {
+	g := createAfflictedTestGame(10)
+	md := &Model{game: g}
+	g.turnQueue.Add(g.PlayerID, 50)
+	for range 3 {
+		addTestActor(g, 0, queuedWait)
+	}
+	g.turnBudget = 0 // One entry per slice
+
+	g.runRound()
+	slices := 1
+	for effect := md.resumeEffect(); effect != nil && slices < 10; slices++ {
+		effect = md.processTurnQueueWithEffect()
+	}
+
+	if !g.waitingForInput || g.turnsPending {
+		t.Fatal("Expected the round to finish on the player's turn")
+	}
+	if slices != 4 {
+		t.Errorf("Expected 3 rats and the player in 4 slices, got %d", slices)
+	}
+}
+
+// freeAction succeeds without taking any time and plans itself again, like
+// a bump that never gets anywhere
+type freeAction struct{ EntityID ecs.EntityID }
+
+func (a freeAction) Execute(g *Game) (uint, error) {
+	actor := g.ecs.GetTurnActorSafe(a.EntityID)
+	actor.AddAction(a)
+	return 0, nil
+}
+
+func TestRunTurnQueue_ReportsStalledActors(t *testing.T) {
+	tests := []struct {
+		name    string
+		actions []func(ecs.EntityID) GameAction
+	}{
+		{"no action", nil}, // Nothing planned, and nothing will plan for it
+		{"free action", []func(ecs.EntityID) GameAction{func(id ecs.EntityID) GameAction { return freeAction{EntityID: id} }}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := createAfflictedTestGame(10)
+			md := &Model{game: g}
+			g.turnQueue.Add(g.PlayerID, 50)
+			idler := addTestActor(g, 0, tt.actions...)
+
+			md.processTurnQueue()
+
+			if !g.waitingForInput {
+				t.Fatal("Expected the stall broken and the player's turn reached")
+			}
+			if g.stalledTurns != 1 {
+				t.Errorf("Expected one stalled turn reported, got %d", g.stalledTurns)
+			}
+			for _, entry := range g.turnQueue.Snapshot() {
+				if entry.EntityID == idler && entry.Time != 100 {
+					t.Errorf("Expected the idle actor to lose its turn until 100, got %d", entry.Time)
+				}
+			}
+		})
+	}
+}
+
+func TestRunTurnQueue_SummonWalledInByChestFades(t *testing.T) {
+	g := createAfflictedTestGame(10)
+	md := &Model{game: g}
+	g.SpawnChest(gruid.Point{X: 2, Y: 2}, nil)
+	summon := g.SpawnBlueprint("summon", gruid.Point{X: 1, Y: 1},
+		components.Summoned{TurnsLeft: 3},
+		components.Name{Name: "Wolf"},
+		components.NewHealth(5),
+		components.NewCombat(),
+	)
+	g.turnQueue.Add(summon, 0)
+
+	actor := g.ecs.GetTurnActorSafe(g.PlayerID)
+	for range 5 {
+		actor.AddAction(WaitAction{EntityID: g.PlayerID})
+	}
+	md.processTurnQueue()
+	g.commands.Apply()
+
+	// Walking towards the player only ever bumps the chest in the corner
+	if !g.waitingForInput || g.turnQueue.CurrentTime != 500 {
+		t.Fatalf("Expected the player's turn at time 500, got %d", g.turnQueue.CurrentTime)
+	}
+	if g.ecs.EntityExists(summon) {
+		t.Error("Expected the stuck summon to wait out its duration and fade")
+	}
+}
+
+func TestRunTurnQueue_FastMonsterPlansBetweenRounds(t *testing.T) {
+	g := createAfflictedTestGame(1000)
+	g.ecs.AddComponents(g.PlayerID, components.NewFOVComponent(8, g.dungeon.Width, g.dungeon.Height), components.BlocksMovement{})
+	md := &Model{game: g}
+	rat, _ := g.monsters.Template("rat")
+	ratID := g.SpawnMonster(rat, gruid.Point{X: 7, Y: 5})
+	g.ecs.AddComponent(ratID, components.CTurnActor, components.NewTurnActor(25)) // Four times the player's speed
+
+	g.runRound()
+	for range 5 {
+		player := g.ecs.GetTurnActorSafe(g.PlayerID)
+		player.AddAction(WaitAction{EntityID: g.PlayerID})
+		g.runRound()
+	}
+
+	if g.stalledTurns != 0 {
+		t.Errorf("Expected the rat to plan its extra turns, got %d stalled", g.stalledTurns)
+	}
+	// The player waited 5 times, the rat acted four times as often from
+	// time 100
+	if got := g.stats.TurnCount; got < 5+16 {
+		t.Errorf("Expected the rat to use its extra turns, got %d actions in all", got)
+	}
+	if !g.waitingForInput || md.resumeEffect() != nil {
+		t.Error("Expected the rounds to end on the player's turn")
+	}
+}
@@ -8,7 +8,10 @@ import (
 
 // FindRepoRoot finds the root of the git repository by checking for a .git directory
 func FindRepoRoot(startPath string) (string, error) {
-	currentPath := startPath
+	currentPath, err := filepath.Abs(startPath)
+	if err != nil {
+		return "", err
+	}
 
 	for {
 		gitPath := filepath.Join(currentPath, ".git")
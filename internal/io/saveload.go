@@ -3,6 +3,7 @@ package io
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
 )
 
@@ -20,6 +21,21 @@ func LoadData[T any](path string) (T, error) {
 	return config, nil
 }
 
+// LoadDataFS reads and parses a JSON file from fsys, such as embedded data
+func LoadDataFS[T any](fsys fs.FS, name string) (T, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return *new(T), fmt.Errorf("failed to read data file: %w", err)
+	}
+
+	var value T
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, fmt.Errorf("failed to parse data file: %w", err)
+	}
+
+	return value, nil
+}
+
 func SaveData[T any](path string, saveData T) error {
 	data, err := json.MarshalIndent(saveData, "", "  ")
 	if err != nil {
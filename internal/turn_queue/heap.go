@@ -1,45 +1,136 @@
 package turn
 
-import "github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+import (
+	"encoding/json"
 
-// TurnEntry represents an item in the turn queue with time (priority) and entity ID
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+)
+
+// TimerID identifies a scheduled timer
+type TimerID uint64
+
+// Timer is a world event scheduled in the turn queue that is not an
+// entity's turn, such as a fuse burning down. Kind selects what happens
+// when it fires and Data holds its arguments. Timers are shared between
+// snapshots and must not be modified once scheduled.
+type Timer struct {
+	ID   TimerID
+	Kind string
+	Data json.RawMessage
+}
+
+// TurnEntry represents an item in the turn queue with time (priority) and
+// either an entity ID or a timer
 type TurnEntry struct {
 	Time     uint64
 	EntityID ecs.EntityID
+	Timer    *Timer // Set for timers, whose EntityID is unused
+	seq      uint64 // Order the entry was queued in, breaks ties in time
 }
 
-// turnHeap implements a min-heap where the smallest time values are at the top
-type turnHeap []TurnEntry
+// IsTimer reports whether the entry is a timer rather than an entity's turn
+func (e TurnEntry) IsTimer() bool { return e.Timer != nil }
 
-func (h turnHeap) Len() int { return len(h) }
+// before orders entries by time. At equal times timers fire before entities
+// act, so an actor never sees the world as it was before a due event. Other
+// ties go to the entry queued first, then to the lowest entity ID.
+func (e TurnEntry) before(other TurnEntry) bool {
+	if e.Time != other.Time {
+		return e.Time < other.Time
+	}
+	if e.IsTimer() != other.IsTimer() {
+		return e.IsTimer()
+	}
+	if e.seq != other.seq {
+		return e.seq < other.seq
+	}
+	return e.EntityID < other.EntityID
+}
+
+// compareEntries orders entries like before, for sorting
+func compareEntries(a, b TurnEntry) int {
+	switch {
+	case a.before(b):
+		return -1
+	case b.before(a):
+		return 1
+	}
+	return 0
+}
+
+// turnHeap implements a min-heap where the smallest time values are at the
+// top. It tracks where each entity and timer sits so they can be found,
+// moved and removed without scanning, which makes an entity appear at most
+// once.
+type turnHeap struct {
+	entries  []TurnEntry
+	entities map[ecs.EntityID]int // Index of each entity's entry
+	timers   map[TimerID]int      // Index of each timer's entry
+}
 
-func (h turnHeap) Less(i, j int) bool {
-	if h[i].Time != h[j].Time {
-		return h[i].Time < h[j].Time
+func newTurnHeap() *turnHeap {
+	return &turnHeap{
+		entities: make(map[ecs.EntityID]int),
+		timers:   make(map[TimerID]int),
 	}
-	return h[i].EntityID < h[j].EntityID
 }
 
-func (h turnHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *turnHeap) Len() int { return len(h.entries) }
+
+func (h *turnHeap) Less(i, j int) bool { return h.entries[i].before(h.entries[j]) }
+
+func (h *turnHeap) Swap(i, j int) {
+	h.entries[i], h.entries[j] = h.entries[j], h.entries[i]
+	h.track(i)
+	h.track(j)
+}
 
 func (h *turnHeap) Push(x any) {
-	*h = append(*h, x.(TurnEntry))
+	h.entries = append(h.entries, x.(TurnEntry))
+	h.track(len(h.entries) - 1)
 }
 
 func (h *turnHeap) Pop() any {
-	old := *h
-	n := len(old)
-	item := old[n-1]
-	*h = old[0 : n-1]
+	n := len(h.entries)
+	item := h.entries[n-1]
+	h.entries = h.entries[0 : n-1]
+	if item.IsTimer() {
+		delete(h.timers, item.Timer.ID)
+	} else {
+		delete(h.entities, item.EntityID)
+	}
 	return item
 }
 
+// track records the index of the entry at i
+func (h *turnHeap) track(i int) {
+	if entry := h.entries[i]; entry.IsTimer() {
+		h.timers[entry.Timer.ID] = i
+	} else {
+		h.entities[entry.EntityID] = i
+	}
+}
+
 func (h *turnHeap) FindIndex(entityID ecs.EntityID) int {
-	for i, entry := range *h {
-		if entry.EntityID == entityID {
-			return i
-		}
+	if i, ok := h.entities[entityID]; ok {
+		return i
 	}
+	return -1
+}
 
+// findTimer returns the index of the timer with the given ID, or -1
+func (h *turnHeap) findTimer(id TimerID) int {
+	if i, ok := h.timers[id]; ok {
+		return i
+	}
 	return -1
 }
+
+// indexOf returns the index of the entry for the same entity or timer as
+// entry, or -1
+func (h *turnHeap) indexOf(entry TurnEntry) int {
+	if entry.IsTimer() {
+		return h.findTimer(entry.Timer.ID)
+	}
+	return h.FindIndex(entry.EntityID)
+}
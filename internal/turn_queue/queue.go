@@ -2,39 +2,48 @@ package turn
 
 import (
 	"container/heap"
+	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
 )
 
-// TurnQueue manages entity turns based on time using a min-heap.
+// TurnQueue manages entity turns and world timers based on time using a
+// min-heap. Each entity has at most one pending turn.
 type TurnQueue struct {
 	queue                  *turnHeap
-	CurrentTime            uint64
+	CurrentTime            uint64 // Time of the entry being processed
+	nextTimerID            TimerID
+	nextSeq                uint64
 	OperationsSinceCleanup uint32
 	TotalCleanups          uint64
 	TotalEntitiesRemoved   uint64
 }
 
 func NewTurnQueue() *TurnQueue {
-	h := &turnHeap{}
+	h := newTurnHeap()
 	heap.Init(h)
 
 	return &TurnQueue{
 		CurrentTime:            0,
 		queue:                  h,
+		nextTimerID:            1,
+		nextSeq:                1,
 		OperationsSinceCleanup: 0,
 		TotalCleanups:          0,
 		TotalEntitiesRemoved:   0,
 	}
 }
 
+// Add schedules the entity's next turn at time. An entity already in the
+// queue is rescheduled, and goes after the entries already due at time.
 func (tq *TurnQueue) Add(entityID ecs.EntityID, time uint64) {
-	entry := TurnEntry{Time: time, EntityID: entityID}
-	heap.Push(tq.queue, entry)
+	tq.Push(TurnEntry{Time: time, EntityID: entityID})
 	slog.Debug("Added entity to turn queue", "entityId", entityID, "time", time)
 }
 
@@ -48,7 +57,71 @@ func (tq *TurnQueue) Remove(entityID ecs.EntityID) {
 	heap.Remove(tq.queue, index)
 }
 
-// Next removes and returns the next entity (the one with the smallest time)
+// ScheduleTimer adds a timer of the given kind firing at time and returns
+// its ID, which stays the same across snapshots and saves.
+func (tq *TurnQueue) ScheduleTimer(time uint64, kind string, data json.RawMessage) TimerID {
+	timer := &Timer{ID: tq.nextTimerID, Kind: kind, Data: data}
+	tq.nextTimerID++
+	tq.Push(TurnEntry{Time: time, Timer: timer})
+	slog.Debug("Scheduled timer", "timerId", timer.ID, "kind", kind, "time", time)
+	return timer.ID
+}
+
+// CancelTimer removes a pending timer. It returns false if the timer already
+// fired or was never scheduled.
+func (tq *TurnQueue) CancelTimer(id TimerID) bool {
+	index := tq.queue.findTimer(id)
+	if index == -1 {
+		return false
+	}
+
+	heap.Remove(tq.queue, index)
+	return true
+}
+
+// Push adds an entry, whether an entity's turn or a timer, after the
+// entries already due at the same time. It replaces any pending entry of
+// the same entity or timer. Used to move entries taken from a snapshot
+// back into the queue.
+func (tq *TurnQueue) Push(entry TurnEntry) {
+	entry.seq = tq.nextSeq
+	tq.nextSeq++
+	tq.ReserveTimerIDs([]TurnEntry{entry})
+	tq.insert(entry)
+}
+
+// Requeue puts an entry returned by Next back unchanged, ahead of anything
+// queued for the same time since it was taken out.
+func (tq *TurnQueue) Requeue(entry TurnEntry) {
+	if entry.seq == 0 {
+		tq.Push(entry)
+		return
+	}
+	tq.insert(entry)
+}
+
+// insert adds entry to the heap, or moves the pending entry it replaces
+func (tq *TurnQueue) insert(entry TurnEntry) {
+	if index := tq.queue.indexOf(entry); index != -1 {
+		tq.queue.entries[index] = entry
+		heap.Fix(tq.queue, index)
+		return
+	}
+	heap.Push(tq.queue, entry)
+}
+
+// ReserveTimerIDs keeps ScheduleTimer from handing out the IDs of the timers
+// among entries. Used for timers kept outside the queue, e.g. on another
+// dungeon level, that may be pushed back later.
+func (tq *TurnQueue) ReserveTimerIDs(entries []TurnEntry) {
+	for _, entry := range entries {
+		if entry.IsTimer() && entry.Timer.ID >= tq.nextTimerID {
+			tq.nextTimerID = entry.Timer.ID + 1
+		}
+	}
+}
+
+// Next removes and returns the next entry (the one with the smallest time)
 // from the queue. Returns the entry and true if the queue is not empty,
 // otherwise returns a zero TurnEntry and false.
 func (tq *TurnQueue) Next() (TurnEntry, bool) {
@@ -57,7 +130,7 @@ func (tq *TurnQueue) Next() (TurnEntry, bool) {
 	}
 
 	entry := heap.Pop(tq.queue).(TurnEntry)
-	slog.Debug("Popped entity from turn queue", "entityId", entry.EntityID, "time", entry.Time)
+	slog.Debug("Popped entry from turn queue", entry.logAttrs()...)
 	return entry, true
 }
 
@@ -69,8 +142,8 @@ func (tq *TurnQueue) Peek() (TurnEntry, bool) {
 		return TurnEntry{}, false
 	}
 
-	entry := (*tq.queue)[0]
-	slog.Debug("Peeked entity from turn queue", "entityId", entry.EntityID, "time", entry.Time)
+	entry := tq.queue.entries[0]
+	slog.Debug("Peeked entry from turn queue", entry.logAttrs()...)
 	return entry, true
 }
 
@@ -82,27 +155,35 @@ func (tq *TurnQueue) IsEmpty() bool {
 	return tq.queue.Len() == 0
 }
 
-// Snapshot returns a copy of all entries in the turn queue for saving
+// Snapshot returns a copy of all entries in the turn queue, in the order
+// they will be processed, for saving
 func (tq *TurnQueue) Snapshot() []TurnEntry {
-	entries := make([]TurnEntry, len(*tq.queue))
-	copy(entries, *tq.queue)
+	entries := slices.Clone(tq.queue.entries)
+	slices.SortFunc(entries, compareEntries)
 	return entries
 }
 
-// RestoreFromSnapshot restores the turn queue from a saved snapshot
+// RestoreFromSnapshot restores the turn queue from a saved snapshot. Entries
+// due at the same time are processed in the order of the snapshot. Timer
+// IDs handed out before are not reused, even for timers the snapshot lacks.
 func (tq *TurnQueue) RestoreFromSnapshot(entries []TurnEntry) {
 	// Clear the current queue
-	tq.queue = &turnHeap{}
+	tq.queue = newTurnHeap()
 	heap.Init(tq.queue)
 
 	// Add all entries back to the queue
 	for _, entry := range entries {
-		heap.Push(tq.queue, entry)
+		tq.Push(entry)
 	}
 }
 
 // PrintQueue prints the current state of the turn queue for debugging purposes.
 func (tq *TurnQueue) PrintQueue() {
+	// Sorting a large queue on every round is not free
+	if !slog.Default().Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+
 	if tq.IsEmpty() {
 		slog.Debug("---- Turn Queue: EMPTY ----")
 		return
@@ -113,39 +194,26 @@ func (tq *TurnQueue) PrintQueue() {
 	slog.Debug("Queue Size", "size", tq.Len())
 
 	slog.Debug("Queue (in heap order):")
-	for i, entry := range *tq.queue {
+	for i, entry := range tq.queue.entries {
 		delta := int64(entry.Time) - int64(tq.CurrentTime)
-		slog.Debug("Queue entry", "index", i, "entityId", entry.EntityID, "time", entry.Time, "delta", delta)
+		slog.Debug("Queue entry", append(entry.logAttrs(), "index", i, "delta", delta)...)
 	}
 
 	slog.Debug("Processing order (sorted by time):")
-	sorted := make([]TurnEntry, len(*tq.queue))
-	copy(sorted, *tq.queue)
-
-	tq.sortEntriesByTime(sorted)
-
-	for i, entry := range sorted {
+	for i, entry := range tq.Snapshot() {
 		delta := int64(entry.Time) - int64(tq.CurrentTime)
-		slog.Debug("Processing entry", "position", i+1, "entityId", entry.EntityID, "time", entry.Time, "delta", delta)
+		slog.Debug("Processing entry", append(entry.logAttrs(), "position", i+1, "delta", delta)...)
 	}
 
 	slog.Debug("----------------------------")
 }
 
-// sortEntriesByTime sorts a slice of TurnEntry by time, then by EntityID for stable ordering
-func (tq *TurnQueue) sortEntriesByTime(entries []TurnEntry) {
-	for i := 1; i < len(entries); i++ {
-		for j := i; j > 0; j-- {
-			if entries[j].Time < entries[j-1].Time {
-				entries[j], entries[j-1] = entries[j-1], entries[j]
-			} else if entries[j].Time == entries[j-1].Time &&
-				entries[j].EntityID < entries[j-1].EntityID {
-				entries[j], entries[j-1] = entries[j-1], entries[j]
-			} else {
-				break
-			}
-		}
+// logAttrs returns the slog attributes describing an entry
+func (e TurnEntry) logAttrs() []any {
+	if e.IsTimer() {
+		return []any{"timerId", e.Timer.ID, "kind", e.Timer.Kind, "time", e.Time}
 	}
+	return []any{"entityId", e.EntityID, "time", e.Time}
 }
 
 type CleanupMetrics struct {
@@ -206,7 +274,8 @@ func (tq *TurnQueue) getCleanupThreshold(world *ecs.ECS) uint32 {
 	return uint32(base_threshold)
 }
 
-// CleanupDeadEntities removes invalid or dead entities from the queue
+// CleanupDeadEntities removes invalid or dead entities from the queue.
+// Timers are kept.
 func (tq *TurnQueue) CleanupDeadEntities(world *ecs.ECS) CleanupMetrics {
 	threshold := tq.getCleanupThreshold(world)
 	if tq.OperationsSinceCleanup < threshold {
@@ -219,32 +288,25 @@ func (tq *TurnQueue) CleanupDeadEntities(world *ecs.ECS) CleanupMetrics {
 	queueSizeBefore := tq.Len()
 	startTime := time.Now()
 
-	newQueueSlice := make(turnHeap, 0, queueSizeBefore)
-	removedCount := 0
-
-	originalQueue := tq.queue
-	for originalQueue.Len() > 0 {
-		entry := heap.Pop(originalQueue).(TurnEntry)
-
-		entityValid := tq.isValIDTurnActor(world, entry.EntityID)
-
-		if entityValid {
-			newQueueSlice = append(newQueueSlice, entry)
-		} else {
-			removedCount++
-
-			name := world.GetNameSafe(entry.EntityID)
-			if name == "" {
-				name = "Unknown"
-			}
-
-			slog.Debug("TurnQueue: Removed dead entity from turn queue", "entityName", name)
+	// Collect first, as removals reorder the heap
+	var dead []ecs.EntityID
+	for _, entry := range tq.queue.entries {
+		if !entry.IsTimer() && !tq.isValIDTurnActor(world, entry.EntityID) {
+			dead = append(dead, entry.EntityID)
 		}
 	}
 
-	tq.queue = &newQueueSlice
+	for _, entityID := range dead {
+		heap.Remove(tq.queue, tq.queue.FindIndex(entityID))
 
-	heap.Init(tq.queue)
+		name := world.GetNameSafe(entityID)
+		if name == "" {
+			name = "Unknown"
+		}
+
+		slog.Debug("TurnQueue: Removed dead entity from turn queue", "entityName", name)
+	}
+	removedCount := len(dead)
 
 	tq.OperationsSinceCleanup = 0
 	tq.TotalCleanups++
@@ -0,0 +1,136 @@
+package turn
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+)
+
+// drain pops every entry and returns the entity IDs in processing order,
+// with timers as the negated timer ID
+func drain(tq *TurnQueue) []ecs.EntityID {
+	var order []ecs.EntityID
+	for {
+		entry, ok := tq.Next()
+		if !ok {
+			return order
+		}
+		if entry.IsTimer() {
+			order = append(order, -ecs.EntityID(entry.Timer.ID))
+		} else {
+			order = append(order, entry.EntityID)
+		}
+	}
+}
+
+func TestTurnQueue_TiesGoToFirstQueued(t *testing.T) {
+	tq := NewTurnQueue()
+	tq.Add(7, 100)
+	tq.Add(3, 100)
+	tq.Add(5, 50)
+	timer := tq.ScheduleTimer(100, "bomb", nil)
+	tq.Add(9, 100)
+
+	want := []ecs.EntityID{5, -ecs.EntityID(timer), 7, 3, 9}
+	if got := drain(tq); !slices.Equal(got, want) {
+		t.Errorf("Expected processing order %v, got %v", want, got)
+	}
+}
+
+func TestTurnQueue_RemoveAndReschedule(t *testing.T) {
+	tq := NewTurnQueue()
+	for id := range ecs.EntityID(10) {
+		tq.Add(id+1, uint64(id)*10)
+	}
+
+	tq.Remove(4)
+	tq.Add(2, 500) // Already queued: moved, not duplicated
+	tq.Add(9, 0)
+
+	if tq.Len() != 9 {
+		t.Errorf("Expected 9 entries, got %d", tq.Len())
+	}
+	want := []ecs.EntityID{1, 9, 3, 5, 6, 7, 8, 10, 2}
+	if got := drain(tq); !slices.Equal(got, want) {
+		t.Errorf("Expected processing order %v, got %v", want, got)
+	}
+}
+
+func TestTurnQueue_RequeueKeepsPlace(t *testing.T) {
+	tq := NewTurnQueue()
+	tq.Add(2, 100)
+	tq.Add(1, 100)
+
+	entry, _ := tq.Next()
+	tq.Requeue(entry)
+
+	if next, _ := tq.Peek(); next.EntityID != 2 {
+		t.Errorf("Expected the requeued entity to stay first, got %d", next.EntityID)
+	}
+}
+
+func TestTurnQueue_SnapshotKeepsOrder(t *testing.T) {
+	tq := NewTurnQueue()
+	tq.Add(8, 100)
+	tq.Add(2, 100)
+	tq.ScheduleTimer(100, "gas", nil)
+	tq.Add(4, 30)
+
+	snapshot := tq.Snapshot()
+	restored := NewTurnQueue()
+	restored.RestoreFromSnapshot(snapshot)
+
+	if want, got := drain(tq), drain(restored); !slices.Equal(got, want) {
+		t.Errorf("Expected the restored queue to process %v, got %v", want, got)
+	}
+}
+
+// newBenchQueue returns a queue of n actors with spread out turns
+func newBenchQueue(n int) *TurnQueue {
+	tq := NewTurnQueue()
+	for i := range n {
+		tq.Add(ecs.EntityID(i+1), uint64(i*37%1000))
+	}
+	return tq
+}
+
+func BenchmarkTurnQueue_NextAndAdd(b *testing.B) {
+	for _, n := range []int{1000, 5000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			tq := newBenchQueue(n)
+			b.ResetTimer()
+			for range b.N {
+				entry, _ := tq.Next()
+				tq.Add(entry.EntityID, entry.Time+100)
+			}
+		})
+	}
+}
+
+func BenchmarkTurnQueue_Remove(b *testing.B) {
+	for _, n := range []int{1000, 5000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			tq := newBenchQueue(n)
+			b.ResetTimer()
+			for i := range b.N {
+				id := ecs.EntityID(i%n + 1)
+				tq.Remove(id)
+				tq.Add(id, uint64(i%1000))
+			}
+		})
+	}
+}
+
+func BenchmarkTurnQueue_Reschedule(b *testing.B) {
+	for _, n := range []int{1000, 5000} {
+		b.Run(strconv.Itoa(n), func(b *testing.B) {
+			tq := newBenchQueue(n)
+			b.ResetTimer()
+			for i := range b.N {
+				tq.Add(ecs.EntityID(i%n+1), uint64(i*53%1000))
+			}
+		})
+	}
+}
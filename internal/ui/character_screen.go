@@ -2,10 +2,12 @@ package ui
 
 import (
 	"fmt"
+	"strings"
 
 	"codeberg.org/anaseto/gruid"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/config"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
 )
 
 // CharacterScreen handles the full-screen character information display
@@ -43,12 +45,13 @@ func (cs *CharacterScreen) generateDrawableElements(gameData GameData, contentWi
 	}
 
 	cs.appendBasicInfoElements(&elements, gameData.ECS(), playerID, contentWidth)
+	cs.appendRunInfoElements(&elements, gameData)
 	cs.drawSpacer(&elements)
 
-	cs.appendAttributesElements(&elements, gameData.ECS(), playerID, contentWidth)
+	cs.appendAttributesElements(&elements, gameData, contentWidth)
 	cs.drawSpacer(&elements)
 
-	cs.appendCombatStatsElements(&elements, gameData.ECS(), playerID, contentWidth)
+	cs.appendCombatStatsElements(&elements, gameData, contentWidth)
 	cs.drawSpacer(&elements)
 
 	cs.appendEquipmentElements(&elements, gameData.ECS(), playerID, contentWidth)
@@ -189,62 +192,95 @@ func (cs *CharacterScreen) appendBasicInfoElements(elements *[]DrawableElement,
 	}
 }
 
-// appendAttributesElements appends drawing functions for attributes
-func (cs *CharacterScreen) appendAttributesElements(elements *[]DrawableElement, ecs *ecs.ECS, playerID ecs.EntityID, contentWidth int) {
+// appendRunInfoElements appends drawing functions for the current run (depth and seed)
+func (cs *CharacterScreen) appendRunInfoElements(elements *[]DrawableElement, gameData GameData) {
+	runText := fmt.Sprintf("Depth: %d | Seed: %d", gameData.GetDepth(), gameData.GetSeed())
+	textColor := ColorUIText
+	*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
+		cs.drawText(grid, runText, drawX, drawY, textColor)
+	})
+}
+
+// withBonus formats an effective value followed by its difference from the
+// base value, e.g. "12 (+2)"
+func withBonus(effective, base int, unit string) string {
+	if effective == base {
+		return fmt.Sprintf("%2d%s", effective, unit)
+	}
+	return fmt.Sprintf("%2d%s (%+d)", effective, unit, effective-base)
+}
+
+// appendAttributesElements appends drawing functions for attributes. Values
+// include equipment and status effects, with the difference to the base
+// attribute in parentheses.
+func (cs *CharacterScreen) appendAttributesElements(elements *[]DrawableElement, gameData GameData, contentWidth int) {
+	ecs, playerID := gameData.ECS(), gameData.GetPlayerID()
 	if !ecs.HasStatsSafe(playerID) {
 		return
 	}
-	stats := ecs.GetStatsSafe(playerID)
+	base := ecs.GetStatsSafe(playerID)
+	stats := gameData.EffectiveStats()
 	titleText := "=== ATTRIBUTES ==="
 	titleColor := ColorUITitle
 	*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
 		cs.drawText(grid, titleText, drawX, drawY, titleColor)
 	})
 
-	line1Text := fmt.Sprintf("Strength:     %2d    Dexterity:    %2d", stats.Strength, stats.Dexterity)
+	line1Text := fmt.Sprintf("Strength:     %-9s Dexterity:    %s", withBonus(stats.Strength, base.Strength, ""), withBonus(stats.Dexterity, base.Dexterity, ""))
 	textColor := ColorUIText
 	*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
 		cs.drawText(grid, line1Text, drawX, drawY, textColor)
 	})
 
-	line2Text := fmt.Sprintf("Constitution: %2d    Intelligence: %2d", stats.Constitution, stats.Intelligence)
+	line2Text := fmt.Sprintf("Constitution: %-9s Intelligence: %s", withBonus(stats.Constitution, base.Constitution, ""), withBonus(stats.Intelligence, base.Intelligence, ""))
 	*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
 		cs.drawText(grid, line2Text, drawX, drawY, textColor)
 	})
 
-	line3Text := fmt.Sprintf("Wisdom:       %2d    Charisma:     %2d", stats.Wisdom, stats.Charisma)
+	line3Text := fmt.Sprintf("Wisdom:       %-9s Charisma:     %s", withBonus(stats.Wisdom, base.Wisdom, ""), withBonus(stats.Charisma, base.Charisma, ""))
 	*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
 		cs.drawText(grid, line3Text, drawX, drawY, textColor)
 	})
 }
 
-// appendCombatStatsElements appends drawing functions for combat stats
-func (cs *CharacterScreen) appendCombatStatsElements(elements *[]DrawableElement, ecs *ecs.ECS, playerID ecs.EntityID, contentWidth int) {
+// appendCombatStatsElements appends drawing functions for combat stats.
+// Values include attributes, equipment and status effects, with the
+// difference to the base value in parentheses.
+func (cs *CharacterScreen) appendCombatStatsElements(elements *[]DrawableElement, gameData GameData, contentWidth int) {
+	ecs, playerID := gameData.ECS(), gameData.GetPlayerID()
 	if !ecs.HasCombatSafe(playerID) {
 		return
 	}
-	combat := ecs.GetCombatSafe(playerID)
+	base := ecs.GetCombatSafe(playerID)
+	combat := gameData.EffectiveCombat()
 	titleText := "=== COMBAT STATS ==="
 	titleColor := ColorUITitle
 	*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
 		cs.drawText(grid, titleText, drawX, drawY, titleColor)
 	})
 
-	line1Text := fmt.Sprintf("Attack Power: %2d    Defense:      %2d", combat.AttackPower, combat.Defense)
+	line1Text := fmt.Sprintf("Attack Power: %-9s Defense:      %s", withBonus(combat.AttackPower, base.AttackPower, ""), withBonus(combat.Defense, base.Defense, ""))
 	textColor := ColorUIText
 	*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
 		cs.drawText(grid, line1Text, drawX, drawY, textColor)
 	})
 
-	line2Text := fmt.Sprintf("Accuracy:     %2d%%   Dodge Chance: %2d%%", combat.Accuracy, combat.DodgeChance)
+	line2Text := fmt.Sprintf("Accuracy:     %-9s Dodge Chance: %s", withBonus(combat.Accuracy, base.Accuracy, "%"), withBonus(combat.DodgeChance, base.DodgeChance, "%"))
 	*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
 		cs.drawText(grid, line2Text, drawX, drawY, textColor)
 	})
 
-	line3Text := fmt.Sprintf("Critical:     %2d%%   Crit Damage:  %2d%%", combat.CriticalChance, combat.CriticalDamage)
+	line3Text := fmt.Sprintf("Critical:     %-9s Crit Damage:  %s", withBonus(combat.CriticalChance, base.CriticalChance, "%"), withBonus(combat.CriticalDamage, base.CriticalDamage, "%"))
 	*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
 		cs.drawText(grid, line3Text, drawX, drawY, textColor)
 	})
+
+	speed, baseSpeed := gameData.PlayerSpeed()
+	carried, limit := gameData.PlayerBurden()
+	line4Text := fmt.Sprintf("Speed:        %-9s Burden:       %d / %d", withBonus(speed, baseSpeed, "%"), carried, limit)
+	*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
+		cs.drawText(grid, line4Text, drawX, drawY, textColor)
+	})
 }
 
 // appendEquipmentElements appends drawing functions for equipment
@@ -262,56 +298,56 @@ func (cs *CharacterScreen) appendEquipmentElements(elements *[]DrawableElement,
 	textColor := ColorUIText
 	highlightColor := ColorUIHighlight
 
-	// Weapon
-	if equipment.Weapon != nil {
-		weaponName := fmt.Sprintf("Weapon: %s", equipment.Weapon.Name)
-		*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
-			cs.drawText(grid, weaponName, drawX, drawY, textColor)
-		})
-		weaponDesc := fmt.Sprintf("  %s", equipment.Weapon.Description)
-		*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
-			cs.drawText(grid, weaponDesc, drawX, drawY, highlightColor)
-		})
-	} else {
-		weaponNone := "Weapon: None"
+	for _, slot := range equipment.Slots() {
+		if slot.Item == nil {
+			noneText := fmt.Sprintf("%-11s None", slot.Slot+":")
+			if slot.Slot == "Offhand" && equipment.Weapon != nil && equipment.Weapon.TwoHanded {
+				noneText = fmt.Sprintf("%-11s (two-handed weapon)", slot.Slot+":")
+			}
+			*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
+				cs.drawText(grid, noneText, drawX, drawY, textColor)
+			})
+			continue
+		}
+
+		nameText := fmt.Sprintf("%-11s %s", slot.Slot+":", slot.Item.Name)
 		*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
-			cs.drawText(grid, weaponNone, drawX, drawY, textColor)
+			cs.drawText(grid, nameText, drawX, drawY, textColor)
 		})
+		if bonuses := itemBonusText(*slot.Item); bonuses != "" {
+			bonusText := fmt.Sprintf("  %s", bonuses)
+			*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
+				cs.drawText(grid, bonusText, drawX, drawY, highlightColor)
+			})
+		}
 	}
+}
 
-	// Armor
-	if equipment.Armor != nil {
-		armorName := fmt.Sprintf("Armor:  %s", equipment.Armor.Name)
-		*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
-			cs.drawText(grid, armorName, drawX, drawY, textColor)
-		})
-		armorDesc := fmt.Sprintf("  %s", equipment.Armor.Description)
-		*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
-			cs.drawText(grid, armorDesc, drawX, drawY, highlightColor)
-		})
-	} else {
-		armorNone := "Armor:  None"
-		*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
-			cs.drawText(grid, armorNone, drawX, drawY, textColor)
-		})
+// itemBonusText lists the non-zero bonuses of an item, e.g. "+3 Atk, +1 Str"
+func itemBonusText(item components.Item) string {
+	bonuses := []struct {
+		value int
+		label string
+	}{
+		{item.AttackBonus, "Atk"},
+		{item.DefenseBonus, "Def"},
+		{item.AccuracyBonus, "Acc"},
+		{item.DodgeBonus, "Dodge"},
+		{item.StatBonus.Strength, "Str"},
+		{item.StatBonus.Dexterity, "Dex"},
+		{item.StatBonus.Constitution, "Con"},
+		{item.StatBonus.Intelligence, "Int"},
+		{item.StatBonus.Wisdom, "Wis"},
+		{item.StatBonus.Charisma, "Cha"},
 	}
 
-	// Accessory
-	if equipment.Accessory != nil {
-		accessoryName := fmt.Sprintf("Accessory: %s", equipment.Accessory.Name)
-		*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
-			cs.drawText(grid, accessoryName, drawX, drawY, textColor)
-		})
-		accessoryDesc := fmt.Sprintf("  %s", equipment.Accessory.Description)
-		*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
-			cs.drawText(grid, accessoryDesc, drawX, drawY, highlightColor)
-		})
-	} else {
-		accessoryNone := "Accessory: None"
-		*elements = append(*elements, func(cs *CharacterScreen, grid gruid.Grid, drawX, drawY int) {
-			cs.drawText(grid, accessoryNone, drawX, drawY, textColor)
-		})
+	var parts []string
+	for _, b := range bonuses {
+		if b.value != 0 {
+			parts = append(parts, fmt.Sprintf("%+d %s", b.value, b.label))
+		}
 	}
+	return strings.Join(parts, ", ")
 }
 
 // appendSkillsElements appends drawing functions for skills
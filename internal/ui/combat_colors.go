@@ -15,6 +15,10 @@ var (
 	ColorDeath    gruid.Color // For death messages
 	ColorCorpse   gruid.Color // For corpse messages
 	ColorCritical gruid.Color // For critical messages
+
+	// Targeting colors
+	ColorTargetPath   gruid.Color // Background of the projectile path
+	ColorTargetCursor gruid.Color // Background of the targeting cursor
 )
 
 func init() {
@@ -26,4 +30,7 @@ func init() {
 	ColorDeath = ColorRed                  // Death messages are red
 	ColorCorpse = ColorForegroundSecondary // Corpse messages are corpse color
 	ColorCritical = ColorRed               // Critical messages are bright white
+
+	ColorTargetPath = ColorBackgroundSecondary // Subtle trail along the line of fire
+	ColorTargetCursor = ColorYellow            // Cursor stands out from the path
 }
@@ -231,6 +231,12 @@ func (is *InventoryScreen) getItemTypeString(itemType components.ItemType) strin
 		return "Consumable"
 	case components.ItemTypeMisc:
 		return "Miscellaneous"
+	case components.ItemTypeAmmo:
+		return "Ammunition"
+	case components.ItemTypeAccessory:
+		return "Accessory"
+	case components.ItemTypeKey:
+		return "Key"
 	default:
 		return "Unknown"
 	}
@@ -6,6 +6,7 @@ import (
 	"codeberg.org/anaseto/gruid"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/config"
 	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs"
+	"github.com/lecoqjacob/ai-go/roguelike-gruid-project/internal/ecs/components"
 )
 
 // GameData interface to avoid import cycles
@@ -13,6 +14,11 @@ type GameData interface {
 	ECS() *ecs.ECS
 	GetPlayerID() ecs.EntityID
 	GetDepth() int
+	GetSeed() int64
+	EffectiveStats() components.Stats   // Player attributes with equipment and effects applied
+	EffectiveCombat() components.Combat // Player combat values with everything applied
+	PlayerSpeed() (current, base int)   // Player speed in percent of normal
+	PlayerBurden() (carried, limit int) // Weight the player carries and can carry unhindered
 	Stats() GameStats
 }
 
@@ -71,6 +77,9 @@ func (sp *StatsPanel) Render(grid gruid.Grid, gameData GameData) {
 		currentY = sp.drawEquipment(grid, gameData.ECS(), playerID, contentX, currentY, contentWidth)
 	}
 
+	// Effective combat values
+	currentY = sp.drawCombat(grid, gameData.EffectiveCombat(), contentX, currentY)
+
 	// Game stats
 	currentY = sp.drawGameStats(grid, gameData, contentX, currentY, contentWidth)
 }
@@ -155,6 +164,16 @@ func (sp *StatsPanel) drawEquipment(grid gruid.Grid, ecs *ecs.ECS, playerID ecs.
 	return y
 }
 
+// drawCombat renders the player's effective combat values
+func (sp *StatsPanel) drawCombat(grid gruid.Grid, combat components.Combat, x, y int) int {
+	sp.drawLine(grid, fmt.Sprintf("Atk: %d  Def: %d", combat.AttackPower, combat.Defense), x, y, ColorUIText)
+	y++
+	sp.drawLine(grid, fmt.Sprintf("Acc: %d%% Dge: %d%%", combat.Accuracy, combat.DodgeChance), x, y, ColorUIText)
+	y++
+
+	return y
+}
+
 // drawGameStats renders game statistics
 func (sp *StatsPanel) drawGameStats(grid gruid.Grid, gameData GameData, x, y, width int) int {
 	// Add spacing